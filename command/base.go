@@ -1,25 +1,50 @@
 package command
 
 import (
+	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"xckit/config"
 	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
 )
 
 type XCStringsCommand struct {
-	filePath string
+	// filePath is the catalog being operated on: the -f file, or in
+	// multi-catalog mode, the current one of forEachCatalog.
+	filePath  string
+	filePaths stringsFlag
+	recursive bool
+	config    *config.Config
+	// catalogDocuments collects the printJSON documents of a multi-catalog
+	// run, to print as one document; nil otherwise.
+	catalogDocuments *[]catalogDocument
+}
+
+// setConfig gives the command the project configuration; see Configured.
+func (c *XCStringsCommand) setConfig(cfg *config.Config) {
+	c.config = cfg
 }
 
 func (c *XCStringsCommand) SetXCStringsFlags(f *flag.FlagSet) {
-	f.StringVar(&c.filePath, "f", "", "Path to the .xcstrings file")
-	f.StringVar(&c.filePath, "file", "", "Path to the .xcstrings file")
+	f.Var(&c.filePaths, "f", "Path to an .xcstrings file or a directory of them (repeatable)")
+	f.Var(&c.filePaths, "file", "Path to an .xcstrings file or a directory of them (repeatable)")
+	f.BoolVar(&c.recursive, "recursive", false, "Search -f directories (default: the current directory) recursively for .xcstrings files")
 }
 
 func (c *XCStringsCommand) LoadXCStrings() (*xcstrings.XCStrings, error) {
 	path := c.filePath
+	if path == "" && len(c.filePaths) > 0 {
+		path = c.filePaths[0]
+	}
 	if path == "" {
 		path = c.findXCStringsFile()
 		if path == "" {
@@ -47,3 +72,211 @@ func (c *XCStringsCommand) findXCStringsFile() string {
 	}
 	return ""
 }
+
+// catalogPaths returns the catalogs named by -f, or by the configuration's
+// catalogs when there is no -f, in order and without duplicates, with each
+// directory replaced by the .xcstrings files in it (or below it, with
+// --recursive). It returns nil when neither -f, --recursive nor configured
+// catalogs are given, leaving LoadXCStrings to find the catalog in the
+// current directory.
+func (c *XCStringsCommand) catalogPaths() ([]string, error) {
+	roots := []string(c.filePaths)
+	recursive := c.recursive
+	if len(roots) == 0 && c.config != nil && len(c.config.Catalogs) > 0 {
+		roots = c.config.CatalogPaths()
+		recursive = recursive || c.config.Recursive
+	}
+	if len(roots) == 0 {
+		if !recursive {
+			return nil, nil
+		}
+		roots = []string{"."}
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("file not found: %s", root)
+		}
+		if !info.IsDir() {
+			add(root)
+			continue
+		}
+		found, err := findCatalogs(root, recursive)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no .xcstrings file found in %s", root)
+		}
+		for _, path := range found {
+			add(path)
+		}
+	}
+	return paths, nil
+}
+
+// findCatalogs returns the .xcstrings files in dir, sorted, descending into
+// subdirectories when recursive is set. Hidden directories (.git, .build,
+// ...) are skipped.
+func findCatalogs(dir string, recursive bool) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (!recursive || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".xcstrings") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// targetLanguages returns the languages xcs should be translated into: the
+// configured languages (other than the source language) when there are any,
+// otherwise the languages present in the catalog, sorted.
+func (c *XCStringsCommand) targetLanguages(xcs *xcstrings.XCStrings) []string {
+	var languages []string
+	if c.config != nil && len(c.config.Languages) > 0 {
+		for _, lang := range c.config.Languages {
+			if lang != xcs.SourceLanguage {
+				languages = append(languages, lang)
+			}
+		}
+		return languages
+	}
+	languages = xcs.Languages()
+	sort.Strings(languages)
+	return languages
+}
+
+// forEachCatalog runs fn once per catalog with filePath set to it. With
+// several catalogs, each run's output is preceded by a "== path ==" header
+// unless quiet is set (for JSON output), and the worst exit status wins. The
+// documents the runs pass to printJSON are then printed as one,
+// {"catalogs": [{"file": ..., ...}]}.
+func (c *XCStringsCommand) forEachCatalog(quiet bool, fn func() subcommands.ExitStatus) subcommands.ExitStatus {
+	paths, err := c.catalogPaths()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	if len(paths) <= 1 {
+		if len(paths) == 1 {
+			c.filePath = paths[0]
+		}
+		return fn()
+	}
+
+	var documents []catalogDocument
+	c.catalogDocuments = &documents
+	defer func() { c.catalogDocuments = nil }()
+
+	status := subcommands.ExitSuccess
+	for i, path := range paths {
+		c.filePath = path
+		if !quiet {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("== %s ==\n", path)
+		}
+		status = max(status, fn())
+	}
+	if len(documents) > 0 {
+		c.catalogDocuments = nil
+		status = max(status, c.printJSON(struct {
+			Catalogs []catalogDocument `json:"catalogs"`
+		}{documents}))
+	}
+	return status
+}
+
+// printJSON writes out to stdout as an indented JSON document, or in a
+// multi-catalog run, keeps it for forEachCatalog to print.
+func (c *XCStringsCommand) printJSON(out any) subcommands.ExitStatus {
+	if c.catalogDocuments != nil {
+		*c.catalogDocuments = append(*c.catalogDocuments, catalogDocument{File: c.filePath, Document: out})
+		return subcommands.ExitSuccess
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Println(string(data))
+	return subcommands.ExitSuccess
+}
+
+// catalogDocument is a catalog's entry in a multi-catalog JSON document:
+// the catalog's own document, preceded by a "file" field.
+type catalogDocument struct {
+	File     string
+	Document any
+}
+
+func (d catalogDocument) MarshalJSON() ([]byte, error) {
+	file, err := json.Marshal(d.File)
+	if err != nil {
+		return nil, err
+	}
+	document, err := json.Marshal(d.Document)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(document, []byte("{")) {
+		return nil, fmt.Errorf("a catalog's JSON document must be an object, got %s", document)
+	}
+	out := append([]byte(`{"file":`), file...)
+	if rest := document[1:]; !bytes.Equal(rest, []byte("}")) {
+		out = append(out, ',')
+		out = append(out, rest...)
+	} else {
+		out = append(out, '}')
+	}
+	return out, nil
+}
+
+// singleCatalog resolves -f for commands that operate on exactly one
+// catalog, failing when several are named.
+func (c *XCStringsCommand) singleCatalog() error {
+	paths, err := c.catalogPaths()
+	if err != nil {
+		return err
+	}
+	if len(paths) > 1 {
+		return fmt.Errorf("this command operates on a single catalog, but %d were given", len(paths))
+	}
+	if len(paths) == 1 {
+		c.filePath = paths[0]
+	}
+	return nil
+}
+
+// stringsFlag is a flag.Value collecting every occurrence of a repeatable
+// flag.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
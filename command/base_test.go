@@ -0,0 +1,197 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"xckit/helper/test"
+
+	"github.com/google/subcommands"
+)
+
+const localizableFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"hello": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Hello"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}}
+		}},
+		"bye": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Bye"}}
+		}}
+	},
+	"version": "1.0"
+}`
+
+const infoPlistFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"CFBundleDisplayName": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Notes"}},
+			"fr": {"stringUnit": {"state": "translated", "value": "Notes"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "メモ"}}
+		}}
+	},
+	"version": "1.0"
+}`
+
+// writeProject creates files (relative path -> content) under a temporary
+// directory and returns the directory.
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		test.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		test.AssertNoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+// runCommand parses args into cmd's flags and executes it, returning its
+// standard output and exit status.
+func runCommand(t *testing.T, cmd subcommands.Command, args ...string) (string, int) {
+	t.Helper()
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse(args))
+
+	var status int
+	output := captureOutput(func() {
+		status = int(cmd.Execute(context.Background(), flagSet))
+	})
+	return output, status
+}
+
+func parseXCStringsFlags(t *testing.T, args ...string) *XCStringsCommand {
+	t.Helper()
+	c := &XCStringsCommand{}
+	f := flag.NewFlagSet("test", flag.ContinueOnError)
+	c.SetXCStringsFlags(f)
+	test.AssertNoError(t, f.Parse(args))
+	return c
+}
+
+func TestCatalogPaths(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"App/Localizable.xcstrings":              localizableFixture,
+		"App/InfoPlist.xcstrings":                infoPlistFixture,
+		"Packages/Core/Core.xcstrings":           localizableFixture,
+		".build/checkouts/Dep/Dep.xcstrings":     localizableFixture,
+		"Packages/Core/Resources/notes.markdown": "not a catalog",
+	})
+
+	paths, err := parseXCStringsFlags(t).catalogPaths()
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, len(paths), 0)
+
+	// A directory lists the catalogs directly inside it.
+	paths, err = parseXCStringsFlags(t, "-f", filepath.Join(dir, "App")).catalogPaths()
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, paths, []string{
+		filepath.Join(dir, "App/InfoPlist.xcstrings"),
+		filepath.Join(dir, "App/Localizable.xcstrings"),
+	})
+
+	// --recursive descends, skipping hidden directories.
+	paths, err = parseXCStringsFlags(t, "--recursive", "-f", dir).catalogPaths()
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, paths, []string{
+		filepath.Join(dir, "App/InfoPlist.xcstrings"),
+		filepath.Join(dir, "App/Localizable.xcstrings"),
+		filepath.Join(dir, "Packages/Core/Core.xcstrings"),
+	})
+
+	// Repeated -f keeps its order and drops duplicates.
+	core := filepath.Join(dir, "Packages/Core/Core.xcstrings")
+	paths, err = parseXCStringsFlags(t, "-f", core, "--file", filepath.Join(dir, "App"), "-f", core).catalogPaths()
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, paths, []string{
+		core,
+		filepath.Join(dir, "App/InfoPlist.xcstrings"),
+		filepath.Join(dir, "App/Localizable.xcstrings"),
+	})
+
+	_, err = parseXCStringsFlags(t, "-f", filepath.Join(dir, "Packages")).catalogPaths()
+	test.AssertError(t, err)
+	_, err = parseXCStringsFlags(t, "-f", filepath.Join(dir, "missing.xcstrings")).catalogPaths()
+	test.AssertError(t, err)
+}
+
+func TestCatalogPaths_RecursiveDefaultsToWorkingDirectory(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"Sub/Localizable.xcstrings": localizableFixture,
+	})
+	t.Chdir(dir)
+
+	paths, err := parseXCStringsFlags(t, "--recursive").catalogPaths()
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, paths, []string{"Sub/Localizable.xcstrings"})
+}
+
+func TestForEachCatalog(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"A.xcstrings": localizableFixture,
+		"B.xcstrings": infoPlistFixture,
+	})
+	c := parseXCStringsFlags(t, "-f", dir)
+
+	var seen []string
+	output := captureOutput(func() {
+		status := c.forEachCatalog(false, func() subcommands.ExitStatus {
+			seen = append(seen, c.filePath)
+			if len(seen) == 1 {
+				return 1
+			}
+			return 0
+		})
+		test.AssertEqual(t, int(status), 1)
+	})
+	test.AssertSliceEqual(t, seen, []string{filepath.Join(dir, "A.xcstrings"), filepath.Join(dir, "B.xcstrings")})
+	test.AssertEqual(t, output, "== "+seen[0]+" ==\n\n== "+seen[1]+" ==\n")
+}
+
+func TestForEachCatalog_JSON(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"A.xcstrings": localizableFixture,
+		"B.xcstrings": infoPlistFixture,
+	})
+	cmd := &ListCommand{}
+	f := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd.SetFlags(f)
+	test.AssertNoError(t, f.Parse([]string{"-f", dir, "--json"}))
+
+	output := captureOutput(func() {
+		test.AssertEqual(t, int(cmd.Execute(context.Background(), f)), 0)
+	})
+	var doc struct {
+		Catalogs []struct {
+			File string             `json:"file"`
+			Keys []listJSONKeyEntry `json:"keys"`
+		} `json:"catalogs"`
+	}
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("output should be a single JSON document, got error %v, output: %q", err, output)
+	}
+	test.AssertEqual(t, len(doc.Catalogs), 2)
+	test.AssertEqual(t, doc.Catalogs[0].File, filepath.Join(dir, "A.xcstrings"))
+	test.AssertEqual(t, len(doc.Catalogs[0].Keys), 2)
+	test.AssertEqual(t, doc.Catalogs[1].File, filepath.Join(dir, "B.xcstrings"))
+	test.AssertEqual(t, doc.Catalogs[1].Keys[0].Key, "CFBundleDisplayName")
+}
+
+func TestSetCommand_RejectsSeveralCatalogs(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"A.xcstrings": localizableFixture,
+		"B.xcstrings": infoPlistFixture,
+	})
+	cmd := &SetCommand{}
+	f := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd.SetFlags(f)
+	test.AssertNoError(t, f.Parse([]string{"-f", dir, "--lang", "ja", "bye", "さようなら"}))
+	test.AssertEqual(t, int(cmd.Execute(context.Background(), f)), 1)
+}
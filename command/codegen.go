@@ -0,0 +1,383 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"xckit/helper/atomicwrite"
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+type CodegenCommand struct {
+	XCStringsCommand
+	output string
+	name   string
+	access string
+	bundle string
+}
+
+func (*CodegenCommand) Name() string {
+	return "codegen"
+}
+
+func (*CodegenCommand) Synopsis() string {
+	return "Generate type-safe Swift accessors for the catalog's keys"
+}
+
+func (*CodegenCommand) Usage() string {
+	return "codegen [-f file.xcstrings] [-o Strings.swift] [--name L10n] [--access internal|public] [--bundle main|module]: Generate a Swift enum with an accessor for every active key: a LocalizedStringResource property, or a String function taking the format arguments as typed parameters; keys are grouped by their dot-separated prefixes into nested enums\n"
+}
+
+func (c *CodegenCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.StringVar(&c.output, "o", "", "Output file path (default: stdout); left untouched when already up to date")
+	f.StringVar(&c.name, "name", "L10n", "Name of the generated top-level enum")
+	f.StringVar(&c.access, "access", "internal", "Access level of the generated declarations (internal or public)")
+	f.StringVar(&c.bundle, "bundle", "main", "Bundle holding the strings: main, or module for a Swift package target")
+}
+
+// codegenEnum is a generated enum: a key prefix.
+type codegenEnum struct {
+	name    string
+	enums   map[string]*codegenEnum
+	members map[string]codegenMember
+}
+
+// codegenMember is the accessor generated for one key.
+type codegenMember struct {
+	key     string
+	comment string
+	// params are the Swift types of the format arguments, by position.
+	params []string
+}
+
+func (c *CodegenCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if (c.access != "internal" && c.access != "public") || (c.bundle != "main" && c.bundle != "module") || !isSwiftIdentifier(c.name) || f.NArg() > 0 {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --access must be internal or public, --bundle main or module, and --name a Swift identifier\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+	if err := c.singleCatalog(); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	xcs, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	filePath := c.filePath
+	if filePath == "" {
+		filePath = c.findXCStringsFile()
+	}
+
+	root := &codegenEnum{name: c.name}
+	keys := xcs.ActiveKeys()
+	sort.Strings(keys)
+	count := 0
+	for _, key := range keys {
+		definition := xcs.Strings[key]
+		params, err := codegenParams(key, definition, xcs.SourceLanguage)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Warning: %s: %v; no accessor generated\n", key, err)
+			continue
+		}
+		root.add(codegenPath(key), codegenMember{key: key, comment: definition.Comment, params: params})
+		count++
+	}
+
+	source := c.render(root, catalogTableName(filePath))
+	if c.output == "" {
+		fmt.Print(source)
+		return subcommands.ExitSuccess
+	}
+	if existing, err := os.ReadFile(c.output); err == nil && bytes.Equal(existing, []byte(source)) {
+		// Leaving the file alone spares Xcode a rebuild.
+		fmt.Printf("%s is up to date\n", c.output)
+		return subcommands.ExitSuccess
+	}
+	if err := atomicwrite.WriteFile(c.output, []byte(source), 0644); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Printf("Generated %d accessors in %s\n", count, c.output)
+	return subcommands.ExitSuccess
+}
+
+// codegenSegmentRe matches a key segment that reads as a name rather than
+// as text, so that "settings.title" is grouped but "Hello. Welcome!" isn't.
+var codegenSegmentRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// codegenPath splits key into the names of its enums and its accessor.
+func codegenPath(key string) []string {
+	segments := strings.Split(key, ".")
+	for _, segment := range segments {
+		if !codegenSegmentRe.MatchString(segment) {
+			return []string{swiftIdentifier(key, false)}
+		}
+	}
+	path := make([]string, len(segments))
+	for i, segment := range segments {
+		path[i] = swiftIdentifier(segment, i < len(segments)-1)
+	}
+	return path
+}
+
+// add places member at path, numbering accessor names that two keys share
+// (title, title2, ...).
+func (e *codegenEnum) add(path []string, member codegenMember) {
+	for _, name := range path[:len(path)-1] {
+		child, ok := e.enums[name]
+		if !ok {
+			if e.enums == nil {
+				e.enums = make(map[string]*codegenEnum)
+			}
+			child = &codegenEnum{name: name}
+			e.enums[name] = child
+		}
+		e = child
+	}
+	if e.members == nil {
+		e.members = make(map[string]codegenMember)
+	}
+	name := path[len(path)-1]
+	base := strings.Trim(name, "`")
+	for n := 2; ; n++ {
+		if _, taken := e.members[name]; !taken {
+			break
+		}
+		name = base + strconv.Itoa(n)
+	}
+	e.members[name] = member
+}
+
+// swiftFormatTypes maps a printf conversion to the Swift type of its
+// argument.
+var swiftFormatTypes = map[string]string{
+	"@": "String",
+	"d": "Int", "i": "Int",
+	"o": "UInt", "u": "UInt", "x": "UInt", "X": "UInt",
+	"f": "Double", "e": "Double", "E": "Double", "g": "Double", "G": "Double", "a": "Double", "A": "Double",
+	"c": "CChar",
+	"s": "UnsafePointer<CChar>",
+	"p": "UnsafeRawPointer",
+}
+
+// codegenParams returns the Swift types of a key's format arguments, by
+// position, read from every source-language string (the key itself when
+// there is none), with a substitution's argNum and formatSpecifier standing
+// for its %#@name@ reference.
+func codegenParams(key string, definition xcstrings.StringDefinition, sourceLanguage string) ([]string, error) {
+	source, ok := definition.Localizations[sourceLanguage]
+	host := xcstrings.Localization{StringUnit: source.StringUnit, Variations: source.Variations}
+	var texts []string
+	for _, unit := range host.AllStringUnits() {
+		texts = append(texts, unit.Value)
+	}
+	if !ok || len(texts) == 0 {
+		texts = []string{key}
+	}
+
+	types := make(map[int]string)
+	last := 0
+	for _, text := range texts {
+		for _, token := range extractFormatTokens(text) {
+			position, conversion := token.position, token.kind
+			switch {
+			case token.kind == "arg":
+				continue
+			case strings.HasPrefix(token.kind, "sub:"):
+				name := strings.TrimPrefix(token.kind, "sub:")
+				sub, ok := source.Substitutions[name]
+				if !ok || sub.FormatSpecifier == "" {
+					return nil, fmt.Errorf("substitution %s is not defined", name)
+				}
+				position, conversion = sub.ArgNum, sub.FormatSpecifier[len(sub.FormatSpecifier)-1:]
+			}
+			swiftType, ok := swiftFormatTypes[conversion]
+			if !ok {
+				return nil, fmt.Errorf("unsupported format specifier %%%s", conversion)
+			}
+			if other, ok := types[position]; ok && other != swiftType {
+				return nil, fmt.Errorf("argument %d is used as both %s and %s", position, other, swiftType)
+			}
+			types[position] = swiftType
+			last = max(last, position)
+		}
+	}
+
+	params := make([]string, last)
+	for position := 1; position <= last; position++ {
+		swiftType, ok := types[position]
+		if !ok {
+			return nil, fmt.Errorf("argument %d is never used", position)
+		}
+		params[position-1] = swiftType
+	}
+	return params, nil
+}
+
+// render returns the Swift source for root.
+func (c *CodegenCommand) render(root *codegenEnum, table string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated by xckit codegen from %s.xcstrings. Do not edit.\n\n", table)
+	b.WriteString("import Foundation\n\n")
+	c.renderEnum(&b, root, "")
+
+	bundle := "Bundle.main"
+	resourceBundle := ".main"
+	if c.bundle == "module" {
+		bundle = "Bundle.module"
+		resourceBundle = ".atURL(Bundle.module.bundleURL)"
+	}
+	// The helpers take the key as a variable, so Xcode doesn't extract this
+	// file's strings into the catalog. swiftIdentifier never starts a name
+	// with an underscore and a letter, so no accessor can shadow them.
+	fmt.Fprintf(&b, `
+extension %[1]s {
+    private static let _xckitTable = %[2]s
+
+    fileprivate static func _xckitResource(_ key: String) -> LocalizedStringResource {
+        LocalizedStringResource(String.LocalizationValue(key), table: _xckitTable, bundle: %[3]s)
+    }
+
+    fileprivate static func _xckitString(_ key: String, _ arguments: CVarArg...) -> String {
+        let format = %[4]s.localizedString(forKey: key, value: nil, table: _xckitTable)
+        return String(format: format, locale: Locale.current, arguments: arguments)
+    }
+}
+`, c.name, swiftStringLiteral(table), resourceBundle, bundle)
+	return b.String()
+}
+
+func (c *CodegenCommand) renderEnum(b *strings.Builder, e *codegenEnum, indent string) {
+	fmt.Fprintf(b, "%s%s enum %s {\n", indent, c.access, e.name)
+	inner := indent + "    "
+
+	names := sortedKeys(e.members)
+	sort.SliceStable(names, func(i, j int) bool { return strings.Trim(names[i], "`") < strings.Trim(names[j], "`") })
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		member := e.members[name]
+		if member.comment != "" {
+			for _, line := range strings.Split(member.comment, "\n") {
+				fmt.Fprintf(b, "%s%s\n", inner, strings.TrimRight("/// "+line, " \t\r"))
+			}
+		}
+		key := swiftStringLiteral(member.key)
+		if len(member.params) == 0 {
+			fmt.Fprintf(b, "%s%s static var %s: LocalizedStringResource { %s._xckitResource(%s) }\n", inner, c.access, name, c.name, key)
+			continue
+		}
+		params := make([]string, len(member.params))
+		args := make([]string, len(member.params))
+		for i, swiftType := range member.params {
+			args[i] = fmt.Sprintf("arg%d", i+1)
+			params[i] = fmt.Sprintf("_ %s: %s", args[i], swiftType)
+		}
+		fmt.Fprintf(b, "%s%s static func %s(%s) -> String {\n%s    %s._xckitString(%s, %s)\n%s}\n",
+			inner, c.access, name, strings.Join(params, ", "), inner, c.name, key, strings.Join(args, ", "), inner)
+	}
+
+	for i, name := range sortedKeys(e.enums) {
+		if i > 0 || len(names) > 0 {
+			b.WriteByte('\n')
+		}
+		c.renderEnum(b, e.enums[name], inner)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// swiftKeywords are the reserved words that need backticks as identifiers.
+var swiftKeywords = map[string]bool{
+	"associatedtype": true, "class": true, "deinit": true, "enum": true, "extension": true, "fileprivate": true,
+	"func": true, "import": true, "init": true, "inout": true, "internal": true, "let": true, "open": true,
+	"operator": true, "private": true, "protocol": true, "public": true, "rethrows": true, "static": true,
+	"struct": true, "subscript": true, "typealias": true, "var": true, "break": true, "case": true,
+	"continue": true, "default": true, "defer": true, "do": true, "else": true, "fallthrough": true,
+	"for": true, "guard": true, "if": true, "in": true, "repeat": true, "return": true, "switch": true,
+	"where": true, "while": true, "as": true, "Any": true, "catch": true, "false": true, "is": true,
+	"nil": true, "super": true, "self": true, "Self": true, "throw": true, "throws": true, "true": true,
+	"try": true, "Type": true, "Protocol": true,
+}
+
+// codegenFormatRe matches the format references left out of identifiers.
+var codegenFormatRe = regexp.MustCompile(lintSubRefRe.String() + `|%%|` + lintArgRe.String() + `|` + lintStdSpecRe.String())
+
+// swiftIdentifier turns s into a camel-case Swift identifier, starting with
+// an upper-case letter when upper is set: "item_count" is itemCount or
+// ItemCount, and "%lld items left" is itemsLeft.
+func swiftIdentifier(s string, upper bool) string {
+	words := strings.FieldsFunc(codegenFormatRe.ReplaceAllString(s, " "), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for i, word := range words {
+		runes := []rune(word)
+		switch {
+		case i == 0 && !upper && strings.ToUpper(word) == word:
+			// An acronym: OK -> ok.
+			b.WriteString(strings.ToLower(word))
+		case i == 0 && !upper:
+			runes[0] = unicode.ToLower(runes[0])
+			b.WriteString(string(runes))
+		default:
+			runes[0] = unicode.ToUpper(runes[0])
+			b.WriteString(string(runes))
+		}
+	}
+	name := b.String()
+	switch {
+	case name == "" && upper:
+		name = "Strings"
+	case name == "":
+		name = "string"
+	case unicode.IsDigit([]rune(name)[0]):
+		name = "_" + name
+	}
+	if swiftKeywords[name] {
+		name = "`" + name + "`"
+	}
+	return name
+}
+
+func isSwiftIdentifier(s string) bool {
+	return s != "" && swiftIdentifier(s, true) == s
+}
+
+// swiftStringLiteral quotes s as a Swift string literal.
+func swiftStringLiteral(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(&b, `\u{%X}`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
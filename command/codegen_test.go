@@ -0,0 +1,189 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+const codegenFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"settings.title": {"comment": "Title of the\nsettings screen", "localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Settings"}}
+		}},
+		"settings.item_count": {"localizations": {
+			"en": {"variations": {"plural": {
+				"one": {"stringUnit": {"state": "translated", "value": "%lld item"}},
+				"other": {"stringUnit": {"state": "translated", "value": "%lld items"}}
+			}}}
+		}},
+		"%@ sent %#@photos@": {"localizations": {
+			"en": {
+				"stringUnit": {"state": "translated", "value": "%1$@ sent %#@photos@"},
+				"substitutions": {"photos": {"argNum": 2, "formatSpecifier": "lld", "variations": {"plural": {
+					"one": {"stringUnit": {"state": "translated", "value": "a photo"}},
+					"other": {"stringUnit": {"state": "translated", "value": "%arg photos"}}
+				}}}}
+			}
+		}},
+		"%.1f%% done": {},
+		"OK": {},
+		"default": {},
+		"Default": {},
+		"Say \"hi\"": {},
+		"mixed %1$d %1$@": {},
+		"old": {"extractionState": "stale"}
+	},
+	"version": "1.0"
+}`
+
+const codegenExpected = `// Generated by xckit codegen from Localizable.xcstrings. Do not edit.
+
+import Foundation
+
+public enum Strings {
+    public static var ` + "`default`" + `: LocalizedStringResource { Strings._xckitResource("Default") }
+
+    public static var default2: LocalizedStringResource { Strings._xckitResource("default") }
+
+    public static func done(_ arg1: Double) -> String {
+        Strings._xckitString("%.1f%% done", arg1)
+    }
+
+    public static var ok: LocalizedStringResource { Strings._xckitResource("OK") }
+
+    public static var sayHi: LocalizedStringResource { Strings._xckitResource("Say \"hi\"") }
+
+    public static func sent(_ arg1: String, _ arg2: Int) -> String {
+        Strings._xckitString("%@ sent %#@photos@", arg1, arg2)
+    }
+
+    public enum Settings {
+        public static func itemCount(_ arg1: Int) -> String {
+            Strings._xckitString("settings.item_count", arg1)
+        }
+
+        /// Title of the
+        /// settings screen
+        public static var title: LocalizedStringResource { Strings._xckitResource("settings.title") }
+    }
+}
+
+extension Strings {
+    private static let _xckitTable = "Localizable"
+
+    fileprivate static func _xckitResource(_ key: String) -> LocalizedStringResource {
+        LocalizedStringResource(String.LocalizationValue(key), table: _xckitTable, bundle: .atURL(Bundle.module.bundleURL))
+    }
+
+    fileprivate static func _xckitString(_ key: String, _ arguments: CVarArg...) -> String {
+        let format = Bundle.module.localizedString(forKey: key, value: nil, table: _xckitTable)
+        return String(format: format, locale: Locale.current, arguments: arguments)
+    }
+}
+`
+
+func TestCodegenCommand(t *testing.T) {
+	catalogPath := test.TempFile(t, "Localizable.xcstrings", codegenFixture)
+
+	var output string
+	var status int
+	stderr := captureStderr(func() {
+		output, status = runCommand(t, &CodegenCommand{}, "-f", catalogPath, "--name", "Strings", "--access", "public", "--bundle", "module")
+	})
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, codegenExpected)
+	test.AssertEqual(t, stderr, "Warning: mixed %1$d %1$@: argument 1 is used as both Int and String; no accessor generated\n")
+}
+
+func TestCodegenCommand_Output(t *testing.T) {
+	catalogPath := test.TempFile(t, "Localizable.xcstrings", codegenFixture)
+	swiftPath := filepath.Join(filepath.Dir(catalogPath), "Strings.swift")
+
+	var output string
+	captureStderr(func() {
+		output, _ = runCommand(t, &CodegenCommand{}, "-f", catalogPath, "-o", swiftPath)
+	})
+	test.AssertEqual(t, output, "Generated 8 accessors in "+swiftPath+"\n")
+	data, err := os.ReadFile(swiftPath)
+	test.AssertNoError(t, err)
+	if !strings.Contains(string(data), "internal enum L10n {\n") || !strings.Contains(string(data), "bundle: .main)") {
+		t.Errorf("unexpected defaults in:\n%s", data)
+	}
+
+	captureStderr(func() {
+		output, _ = runCommand(t, &CodegenCommand{}, "-f", catalogPath, "-o", swiftPath)
+	})
+	test.AssertEqual(t, output, swiftPath+" is up to date\n")
+
+	_, status := runCommand(t, &CodegenCommand{}, "-f", catalogPath, "--access", "private")
+	test.AssertEqual(t, status, 2)
+}
+
+func TestCodegenCommand_HelperNamesAreReserved(t *testing.T) {
+	catalogPath := test.TempFile(t, "Localizable.xcstrings", `{"sourceLanguage": "en", "strings": {
+		"table": {}, "resource": {}, "string": {}, "%lld": {}, "_xckitTable": {}
+	}, "version": "1.0"}`)
+
+	output, status := runCommand(t, &CodegenCommand{}, "-f", catalogPath)
+	test.AssertEqual(t, status, 0)
+	for _, want := range []string{
+		`static var resource: LocalizedStringResource { L10n._xckitResource("resource") }`,
+		`static func string(_ arg1: Int) -> String {`,
+		`static var string2: LocalizedStringResource { L10n._xckitResource("string") }`,
+		`static var table: LocalizedStringResource { L10n._xckitResource("table") }`,
+		`static var xckitTable: LocalizedStringResource { L10n._xckitResource("_xckitTable") }`,
+		"private static let _xckitTable = \"Localizable\"\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestCodegenParams(t *testing.T) {
+	catalogPath := test.TempFile(t, "Localizable.xcstrings", `{"sourceLanguage": "en", "strings": {
+		"gap": {"localizations": {"en": {"stringUnit": {"state": "translated", "value": "%2$@"}}}},
+		"reordered": {"localizations": {"en": {"stringUnit": {"state": "translated", "value": "%2$@ %1$u %3$c"}}}},
+		"undefined": {"localizations": {"en": {"stringUnit": {"state": "translated", "value": "%#@missing@"}}}}
+	}, "version": "1.0"}`)
+	xcs, err := xcstrings.Load(catalogPath)
+	test.AssertNoError(t, err)
+
+	params, err := codegenParams("reordered", xcs.Strings["reordered"], "en")
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, params, []string{"UInt", "String", "CChar"})
+
+	_, err = codegenParams("gap", xcs.Strings["gap"], "en")
+	test.AssertError(t, err)
+	_, err = codegenParams("undefined", xcs.Strings["undefined"], "en")
+	test.AssertError(t, err)
+}
+
+func TestSwiftIdentifier(t *testing.T) {
+	for _, tc := range []struct {
+		in    string
+		upper bool
+		want  string
+	}{
+		{"item_count", false, "itemCount"},
+		{"item_count", true, "ItemCount"},
+		{"itemCount", false, "itemCount"},
+		{"URL", false, "url"},
+		{"%lld items left", false, "itemsLeft"},
+		{"2fa-code", false, "_2faCode"},
+		{"Ünïcode wörds", false, "ünïcodeWörds"},
+		{"%@", false, "string"},
+		{"%@", true, "Strings"},
+		{"return", false, "`return`"},
+	} {
+		test.AssertEqual(t, swiftIdentifier(tc.in, tc.upper), tc.want)
+	}
+	test.AssertSliceEqual(t, codegenPath("home.header.title"), []string{"Home", "Header", "title"})
+	test.AssertSliceEqual(t, codegenPath("Hello. Welcome!"), []string{"helloWelcome"})
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"xckit/config"
+
+	"github.com/google/subcommands"
+)
+
+// configurable is implemented by every command embedding XCStringsCommand.
+type configurable interface {
+	setConfig(cfg *config.Config)
+}
+
+type configuredCommand struct {
+	subcommands.Command
+}
+
+// Configured wraps cmd so that it runs with the project configuration file
+// found from the working directory upward, if any: the configured flag
+// defaults for the command are applied first, then the configuration is
+// handed to the command itself.
+func Configured(cmd subcommands.Command) subcommands.Command {
+	return &configuredCommand{Command: cmd}
+}
+
+func (c *configuredCommand) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
+	cfg, err := config.Find(".")
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	if cfg != nil {
+		if err := cfg.ApplyDefaults(c.Name(), f); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitUsageError
+		}
+		if cc, ok := c.Command.(configurable); ok {
+			cc.setConfig(cfg)
+		}
+	}
+	return c.Command.Execute(ctx, f, args...)
+}
@@ -0,0 +1,143 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+
+	"github.com/google/subcommands"
+)
+
+// runConfigured runs cmd through Configured from dir, as main does.
+func runConfigured(t *testing.T, dir string, cmd subcommands.Command, args ...string) (string, int) {
+	t.Helper()
+	t.Chdir(dir)
+	return runCommand(t, Configured(cmd), args...)
+}
+
+func TestConfigured_CatalogsAndLanguages(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		".xckit.yaml": `catalogs:
+  - App/Localizable.xcstrings
+languages: [en, ja, fr]
+`,
+		"App/Localizable.xcstrings": localizableFixture,
+	})
+
+	// fr is reported although no key has started on it.
+	output, status := runConfigured(t, dir, &StatusCommand{})
+	test.AssertEqual(t, status, 0)
+	for _, want := range []string{"Total Keys: 2", "fr", "ja"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("status output should contain %q, got: %s", want, output)
+		}
+	}
+
+	output, status = runConfigured(t, dir, &UntranslatedCommand{})
+	test.AssertEqual(t, status, 0)
+	if !strings.Contains(output, "hello") || !strings.Contains(output, "bye") {
+		t.Errorf("both keys lack fr, got: %s", output)
+	}
+
+	// -f replaces the configured catalogs.
+	_, status = runConfigured(t, dir, &ListCommand{}, "-f", "missing.xcstrings")
+	test.AssertEqual(t, status, 1)
+}
+
+func TestConfigured_CommandDefaults(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		".xckit.yaml": `commands:
+  untranslated:
+    lang: ja
+`,
+		"Localizable.xcstrings": localizableFixture,
+	})
+
+	output, status := runConfigured(t, dir, &UntranslatedCommand{})
+	test.AssertEqual(t, status, 0)
+	if !strings.Contains(output, "bye") || strings.Contains(output, "hello") {
+		t.Errorf("expected only bye to be untranslated in ja, got: %s", output)
+	}
+
+	// The command line wins over the configuration.
+	output, status = runConfigured(t, dir, &UntranslatedCommand{}, "--lang", "fr")
+	test.AssertEqual(t, status, 0)
+	if !strings.Contains(output, "hello") {
+		t.Errorf("expected hello to be untranslated in fr, got: %s", output)
+	}
+
+	test.AssertNoError(t, writeConfig(dir, "commands:\n  untranslated:\n    no-such-flag: true\n"))
+	_, status = runConfigured(t, dir, &UntranslatedCommand{})
+	test.AssertEqual(t, status, 2)
+}
+
+func TestConfigured_LintRules(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		".xckit.yaml": "lint:\n  rules:\n    literal-newline: error\n",
+		"Localizable.xcstrings": `{
+			"sourceLanguage": "en",
+			"strings": {
+				"": {"localizations": {"en": {"stringUnit": {"state": "translated", "value": "Empty"}}}},
+				"multiline": {"localizations": {"en": {"stringUnit": {"state": "translated", "value": "line one\nline two"}}}}
+			},
+			"version": "1.0"
+		}`,
+	})
+
+	output, status := runConfigured(t, dir, &LintCommand{})
+	test.AssertEqual(t, status, 1)
+	if !strings.Contains(output, "[error] literal-newline") || !strings.Contains(output, "empty-key") {
+		t.Errorf("expected literal-newline promoted to an error, got: %s", output)
+	}
+
+	test.AssertNoError(t, writeConfig(dir, "lint:\n  rules:\n    empty-key: off\n"))
+	output, status = runConfigured(t, dir, &LintCommand{})
+	test.AssertEqual(t, status, 0)
+	if strings.Contains(output, "empty-key") || !strings.Contains(output, "[warning] literal-newline") {
+		t.Errorf("expected empty-key to be turned off, got: %s", output)
+	}
+
+	test.AssertNoError(t, writeConfig(dir, "lint:\n  rules:\n    no-such-rule: off\n"))
+	_, status = runConfigured(t, dir, &LintCommand{})
+	test.AssertEqual(t, status, 2)
+}
+
+func TestConfigured_LintGlossary(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		".xckit.yaml":       "lint:\n  glossary: Docs/glossary.csv\n",
+		"Docs/glossary.csv": "en,ja\nWorkspace,ワークスペース\n",
+		"Localizable.xcstrings": `{
+			"sourceLanguage": "en",
+			"strings": {
+				"open": {"localizations": {
+					"en": {"stringUnit": {"state": "translated", "value": "Open Workspace"}},
+					"ja": {"stringUnit": {"state": "translated", "value": "作業スペースを開く"}}
+				}}
+			},
+			"version": "1.0"
+		}`,
+	})
+
+	output, status := runConfigured(t, dir, &LintCommand{})
+	test.AssertEqual(t, status, 0)
+	if !strings.Contains(output, "[warning] glossary") {
+		t.Errorf("expected the configured glossary to be used, got: %s", output)
+	}
+}
+
+func TestConfigured_InvalidConfig(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		".xckit.yaml":           "langauges: [ja]\n",
+		"Localizable.xcstrings": localizableFixture,
+	})
+
+	_, status := runConfigured(t, dir, &StatusCommand{})
+	test.AssertEqual(t, status, 1)
+}
+
+func writeConfig(dir, content string) error {
+	return os.WriteFile(filepath.Join(dir, ".xckit.yaml"), []byte(content), 0644)
+}
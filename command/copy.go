@@ -0,0 +1,207 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+type CopyCommand struct {
+	XCStringsCommand
+	to         string
+	languages  stringsFlag
+	force      bool
+	dryRun     bool
+	jsonOutput bool
+}
+
+func (*CopyCommand) Name() string {
+	return "copy"
+}
+
+func (*CopyCommand) Synopsis() string {
+	return "Copy a key's translations to another key or catalog"
+}
+
+func (*CopyCommand) Usage() string {
+	return `copy [-f file.xcstrings] [--to other.xcstrings] [--lang <language> ...] [--force] [--dry-run] [--json] <key> [<new-key>]: Copy <key>'s localizations to <new-key> (default: <key> in the --to catalog), creating it when missing
+Variations and substitutions are merged string unit by string unit. Nothing is copied when that would overwrite a translated string unit with a different value, unless --force is given.
+`
+}
+
+func (c *CopyCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.StringVar(&c.to, "to", "", "Copy into this .xcstrings file instead of the source catalog")
+	f.Var(&c.languages, "lang", "Only copy this language (repeatable; default: every language of the key)")
+	f.BoolVar(&c.force, "force", false, "Overwrite translated string units that differ from the source")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show what would be copied without writing the file")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output a structured JSON document describing the copied string units")
+}
+
+// copyJSONResult lists the string units copied for one language.
+type copyJSONResult struct {
+	Language    string   `json:"language"`
+	Paths       []string `json:"paths"`
+	Overwritten []string `json:"overwritten,omitempty"`
+}
+
+// copyJSONOutput is the top-level document printed by `copy --json`.
+type copyJSONOutput struct {
+	DryRun     bool             `json:"dryRun"`
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	File       string           `json:"file,omitempty"`
+	KeyCreated bool             `json:"keyCreated"`
+	Results    []copyJSONResult `json:"results"`
+	Summary    struct {
+		Languages int `json:"languages"`
+		Strings   int `json:"strings"`
+	} `json:"summary"`
+}
+
+func (c *CopyCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() < 1 || f.NArg() > 2 || (c.to == "" && (f.NArg() == 1 || f.Arg(0) == f.Arg(1))) {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: <key> and a different <new-key>, or --to, are required\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+	from, to := f.Arg(0), f.Arg(0)
+	if f.NArg() == 2 {
+		to = f.Arg(1)
+	}
+
+	if err := c.singleCatalog(); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	src, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	srcPath := c.filePath
+	if srcPath == "" {
+		srcPath = c.findXCStringsFile()
+	}
+
+	dst, dstPath := src, srcPath
+	if c.to != "" && !samePath(c.to, srcPath) {
+		dstPath = c.to
+		if dst, err = xcstrings.Load(dstPath); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	} else if from == to {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --to names the source catalog; give a different <new-key>\n")
+		return subcommands.ExitUsageError
+	}
+
+	definition, ok := src.Strings[from]
+	if !ok {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: key not found: %s\n", from)
+		return subcommands.ExitFailure
+	}
+	languages := []string(c.languages)
+	if len(languages) == 0 {
+		languages = sortedKeys(definition.Localizations)
+	}
+	for _, lang := range languages {
+		if _, ok := definition.Localizations[lang]; !ok {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: key %s has no %s localization\n", from, lang)
+			return subcommands.ExitFailure
+		}
+	}
+
+	out := copyJSONOutput{DryRun: c.dryRun, From: from, To: to, Results: []copyJSONResult{}}
+	if dstPath != srcPath {
+		out.File = dstPath
+	}
+	if _, exists := dst.Strings[to]; !exists {
+		// Like set, a key created outside of Xcode is "manual", so Xcode
+		// doesn't mark it stale before the code uses it.
+		dst.Strings[to] = xcstrings.StringDefinition{
+			Comment:         definition.Comment,
+			ExtractionState: "manual",
+			Localizations:   make(map[string]xcstrings.Localization),
+			ShouldTranslate: definition.ShouldTranslate,
+		}
+		out.KeyCreated = true
+	}
+
+	var conflicts []string
+	for _, lang := range languages {
+		copied, overwritten, err := dst.CopyLocalization(to, lang, definition.Localizations[lang], c.force)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		if !c.force {
+			for _, path := range overwritten {
+				conflicts = append(conflicts, fmt.Sprintf("%s (%s)", unitLabel(to, path), lang))
+			}
+		}
+		if len(copied) > 0 {
+			out.Results = append(out.Results, copyJSONResult{Language: lang, Paths: copied, Overwritten: overwritten})
+			out.Summary.Strings += len(copied)
+		}
+	}
+	if len(conflicts) > 0 {
+		for _, conflict := range conflicts {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s already has a different translation\n", conflict)
+		}
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Nothing was copied; use --force to overwrite, or --lang to copy other languages only\n")
+		return subcommands.ExitFailure
+	}
+	out.Summary.Languages = len(out.Results)
+
+	if !c.dryRun && (out.KeyCreated || len(out.Results) > 0) {
+		if err := dst.SaveToFile(dstPath); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	if c.jsonOutput {
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		fmt.Println(string(data))
+		return subcommands.ExitSuccess
+	}
+
+	prefix := ""
+	if c.dryRun {
+		prefix = "[dry-run] "
+	}
+	target := to
+	if out.File != "" {
+		target = out.File + ": " + to
+	}
+	if out.KeyCreated {
+		fmt.Printf("%sCreated key %s\n", prefix, target)
+	}
+	for _, r := range out.Results {
+		labels := make([]string, len(r.Paths))
+		for i, path := range r.Paths {
+			labels[i] = path
+			if path == "" {
+				labels[i] = "(string)"
+			}
+			if slices.Contains(r.Overwritten, path) {
+				labels[i] += " (overwritten)"
+			}
+		}
+		fmt.Printf("%s%s -> %s (%s): %s\n", prefix, from, target, r.Language, strings.Join(labels, ", "))
+	}
+	fmt.Printf("%sSummary: %d string units copied in %d languages\n", prefix, out.Summary.Strings, out.Summary.Languages)
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,117 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+const copyFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"hello": {"comment": "Greeting", "localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Hello"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}},
+			"fr": {"stringUnit": {"state": "translated", "value": "Bonjour"}}
+		}},
+		"greeting": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Hello"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "やあ"}}
+		}}
+	},
+	"version": "1.0"
+}`
+
+const copyPackageFixture = `{
+	"sourceLanguage": "en",
+	"strings": {},
+	"version": "1.0"
+}`
+
+func TestCopyCommand_NewKey(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", copyFixture)
+
+	output, status := runCommand(t, &CopyCommand{}, "-f", filePath, "--lang", "en", "--lang", "ja", "hello", "welcome")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "Created key welcome\nhello -> welcome (en): (string)\nhello -> welcome (ja): (string)\nSummary: 2 string units copied in 2 languages\n")
+
+	xcs, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	def := xcs.Strings["welcome"]
+	test.AssertEqual(t, def.Comment, "Greeting")
+	test.AssertEqual(t, def.ExtractionState, "manual")
+	test.AssertEqual(t, def.Localizations["ja"].StringUnit.Value, "こんにちは")
+	if _, ok := def.Localizations["fr"]; ok {
+		t.Error("fr was not asked for")
+	}
+	test.AssertEqual(t, xcs.Strings["hello"].Localizations["fr"].StringUnit.Value, "Bonjour")
+}
+
+func TestCopyCommand_Conflict(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", copyFixture)
+
+	// ja differs; fr would be added, but nothing is copied.
+	_, status := runCommand(t, &CopyCommand{}, "-f", filePath, "hello", "greeting")
+	test.AssertEqual(t, status, 1)
+	data, err := os.ReadFile(filePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(data), copyFixture)
+
+	output, status := runCommand(t, &CopyCommand{}, "-f", filePath, "--force", "hello", "greeting")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "hello -> greeting (fr): (string)\nhello -> greeting (ja): (string) (overwritten)\nSummary: 2 string units copied in 2 languages\n")
+
+	xcs, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xcs.Strings["greeting"].Localizations["ja"].StringUnit.Value, "こんにちは")
+	test.AssertEqual(t, xcs.Strings["greeting"].Localizations["fr"].StringUnit.Value, "Bonjour")
+}
+
+func TestCopyCommand_OtherCatalog(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", copyFixture)
+	packagePath := test.TempFile(t, "Package.xcstrings", copyPackageFixture)
+
+	output, status := runCommand(t, &CopyCommand{}, "-f", filePath, "--to", packagePath, "--dry-run", "--json", "hello")
+	test.AssertEqual(t, status, 0)
+	var out copyJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertEqual(t, out.KeyCreated, true)
+	test.AssertEqual(t, out.File, packagePath)
+	test.AssertEqual(t, out.Summary.Strings, 3)
+	data, err := os.ReadFile(packagePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(data), copyPackageFixture)
+
+	_, status = runCommand(t, &CopyCommand{}, "-f", filePath, "--to", packagePath, "hello")
+	test.AssertEqual(t, status, 0)
+	pkg, err := xcstrings.Load(packagePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, pkg.Strings["hello"].Localizations["fr"].StringUnit.Value, "Bonjour")
+
+	// Copying again changes nothing and is not a conflict.
+	output, status = runCommand(t, &CopyCommand{}, "-f", filePath, "--to", packagePath, "hello")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "Summary: 0 string units copied in 0 languages\n")
+}
+
+func TestCopyCommand_Errors(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", copyFixture)
+
+	for _, args := range [][]string{
+		{"hello"},
+		{"hello", "hello"},
+		{"--to", filePath, "hello"},
+		{"a", "b", "c"},
+	} {
+		_, status := runCommand(t, &CopyCommand{}, append([]string{"-f", filePath}, args...)...)
+		test.AssertEqual(t, status, 2)
+	}
+
+	_, status := runCommand(t, &CopyCommand{}, "-f", filePath, "missing", "other")
+	test.AssertEqual(t, status, 1)
+	_, status = runCommand(t, &CopyCommand{}, "-f", filePath, "--lang", "de", "hello", "other")
+	test.AssertEqual(t, status, 1)
+}
@@ -0,0 +1,418 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+type DiffCommand struct {
+	format string
+}
+
+func (*DiffCommand) Name() string {
+	return "diff"
+}
+
+func (*DiffCommand) Synopsis() string {
+	return "Show the differences between two catalogs per key, language and variation"
+}
+
+func (*DiffCommand) Usage() string {
+	return `diff [--format text|json|markdown] <old.xcstrings> <new.xcstrings>: Report added and removed keys, changed values and states per language and variation path, and comment changes
+diff <file.xcstrings>: Print the catalog one translation per line, for use as a git textconv filter
+Also accepts the seven arguments git passes to an external diff command (diff.<driver>.command or GIT_EXTERNAL_DIFF). A missing side (/dev/null or an empty file) is read as an empty catalog.
+`
+}
+
+func (c *DiffCommand) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.format, "format", "text", "Output format (text, json, markdown)")
+}
+
+// diffEntry is one difference between two catalogs. Language and Path are
+// empty for key-level changes; Path is the variation path of a translation
+// ("" for the plain string unit).
+//
+// Change is one of:
+//   - key-added, key-removed: the whole key (an added key is followed by
+//     an "added" entry for each of its translations)
+//   - comment, extractionState, shouldTranslate: a key property changed
+//   - added, removed: a translation appeared or disappeared
+//   - changed: a translation's value changed (its state may have too)
+//   - state: only a translation's state changed
+type diffEntry struct {
+	Key      string `json:"key"`
+	Language string `json:"language,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Change   string `json:"change"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+	OldState string `json:"oldState,omitempty"`
+	NewState string `json:"newState,omitempty"`
+}
+
+// diffSummary counts keys by how they differ.
+type diffSummary struct {
+	KeysAdded   int `json:"keysAdded"`
+	KeysRemoved int `json:"keysRemoved"`
+	KeysChanged int `json:"keysChanged"`
+}
+
+// diffJSONOutput is the document printed by `diff --format json`.
+type diffJSONOutput struct {
+	Old     string      `json:"old"`
+	New     string      `json:"new"`
+	Changes []diffEntry `json:"changes"`
+	Summary diffSummary `json:"summary"`
+}
+
+func (c *DiffCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.format != "text" && c.format != "json" && c.format != "markdown" {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --format must be text, json or markdown\n")
+		return subcommands.ExitUsageError
+	}
+
+	// name labels the catalog in Markdown; oldLabel and newLabel label the
+	// two sides in text and JSON output.
+	var oldPath, newPath, name, oldLabel, newLabel string
+	switch f.NArg() {
+	case 1:
+		xcs, err := loadDiffSide(f.Arg(0))
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		printTextconv(xcs)
+		return subcommands.ExitSuccess
+	case 2:
+		oldPath, newPath = f.Arg(0), f.Arg(1)
+		name, oldLabel, newLabel = newPath, oldPath, newPath
+	case 7:
+		// path old-file old-hex old-mode new-file new-hex new-mode
+		oldPath, newPath = f.Arg(1), f.Arg(4)
+		name, oldLabel, newLabel = f.Arg(0), "a/"+f.Arg(0), "b/"+f.Arg(0)
+	default:
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: old and new catalogs are required\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	oldXCS, err := loadDiffSide(oldPath)
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	newXCS, err := loadDiffSide(newPath)
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	entries := diffCatalogs(oldXCS, newXCS)
+	summary := summarizeDiff(entries)
+
+	switch c.format {
+	case "json":
+		out := diffJSONOutput{Old: oldLabel, New: newLabel, Changes: entries, Summary: summary}
+		if out.Changes == nil {
+			out.Changes = []diffEntry{}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		printDiffMarkdown(name, entries, summary)
+	default:
+		printDiffText(oldLabel, newLabel, entries, summary)
+	}
+	return subcommands.ExitSuccess
+}
+
+// loadDiffSide loads one side of a diff. git passes /dev/null, and an empty
+// file stands for a catalog that doesn't exist on that side yet.
+func loadDiffSide(path string) (*xcstrings.XCStrings, error) {
+	empty := &xcstrings.XCStrings{Strings: map[string]xcstrings.StringDefinition{}}
+	if path == os.DevNull {
+		return empty, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return empty, nil
+	}
+	xcs, err := xcstrings.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return xcs, nil
+}
+
+// diffCatalogs compares two catalogs key by key, in key order.
+func diffCatalogs(oldXCS, newXCS *xcstrings.XCStrings) []diffEntry {
+	keySet := make(map[string]bool)
+	for key := range oldXCS.Strings {
+		keySet[key] = true
+	}
+	for key := range newXCS.Strings {
+		keySet[key] = true
+	}
+
+	var entries []diffEntry
+	for _, key := range sortedKeys(keySet) {
+		oldDef, inOld := oldXCS.Strings[key]
+		newDef, inNew := newXCS.Strings[key]
+		switch {
+		case !inOld:
+			entries = append(entries, diffEntry{Key: key, Change: "key-added"})
+			oldDef = xcstrings.StringDefinition{}
+		case !inNew:
+			entries = append(entries, diffEntry{Key: key, Change: "key-removed"})
+			continue
+		default:
+			entries = append(entries, diffKeyProperties(key, oldDef, newDef)...)
+		}
+		entries = append(entries, diffLocalizations(key, oldDef, newDef)...)
+	}
+	return entries
+}
+
+func diffKeyProperties(key string, oldDef, newDef xcstrings.StringDefinition) []diffEntry {
+	var entries []diffEntry
+	property := func(name, oldValue, newValue string) {
+		if oldValue != newValue {
+			entries = append(entries, diffEntry{Key: key, Change: name, Old: oldValue, New: newValue})
+		}
+	}
+	property("comment", oldDef.Comment, newDef.Comment)
+	property("extractionState", oldDef.ExtractionState, newDef.ExtractionState)
+	property("shouldTranslate", shouldTranslateString(oldDef.ShouldTranslate), shouldTranslateString(newDef.ShouldTranslate))
+	return entries
+}
+
+func shouldTranslateString(b *bool) string {
+	if b != nil && !*b {
+		return "false"
+	}
+	return "true"
+}
+
+func diffLocalizations(key string, oldDef, newDef xcstrings.StringDefinition) []diffEntry {
+	langSet := make(map[string]bool)
+	for lang := range oldDef.Localizations {
+		langSet[lang] = true
+	}
+	for lang := range newDef.Localizations {
+		langSet[lang] = true
+	}
+
+	var entries []diffEntry
+	for _, lang := range sortedKeys(langSet) {
+		oldLoc := oldDef.Localizations[lang]
+		newLoc := newDef.Localizations[lang]
+		paths := localizationPaths(oldLoc)
+		for path := range localizationPaths(newLoc) {
+			paths[path] = true
+		}
+		for _, path := range sortedKeys(paths) {
+			oldUnit := localizationUnit(oldLoc, path)
+			newUnit := localizationUnit(newLoc, path)
+			entry := diffEntry{Key: key, Language: lang, Path: path}
+			entry.Old, entry.OldState = unitValueAndState(oldUnit)
+			entry.New, entry.NewState = unitValueAndState(newUnit)
+			switch {
+			case oldUnit == nil:
+				entry.Change = "added"
+			case newUnit == nil:
+				entry.Change = "removed"
+			case oldUnit.Value != newUnit.Value:
+				entry.Change = "changed"
+			case oldUnit.State != newUnit.State:
+				entry.Change = "state"
+				entry.Old, entry.New = "", ""
+			default:
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// localizationPaths returns the paths of every string unit in loc, "" for
+// the plain string unit.
+func localizationPaths(loc xcstrings.Localization) map[string]bool {
+	paths := make(map[string]bool)
+	if loc.StringUnit != nil {
+		paths[""] = true
+	}
+	if loc.Variations != nil {
+		collectVariationPaths(loc.Variations, "", paths)
+	}
+	for name, sub := range loc.Substitutions {
+		collectVariationPaths(&sub.Variations, "substitutions."+name, paths)
+	}
+	return paths
+}
+
+func summarizeDiff(entries []diffEntry) diffSummary {
+	var summary diffSummary
+	changed := make(map[string]bool)
+	added := make(map[string]bool)
+	for _, e := range entries {
+		switch e.Change {
+		case "key-added":
+			summary.KeysAdded++
+			added[e.Key] = true
+		case "key-removed":
+			summary.KeysRemoved++
+		default:
+			if !added[e.Key] && !changed[e.Key] {
+				changed[e.Key] = true
+				summary.KeysChanged++
+			}
+		}
+	}
+	return summary
+}
+
+// printDiffText prints the differences grouped by key: "+" for an added
+// key, "-" for a removed one and "~" for a changed one, each followed by its
+// changes.
+func printDiffText(oldLabel, newLabel string, entries []diffEntry, summary diffSummary) {
+	if len(entries) == 0 {
+		fmt.Println("No differences")
+		return
+	}
+	fmt.Printf("--- %s\n+++ %s\n", oldLabel, newLabel)
+	lastKey := ""
+	for i, e := range entries {
+		if i == 0 || e.Key != lastKey {
+			marker := "~"
+			switch e.Change {
+			case "key-added":
+				marker = "+"
+			case "key-removed":
+				marker = "-"
+			}
+			fmt.Printf("%s %s\n", marker, e.Key)
+			lastKey = e.Key
+		}
+		if line := diffEntryText(e); line != "" {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	fmt.Printf("Summary: %d keys added, %d removed, %d changed\n", summary.KeysAdded, summary.KeysRemoved, summary.KeysChanged)
+}
+
+// diffEntryText describes one change below its key's line, or returns ""
+// for the key-added and key-removed entries the key's line already shows.
+func diffEntryText(e diffEntry) string {
+	label := e.Language
+	if e.Path != "" {
+		label += "[" + e.Path + "]"
+	}
+	switch e.Change {
+	case "key-added", "key-removed":
+		return ""
+	case "comment", "extractionState", "shouldTranslate":
+		return fmt.Sprintf("%s: %q -> %q", e.Change, e.Old, e.New)
+	case "added":
+		return fmt.Sprintf("%s: + %q [%s]", label, e.New, e.NewState)
+	case "removed":
+		return fmt.Sprintf("%s: - %q [%s]", label, e.Old, e.OldState)
+	case "state":
+		return fmt.Sprintf("%s: state %s -> %s", label, e.OldState, e.NewState)
+	}
+	line := fmt.Sprintf("%s: %q -> %q", label, e.Old, e.New)
+	if e.OldState != e.NewState {
+		line += fmt.Sprintf(" [%s -> %s]", e.OldState, e.NewState)
+	}
+	return line
+}
+
+// printDiffMarkdown prints the differences as a Markdown table, suitable for
+// a pull request comment.
+func printDiffMarkdown(name string, entries []diffEntry, summary diffSummary) {
+	fmt.Printf("#### `%s`: %d keys added, %d removed, %d changed\n\n", name, summary.KeysAdded, summary.KeysRemoved, summary.KeysChanged)
+	if len(entries) == 0 {
+		fmt.Println("No differences.")
+		return
+	}
+	fmt.Println("| Key | Language | Path | Change | Old | New |")
+	fmt.Println("| --- | --- | --- | --- | --- | --- |")
+	for _, e := range entries {
+		oldCell, newCell := e.Old, e.New
+		if e.OldState != e.NewState {
+			oldCell = joinNonEmpty(oldCell, stateCell(e.OldState))
+			newCell = joinNonEmpty(newCell, stateCell(e.NewState))
+		}
+		fmt.Printf("| `%s` | %s | %s | %s | %s | %s |\n",
+			markdownCell(strings.ReplaceAll(e.Key, "`", "'")), markdownCell(e.Language), markdownCell(e.Path), e.Change, markdownCell(oldCell), markdownCell(newCell))
+	}
+}
+
+func stateCell(state string) string {
+	if state == "" {
+		return ""
+	}
+	return "_(" + state + ")_"
+}
+
+func joinNonEmpty(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + " " + b
+}
+
+// markdownCell escapes text for a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// printTextconv prints a catalog one key property or translation per line,
+// sorted, so that git's line diff of two catalogs reads like a catalog diff:
+//
+//	greeting comment="Shown on launch"
+//	greeting (ja): "こんにちは" [translated]
+//	item_count[plural.one] (en): "%lld item" [translated]
+func printTextconv(xcs *xcstrings.XCStrings) {
+	fmt.Printf("sourceLanguage: %s\n", xcs.SourceLanguage)
+	for _, key := range sortedKeys(xcs.Strings) {
+		def := xcs.Strings[key]
+		line := key
+		if def.ExtractionState != "" {
+			line += " extractionState=" + def.ExtractionState
+		}
+		if def.ShouldTranslate != nil && !*def.ShouldTranslate {
+			line += " shouldTranslate=false"
+		}
+		if def.Comment != "" {
+			line += " comment=" + strconv.Quote(def.Comment)
+		}
+		fmt.Println(line)
+
+		for _, lang := range sortedKeys(def.Localizations) {
+			loc := def.Localizations[lang]
+			for _, path := range sortedKeys(localizationPaths(loc)) {
+				unit := localizationUnit(loc, path)
+				fmt.Printf("%s (%s): %q [%s]\n", unitLabel(key, path), lang, unit.Value, unit.State)
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+const diffOldFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"greeting": {"comment": "Shown on launch", "localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Hello"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}},
+			"de": {"stringUnit": {"state": "translated", "value": "Hallo"}}
+		}},
+		"item_count": {"localizations": {
+			"en": {"variations": {"plural": {
+				"one": {"stringUnit": {"state": "translated", "value": "%lld item"}},
+				"other": {"stringUnit": {"state": "translated", "value": "%lld items"}}
+			}}}
+		}},
+		"legacy": {"extractionState": "stale", "localizations": {}}
+	},
+	"version": "1.0"
+}`
+
+const diffNewFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"greeting": {"comment": "Shown on first launch", "localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Hello"}},
+			"ja": {"stringUnit": {"state": "needs_review", "value": "やあ"}},
+			"fr": {"stringUnit": {"state": "translated", "value": "Bonjour"}}
+		}},
+		"item_count": {"localizations": {
+			"en": {"variations": {"plural": {
+				"one": {"stringUnit": {"state": "needs_review", "value": "%lld item"}},
+				"other": {"stringUnit": {"state": "translated", "value": "%lld items"}}
+			}}}
+		}},
+		"welcome": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Welcome"}}
+		}}
+	},
+	"version": "1.0"
+}`
+
+func TestDiffCommand_JSON(t *testing.T) {
+	oldPath := test.TempFile(t, "old.xcstrings", diffOldFixture)
+	newPath := test.TempFile(t, "new.xcstrings", diffNewFixture)
+
+	output, status := runCommand(t, &DiffCommand{}, "--format", "json", oldPath, newPath)
+	test.AssertEqual(t, status, 0)
+
+	var out diffJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	want := []diffEntry{
+		{Key: "greeting", Change: "comment", Old: "Shown on launch", New: "Shown on first launch"},
+		{Key: "greeting", Language: "de", Change: "removed", Old: "Hallo", OldState: "translated"},
+		{Key: "greeting", Language: "fr", Change: "added", New: "Bonjour", NewState: "translated"},
+		{Key: "greeting", Language: "ja", Change: "changed", Old: "こんにちは", New: "やあ", OldState: "translated", NewState: "needs_review"},
+		{Key: "item_count", Language: "en", Path: "plural.one", Change: "state", OldState: "translated", NewState: "needs_review"},
+		{Key: "legacy", Change: "key-removed"},
+		{Key: "welcome", Change: "key-added"},
+		{Key: "welcome", Language: "en", Change: "added", New: "Welcome", NewState: "translated"},
+	}
+	if !slices.Equal(out.Changes, want) {
+		t.Errorf("changes:\n got %+v\nwant %+v", out.Changes, want)
+	}
+	test.AssertEqual(t, out.Summary, diffSummary{KeysAdded: 1, KeysRemoved: 1, KeysChanged: 2})
+}
+
+func TestDiffCommand_Text(t *testing.T) {
+	oldPath := test.TempFile(t, "old.xcstrings", diffOldFixture)
+	newPath := test.TempFile(t, "new.xcstrings", diffNewFixture)
+
+	output, status := runCommand(t, &DiffCommand{}, oldPath, newPath)
+	test.AssertEqual(t, status, 0)
+	for _, want := range []string{
+		"~ greeting\n" +
+			`    comment: "Shown on launch" -> "Shown on first launch"` + "\n" +
+			`    de: - "Hallo" [translated]` + "\n" +
+			`    fr: + "Bonjour" [translated]` + "\n" +
+			`    ja: "こんにちは" -> "やあ" [translated -> needs_review]` + "\n",
+		"~ item_count\n    en[plural.one]: state translated -> needs_review\n",
+		"- legacy\n+ welcome\n" + `    en: + "Welcome" [translated]` + "\n",
+		"Summary: 1 keys added, 1 removed, 2 changed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	output, status = runCommand(t, &DiffCommand{}, oldPath, oldPath)
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "No differences\n")
+}
+
+func TestDiffCommand_Markdown(t *testing.T) {
+	oldPath := test.TempFile(t, "old.xcstrings", diffOldFixture)
+	newPath := test.TempFile(t, "new.xcstrings", strings.Replace(diffNewFixture, `"Bonjour"`, `"Bon|jour\nà tous"`, 1))
+
+	output, status := runCommand(t, &DiffCommand{}, "--format", "markdown", oldPath, newPath)
+	test.AssertEqual(t, status, 0)
+	for _, want := range []string{
+		"`: 1 keys added, 1 removed, 2 changed\n",
+		"| Key | Language | Path | Change | Old | New |",
+		"| `greeting` | fr |  | added |  | Bon\\|jour<br>à tous _(translated)_ |",
+		"| `item_count` | en | plural.one | state | _(translated)_ | _(needs_review)_ |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestDiffCommand_MarkdownEscapesKeys(t *testing.T) {
+	oldPath := test.TempFile(t, "old.xcstrings", `{"sourceLanguage": "en", "strings": {}, "version": "1.0"}`)
+	newPath := test.TempFile(t, "new.xcstrings", `{
+		"sourceLanguage": "en",
+		"strings": {
+			"yes|no": {"localizations": {"en": {"stringUnit": {"state": "translated", "value": "Yes"}}}}
+		},
+		"version": "1.0"
+	}`)
+
+	output, status := runCommand(t, &DiffCommand{}, "--format", "markdown", oldPath, newPath)
+	test.AssertEqual(t, status, 0)
+	if want := "| `yes\\|no` | en |  | added |  | Yes _(translated)_ |"; !strings.Contains(output, want) {
+		t.Errorf("expected %q in output, got:\n%s", want, output)
+	}
+}
+
+func TestDiffCommand_GitArguments(t *testing.T) {
+	newPath := test.TempFile(t, "new.xcstrings", diffNewFixture)
+
+	// A file added in git: git's external diff arguments with /dev/null as
+	// the old side.
+	output, status := runCommand(t, &DiffCommand{}, "--format", "markdown", "App/Localizable.xcstrings", os.DevNull, ".", ".", newPath, "abc123", "100644")
+	test.AssertEqual(t, status, 0)
+	if !strings.HasPrefix(output, "#### `App/Localizable.xcstrings`: 3 keys added, 0 removed, 0 changed") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+
+	output, status = runCommand(t, &DiffCommand{}, "App/Localizable.xcstrings", os.DevNull, ".", ".", newPath, "abc123", "100644")
+	test.AssertEqual(t, status, 0)
+	if !strings.HasPrefix(output, "--- a/App/Localizable.xcstrings\n+++ b/App/Localizable.xcstrings\n") {
+		t.Errorf("expected git's path in the header, got:\n%s", output)
+	}
+
+	// textconv: one line per translation.
+	output, status = runCommand(t, &DiffCommand{}, newPath)
+	test.AssertEqual(t, status, 0)
+	for _, want := range []string{
+		"sourceLanguage: en\n",
+		"greeting comment=\"Shown on first launch\"\n",
+		"greeting (ja): \"やあ\" [needs_review]\n",
+		"item_count[plural.one] (en): \"%lld item\" [needs_review]\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in textconv output, got:\n%s", want, output)
+		}
+	}
+
+	_, status = runCommand(t, &DiffCommand{}, newPath, newPath, newPath)
+	test.AssertEqual(t, status, 2)
+	_, status = runCommand(t, &DiffCommand{}, "--format", "html", newPath, newPath)
+	test.AssertEqual(t, status, 2)
+}
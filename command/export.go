@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sort"
 
 	"xckit/xcstrings"
@@ -16,8 +17,10 @@ import (
 
 type ExportCommand struct {
 	XCStringsCommand
-	format string
-	output string
+	format   string
+	output   string
+	language string
+	state    string
 }
 
 func (*ExportCommand) Name() string {
@@ -25,24 +28,63 @@ func (*ExportCommand) Name() string {
 }
 
 func (*ExportCommand) Synopsis() string {
-	return "Export strings to CSV"
+	return "Export strings to CSV, XLIFF, .strings/.stringsdict, PO or NDJSON"
 }
 
 func (*ExportCommand) Usage() string {
-	return "export --format csv [-f file.xcstrings] [-o output.csv]: Export strings to CSV\n"
+	return "export --format csv|xliff|strings|po|ndjson [-f file.xcstrings] [--lang <language>] [--state <state>] [-o output]: Export strings to CSV, XLIFF 1.2, <lang>.lproj/*.strings and *.stringsdict, gettext .po/.pot files (strings and po write into the -o directory), or set --stdin NDJSON rows\n"
 }
 
 func (c *ExportCommand) SetFlags(f *flag.FlagSet) {
 	c.SetXCStringsFlags(f)
-	f.StringVar(&c.format, "format", "", "Export format (csv)")
-	f.StringVar(&c.output, "o", "", "Output file path (default: stdout)")
+	f.StringVar(&c.format, "format", "", "Export format (csv, xliff, strings, po, ndjson)")
+	f.StringVar(&c.output, "o", "", "Output file path (default: stdout); output directory for strings and po")
+	f.StringVar(&c.language, "lang", "", "Only export this language (xliff, strings, po, ndjson; default: every language, excluding the source for xliff and po)")
+	f.StringVar(&c.state, "state", "", "Only export string units in this translation state, e.g. needs_review (ndjson)")
 }
 
 func (c *ExportCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
-	if c.format != "csv" {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --format csv is required\n")
+	if c.format != "csv" && c.format != "xliff" && c.format != "strings" && c.format != "po" && c.format != "ndjson" {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --format csv, xliff, strings, po or ndjson is required\n")
 		return subcommands.ExitFailure
 	}
+	if c.state != "" && c.format != "ndjson" {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --state is only supported with --format ndjson\n")
+		return subcommands.ExitFailure
+	}
+	dirFormat := c.format == "strings" || c.format == "po"
+	if dirFormat && c.output == "" {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --format %s requires -o <directory>\n", c.format)
+		return subcommands.ExitFailure
+	}
+
+	if c.format == "strings" {
+		// Every catalog is written as its own table, so several catalogs can
+		// share the .lproj directories as long as their names differ.
+		paths, err := c.catalogPaths()
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		tables := make(map[string]string)
+		for _, path := range paths {
+			table := catalogTableName(path)
+			if other, ok := tables[table]; ok {
+				_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s and %s would both be exported as table %s\n", other, path, table)
+				return subcommands.ExitFailure
+			}
+			tables[table] = path
+		}
+		return c.forEachCatalog(true, c.exportDir)
+	}
+
+	if err := c.singleCatalog(); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	if dirFormat {
+		return c.exportDir()
+	}
 
 	xc, err := c.LoadXCStrings()
 	if err != nil {
@@ -50,6 +92,11 @@ func (c *ExportCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interfa
 		return subcommands.ExitFailure
 	}
 
+	xcPath := c.filePath
+	if xcPath == "" {
+		xcPath = c.findXCStringsFile()
+	}
+
 	var w io.Writer
 	if c.output != "" {
 		file, err := os.Create(c.output)
@@ -63,7 +110,15 @@ func (c *ExportCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interfa
 		w = os.Stdout
 	}
 
-	if err := writeCSV(w, xc); err != nil {
+	switch c.format {
+	case "xliff":
+		err = writeXLIFF(w, xc, filepath.Base(xcPath), c.language)
+	case "ndjson":
+		err = writeNDJSON(w, xc, c.language, c.state)
+	default:
+		err = writeCSV(w, xc)
+	}
+	if err != nil {
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
 		return subcommands.ExitFailure
 	}
@@ -71,6 +126,33 @@ func (c *ExportCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interfa
 	return subcommands.ExitSuccess
 }
 
+// exportDir writes the current catalog into the -o directory as its own
+// .strings/.stringsdict or .po/.pot table.
+func (c *ExportCommand) exportDir() subcommands.ExitStatus {
+	xc, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	xcPath := c.filePath
+	if xcPath == "" {
+		xcPath = c.findXCStringsFile()
+	}
+
+	table := catalogTableName(xcPath)
+	if c.format == "po" {
+		err = writePO(c.output, xc, table, c.language)
+	} else {
+		err = writeLproj(c.output, xc, table, c.language)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
 // csvRow represents a single row in the CSV output.
 type csvRow struct {
 	key             string
@@ -6,8 +6,8 @@ import (
 	"flag"
 	"fmt"
 	"io"
-	"log"
 	"os"
+	"slices"
 	"strings"
 
 	"xckit/helper/atomicwrite"
@@ -23,6 +23,8 @@ type ImportCommand struct {
 	backup       bool
 	onMissingKey string
 	clearEmpty   bool
+	report       bool
+	jsonOutput   bool
 }
 
 func (*ImportCommand) Name() string {
@@ -30,68 +32,83 @@ func (*ImportCommand) Name() string {
 }
 
 func (*ImportCommand) Synopsis() string {
-	return "Import translations from CSV"
+	return "Import translations from CSV, XLIFF, .strings/.stringsdict or PO"
 }
 
 func (*ImportCommand) Usage() string {
-	return "import --format csv [-f file.xcstrings] [--dry-run] [--backup] [--on-missing-key skip|error] [--clear-empty] <csv-file>: Import translations from CSV\n"
+	return "import --format csv|xliff|strings|po [-f file.xcstrings] [--dry-run] [--backup] [--on-missing-key skip|error|create] [--clear-empty] [--report] [--json] <input-file|lproj-dir>: Import translations from CSV, XLIFF 1.2, a tree of <lang>.lproj directories, or a gettext .po file\n"
 }
 
 func (c *ImportCommand) SetFlags(f *flag.FlagSet) {
 	c.SetXCStringsFlags(f)
-	f.StringVar(&c.format, "format", "", "Import format (csv)")
+	f.StringVar(&c.format, "format", "", "Import format (csv, xliff, strings, po)")
 	f.BoolVar(&c.dryRun, "dry-run", false, "Show change summary without writing")
 	f.BoolVar(&c.backup, "backup", false, "Copy original to .bak before writing")
-	f.StringVar(&c.onMissingKey, "on-missing-key", "skip", "Action for missing keys: skip or error")
-	f.BoolVar(&c.clearEmpty, "clear-empty", false, "Clear translations for empty CSV cells")
+	f.StringVar(&c.onMissingKey, "on-missing-key", "skip", "Action for missing keys: skip, error, or create (CSV only)")
+	f.BoolVar(&c.clearEmpty, "clear-empty", false, "Clear translations for empty CSV cells, XLIFF units without a target, or empty msgstr")
+	f.BoolVar(&c.report, "report", false, "List every created, updated, cleared and skipped translation with its old and new value")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output the change report as a JSON document")
 }
 
 func (c *ImportCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
-	if c.format != "csv" {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --format csv is required\n")
+	if c.format != "csv" && c.format != "xliff" && c.format != "strings" && c.format != "po" {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --format csv, xliff, strings or po is required\n")
 		return subcommands.ExitFailure
 	}
 
-	if c.onMissingKey != "skip" && c.onMissingKey != "error" {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --on-missing-key must be skip or error\n")
+	if c.onMissingKey != "skip" && c.onMissingKey != "error" && c.onMissingKey != "create" {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --on-missing-key must be skip, error or create\n")
 		return subcommands.ExitFailure
 	}
+	if c.onMissingKey == "create" && (c.format == "xliff" || c.format == "po") {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --on-missing-key create is only supported with --format csv\n")
+		return subcommands.ExitUsageError
+	}
 
 	args := f.Args()
 	if len(args) < 1 {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: CSV file path is required\n")
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: input file path is required\n")
 		return subcommands.ExitFailure
 	}
-	csvPath := args[0]
+	inputPath := args[0]
 
-	xc, err := c.LoadXCStrings()
-	if err != nil {
+	// A .lproj tree holds one table per catalog, so it can be imported into
+	// several catalogs at once; the other formats target a single catalog.
+	if c.format == "strings" {
+		return c.forEachCatalog(c.jsonOutput, func() subcommands.ExitStatus { return c.execute(inputPath) })
+	}
+	if err := c.singleCatalog(); err != nil {
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
 		return subcommands.ExitFailure
 	}
+	return c.execute(inputPath)
+}
 
-	csvFile, err := os.Open(csvPath)
+func (c *ImportCommand) execute(inputPath string) subcommands.ExitStatus {
+	xc, err := c.LoadXCStrings()
 	if err != nil {
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
 		return subcommands.ExitFailure
 	}
-	defer func() { _ = csvFile.Close() }()
 
-	summary, err := importCSV(csvFile, xc, c.onMissingKey, c.clearEmpty)
+	xcPath := c.filePath
+	if xcPath == "" {
+		xcPath = c.findXCStringsFile()
+	}
+
+	var summary *importSummary
+	if c.format == "strings" {
+		summary, err = importLproj(inputPath, xc, catalogTableName(xcPath), c.clearEmpty)
+	} else {
+		summary, err = c.importFile(inputPath, xc)
+	}
 	if err != nil {
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
 		return subcommands.ExitFailure
 	}
 
 	if c.dryRun {
-		_, _ = fmt.Fprintf(os.Stdout, "Dry run: %d created, %d updated, %d unchanged, %d cleared, %d skipped\n",
-			summary.created, summary.updated, summary.unchanged, summary.cleared, summary.skipped)
-		return subcommands.ExitSuccess
-	}
-
-	xcPath := c.filePath
-	if xcPath == "" {
-		xcPath = c.findXCStringsFile()
+		return c.printSummary(summary)
 	}
 
 	if c.backup {
@@ -112,11 +129,58 @@ func (c *ImportCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interfa
 		return subcommands.ExitFailure
 	}
 
-	_, _ = fmt.Fprintf(os.Stdout, "Imported: %d created, %d updated, %d unchanged, %d cleared, %d skipped\n",
-		summary.created, summary.updated, summary.unchanged, summary.cleared, summary.skipped)
+	return c.printSummary(summary)
+}
+
+// printSummary prints the outcome of the import: the JSON report with
+// --json, otherwise the tally, preceded by the change log with --report.
+func (c *ImportCommand) printSummary(summary *importSummary) subcommands.ExitStatus {
+	if c.jsonOutput {
+		return c.printJSON(summary.jsonOutput(c.dryRun))
+	}
+
+	label := "Imported"
+	if c.dryRun {
+		label = "Dry run"
+	}
+	if c.report {
+		prefix := ""
+		if c.dryRun {
+			prefix = "[dry-run] "
+		}
+		for _, line := range summary.reportLines() {
+			fmt.Printf("%s%s\n", prefix, line)
+		}
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "%s: %d created, %d updated, %d unchanged, %d cleared, %d skipped\n",
+		label, summary.created, summary.updated, summary.unchanged, summary.cleared, summary.skipped)
+	// Key-level changes, which the per-translation tally doesn't cover.
+	if summary.keysCreated > 0 {
+		_, _ = fmt.Fprintf(os.Stdout, "Created %d keys\n", summary.keysCreated)
+	}
+	if summary.keysUpdated > 0 {
+		_, _ = fmt.Fprintf(os.Stdout, "Comment or shouldTranslate changed for %d keys\n", summary.keysUpdated)
+	}
 	return subcommands.ExitSuccess
 }
 
+// importFile reads a CSV, XLIFF or PO input file into the catalog.
+func (c *ImportCommand) importFile(inputPath string, xc *xcstrings.XCStrings) (*importSummary, error) {
+	inputFile, err := os.Open(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = inputFile.Close() }()
+
+	switch c.format {
+	case "xliff":
+		return importXLIFF(inputFile, xc, c.onMissingKey, c.clearEmpty)
+	case "po":
+		return importPO(inputFile, xc, c.onMissingKey, c.clearEmpty)
+	}
+	return importCSV(inputFile, xc, c.onMissingKey, c.clearEmpty)
+}
+
 // importSummary tallies the outcome of an import run:
 //   - created:   a translation was written where none existed for that lang/variation
 //   - updated:   an existing translation's value was changed
@@ -124,104 +188,346 @@ func (c *ImportCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interfa
 //     or the CSV cell was empty and there was nothing to clear
 //   - cleared:   an existing translation was removed via --clear-empty
 //   - skipped:   the row's key was missing (--on-missing-key skip) or the write failed
+//
+// keysCreated counts keys created by --on-missing-key create, and keysUpdated
+// keys whose comment or shouldTranslate flag changed (CSV only). changes and
+// keyChanges log every counted change but the unchanged ones, for --report
+// and --json.
 type importSummary struct {
-	created   int
-	updated   int
-	unchanged int
-	cleared   int
-	skipped   int
+	created     int
+	updated     int
+	unchanged   int
+	cleared     int
+	skipped     int
+	keysCreated int
+	keysUpdated int
+	changes     []importChange
+	keyChanges  []importKeyChange
+}
+
+// csvStates are the string unit states accepted in a CSV <lang>:state
+// column.
+var csvStates = []string{"translated", "needs_review", "new", "stale"}
+
+// csvImportRow is a validated CSV data row.
+type csvImportRow struct {
+	line            int
+	key             string
+	variationPath   string
+	comment         string
+	shouldTranslate string // "", "true" or "false"
+	missing         bool   // the key is not in the catalog (--on-missing-key skip)
+	create          bool   // the key is not in the catalog (--on-missing-key create)
+	record          []string
+}
+
+// csvKeyMetadata is the comment and shouldTranslate a key's rows agree on,
+// with the line each was first given on.
+type csvKeyMetadata struct {
+	comment             string
+	commentLine         int
+	shouldTranslate     string
+	shouldTranslateLine int
 }
 
 // importCSV reads CSV data and applies translations to the xcstrings catalog.
+// Besides the values, it honors the <lang>:state columns, the comment column
+// (given on the first row of a key with variations) and the shouldTranslate
+// column. Every row is validated before anything is applied; all invalid rows
+// are reported together and the catalog is left untouched.
 func importCSV(r io.Reader, xc *xcstrings.XCStrings, onMissingKey string, clearEmpty bool) (*importSummary, error) {
 	reader := csv.NewReader(r)
-	records, err := reader.ReadAll()
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &importSummary{}, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read CSV: %w", err)
 	}
 
-	if len(records) < 1 {
-		return &importSummary{}, nil
-	}
-
-	header := records[0]
 	langColumns, err := parseHeader(header)
 	if err != nil {
 		return nil, err
 	}
 
-	summary := &importSummary{}
-
-	for _, record := range records[1:] {
-		if len(record) < 1 {
-			continue
+	var rows []csvImportRow
+	var keyOrder []string
+	metadata := make(map[string]*csvKeyMetadata)
+	var rowErrs []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
 		}
-		rawKey := record[0]
-		// column 1 = comment (ignored)
-		// column 2 = shouldTranslate (ignored)
+		line, _ := reader.FieldPos(0)
 
-		baseKey, variationPath := parseKeyBracket(rawKey)
+		baseKey, variationPath := parseKeyBracket(record[0])
+		row := csvImportRow{line: line, key: baseKey, variationPath: variationPath, record: record}
+		if len(record) > 1 {
+			row.comment = record[1]
+		}
+		if len(record) > 2 {
+			row.shouldTranslate = strings.ToLower(strings.TrimSpace(record[2]))
+		}
 
-		_, keyExists := xc.Strings[baseKey]
-		if !keyExists {
-			if onMissingKey == "error" {
-				return nil, fmt.Errorf("key not found: %s", baseKey)
+		valid := true
+		if _, exists := xc.Strings[baseKey]; !exists {
+			switch onMissingKey {
+			case "error":
+				rowErrs = append(rowErrs, fmt.Sprintf("line %d: key not found: %s", line, baseKey))
+				continue
+			case "create":
+				row.create = true
+				if err := validateCSVNewKeyRow(row, xc.SourceLanguage, langColumns); err != nil {
+					rowErrs = append(rowErrs, fmt.Sprintf("line %d: %v", line, err))
+					valid = false
+				}
+			default:
+				row.missing = true
+				rows = append(rows, row)
+				continue
 			}
-			summary.skipped++
-			continue
 		}
 
+		if row.shouldTranslate != "" && row.shouldTranslate != "true" && row.shouldTranslate != "false" {
+			rowErrs = append(rowErrs, fmt.Sprintf("line %d: invalid shouldTranslate '%s' (valid: true, false or empty)", line, record[2]))
+			valid = false
+		}
 		for _, lc := range langColumns {
-			// Skip source language
-			if lc.lang == xc.SourceLanguage {
+			if lc.lang == xc.SourceLanguage || lc.stateIdx >= len(record) {
 				continue
 			}
+			if state := record[lc.stateIdx]; state != "" && !slices.Contains(csvStates, state) {
+				rowErrs = append(rowErrs, fmt.Sprintf("line %d: invalid %s:state '%s' (valid: %s)", line, lc.lang, state, strings.Join(csvStates, ", ")))
+				valid = false
+			}
+		}
 
-			if lc.valueIdx >= len(record) {
-				continue
+		meta, seen := metadata[baseKey]
+		if !seen {
+			meta = &csvKeyMetadata{shouldTranslate: row.shouldTranslate, shouldTranslateLine: line}
+			metadata[baseKey] = meta
+			keyOrder = append(keyOrder, baseKey)
+		} else if valid && row.shouldTranslate != meta.shouldTranslate {
+			rowErrs = append(rowErrs, fmt.Sprintf("line %d: shouldTranslate '%s' of key %s conflicts with '%s' on line %d", line, row.shouldTranslate, baseKey, meta.shouldTranslate, meta.shouldTranslateLine))
+			valid = false
+		}
+		if row.comment != "" {
+			if meta.commentLine == 0 {
+				meta.comment, meta.commentLine = row.comment, line
+			} else if row.comment != meta.comment {
+				rowErrs = append(rowErrs, fmt.Sprintf("line %d: comment of key %s conflicts with the one on line %d", line, baseKey, meta.commentLine))
+				valid = false
 			}
-			value := record[lc.valueIdx]
+		}
 
-			existingValue, existing := currentTranslationValue(xc, baseKey, lc.lang, variationPath)
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+	if len(rowErrs) > 0 {
+		return nil, fmt.Errorf("%d invalid CSV rows, nothing was imported:\n  %s", len(rowErrs), strings.Join(rowErrs, "\n  "))
+	}
 
-			if value == "" {
-				// Nothing to clear: no existing translation, or it's already empty.
-				if !existing || existingValue == "" {
-					summary.unchanged++
-					continue
-				}
-				if !clearEmpty {
-					// A translation exists but --clear-empty wasn't requested: leave it untouched.
-					summary.unchanged++
-					continue
-				}
-				if err := clearTranslation(xc, baseKey, lc.lang, variationPath); err == nil {
-					summary.cleared++
-				}
-				continue
+	summary := &importSummary{}
+	for _, row := range rows {
+		if row.missing {
+			summary.skip(row.key, "", row.variationPath, skipMissingKey, "key not found: "+row.key)
+			continue
+		}
+		if row.create {
+			if err := createCSVKeyValue(xc, summary, row, langColumns); err != nil {
+				return nil, fmt.Errorf("line %d: %w", row.line, err)
 			}
-
-			if existing && existingValue == value {
-				summary.unchanged++
+		}
+		for _, lc := range langColumns {
+			// Skip source language
+			if lc.lang == xc.SourceLanguage {
 				continue
 			}
-
-			if err := setTranslation(xc, baseKey, lc.lang, value, variationPath); err != nil {
-				log.Printf("Warning: skipping key %q lang %q: %v", baseKey, lc.lang, err)
-				summary.skipped++
+			if lc.valueIdx >= len(row.record) {
 				continue
 			}
-			if existing {
-				summary.updated++
-			} else {
-				summary.created++
+			value := row.record[lc.valueIdx]
+			state := ""
+			if lc.stateIdx < len(row.record) {
+				state = csvImportState(xc, row.key, lc.lang, row.variationPath, value, row.record[lc.stateIdx])
 			}
+			applyImportValue(xc, summary, row.key, lc.lang, row.variationPath, value, state, clearEmpty)
 		}
 	}
 
+	for _, key := range keyOrder {
+		applyCSVKeyMetadata(xc, summary, key, metadata[key], clearEmpty)
+	}
+
 	return summary, nil
 }
 
+// validateCSVNewKeyRow checks that a row of a key missing from the catalog
+// can create it: it needs a source-language value, and a variation path made
+// of plural and device segments. Substitution rows can't create a key, since
+// the CSV doesn't carry a substitution's argNum and formatSpecifier.
+func validateCSVNewKeyRow(row csvImportRow, sourceLang string, langColumns []langColumn) error {
+	if row.variationPath != "" {
+		parts := splitPath(row.variationPath)
+		if parts[0] == "substitutions" {
+			return fmt.Errorf("cannot create key %s from a substitution row; create the key, then add the substitution with set --substitution", row.key)
+		}
+		opts, err := parseVariationOpts(parts)
+		if err != nil {
+			return err
+		}
+		if opts.Plural != "" && !slices.Contains(xcstrings.ValidPluralCategories, opts.Plural) {
+			return fmt.Errorf("invalid plural category '%s' (valid: %s)", opts.Plural, strings.Join(xcstrings.ValidPluralCategories, ", "))
+		}
+		if opts.Device != "" && !slices.Contains(xcstrings.ValidDeviceCategories, opts.Device) {
+			return fmt.Errorf("invalid device '%s' (valid: %s)", opts.Device, strings.Join(xcstrings.ValidDeviceCategories, ", "))
+		}
+	}
+	for _, lc := range langColumns {
+		if lc.lang == sourceLang && lc.valueIdx < len(row.record) && row.record[lc.valueIdx] != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot create key %s without a source-language (%s) value", row.key, sourceLang)
+}
+
+// createCSVKeyValue writes the source-language value of a row of a key
+// missing from the catalog the way `set` does, creating the key with
+// extractionState "manual" on its first row.
+func createCSVKeyValue(xc *xcstrings.XCStrings, summary *importSummary, row csvImportRow, langColumns []langColumn) error {
+	value := ""
+	for _, lc := range langColumns {
+		if lc.lang == xc.SourceLanguage {
+			value = row.record[lc.valueIdx]
+		}
+	}
+
+	var opts xcstrings.VariationOptions
+	if row.variationPath != "" {
+		opts, _ = parseVariationOpts(splitPath(row.variationPath))
+	}
+	result, _, err := applySetTranslation(xc, row.key, xc.SourceLanguage, value, opts.Plural, opts.Device, "manual", nil, "", 0, "")
+	if err != nil {
+		return err
+	}
+	if result.Action == "created" {
+		summary.keysCreated++
+		summary.keyChanges = append(summary.keyChanges, importKeyChange{Key: row.key, Action: "created"})
+	}
+	newValue, newState := unitValueAndState(currentTranslationUnit(xc, row.key, xc.SourceLanguage, row.variationPath))
+	summary.record(importChange{
+		Key: row.key, Language: xc.SourceLanguage, Path: row.variationPath, Action: "created",
+		NewValue: newValue, NewState: newState,
+	})
+	return nil
+}
+
+// csvImportState returns the state to write for an imported CSV value. The
+// column's state is honored, except that "new" next to a changed value is
+// read as "translated": export writes "new" for untranslated strings, and a
+// translator filling in the value usually leaves the state column alone.
+func csvImportState(xc *xcstrings.XCStrings, key, lang, variationPath, value, state string) string {
+	if state != "new" {
+		return state
+	}
+	if unit := currentTranslationUnit(xc, key, lang, variationPath); unit == nil || unit.Value != value {
+		return "translated"
+	}
+	return state
+}
+
+// applyCSVKeyMetadata writes a key's imported comment and shouldTranslate
+// flag, tallying the key in summary when either changed. An empty comment
+// clears the key's comment only with clearEmpty; an empty shouldTranslate
+// means "true", as export writes it.
+func applyCSVKeyMetadata(xc *xcstrings.XCStrings, summary *importSummary, key string, meta *csvKeyMetadata, clearEmpty bool) {
+	def := xc.Strings[key]
+	var changes []importKeyChange
+
+	if meta.comment != "" || clearEmpty {
+		if def.Comment != meta.comment {
+			changes = append(changes, importKeyChange{Key: key, Action: "updated", Field: "comment", Old: def.Comment, New: meta.comment})
+			def.Comment = meta.comment
+		}
+	}
+
+	currentlyFalse := def.ShouldTranslate != nil && !*def.ShouldTranslate
+	switch {
+	case meta.shouldTranslate == "false" && !currentlyFalse:
+		shouldTranslate := false
+		def.ShouldTranslate = &shouldTranslate
+		changes = append(changes, importKeyChange{Key: key, Action: "updated", Field: "shouldTranslate", Old: "true", New: "false"})
+	case meta.shouldTranslate != "false" && currentlyFalse:
+		def.ShouldTranslate = nil
+		changes = append(changes, importKeyChange{Key: key, Action: "updated", Field: "shouldTranslate", Old: "false", New: "true"})
+	}
+
+	if len(changes) > 0 {
+		xc.Strings[key] = def
+		summary.keysUpdated++
+		summary.keyChanges = append(summary.keyChanges, changes...)
+	}
+}
+
+// applyImportValue applies a single imported value to the key/lang/variation
+// path and tallies the outcome in summary. It is shared by every import
+// format so that created/updated/unchanged/cleared/skipped mean the same
+// thing regardless of the file type. An empty value is treated as a request
+// to clear the translation, which only happens when clearEmpty is set.
+// When state is non-empty, it is written as the leaf's translation state
+// and a state change alone counts as an update; an empty state keeps the
+// historical behavior of marking written values "translated".
+func applyImportValue(xc *xcstrings.XCStrings, summary *importSummary, key, lang, variationPath, value, state string, clearEmpty bool) {
+	existingUnit := currentTranslationUnit(xc, key, lang, variationPath)
+	existing := existingUnit != nil
+	change := importChange{Key: key, Language: lang, Path: variationPath, NewValue: value}
+	change.OldValue, change.OldState = unitValueAndState(existingUnit)
+
+	if value == "" {
+		// Nothing to clear: no existing translation, or it's already empty.
+		if !existing || existingUnit.Value == "" {
+			summary.unchanged++
+			return
+		}
+		if !clearEmpty {
+			// A translation exists but --clear-empty wasn't requested: leave it untouched.
+			summary.unchanged++
+			return
+		}
+		if err := clearTranslation(xc, key, lang, variationPath); err == nil {
+			change.Action = "cleared"
+			summary.record(change)
+		}
+		return
+	}
+
+	if existing && existingUnit.Value == value && (state == "" || existingUnit.State == state) {
+		summary.unchanged++
+		return
+	}
+
+	if err := setTranslation(xc, key, lang, value, variationPath); err != nil {
+		summary.skip(key, lang, variationPath, skipWriteFailed, err.Error())
+		return
+	}
+	if unit := currentTranslationUnit(xc, key, lang, variationPath); unit != nil {
+		if state != "" {
+			unit.State = state
+		}
+		change.NewState = unit.State
+	}
+	change.Action = "created"
+	if existing {
+		change.Action = "updated"
+	}
+	summary.record(change)
+}
+
 // langColumn represents a language column pair in the CSV header.
 type langColumn struct {
 	lang     string
@@ -272,39 +578,46 @@ func parseKeyBracket(raw string) (string, string) {
 	return raw[:idx], raw[idx+1 : end]
 }
 
-// currentTranslationValue returns the existing translation value for a key/lang/variation
-// path in the catalog, and whether a translation is currently present at all. It reuses
-// resolveVariationUnit (defined in export.go) so lookup and CSV flattening stay in sync.
-func currentTranslationValue(xc *xcstrings.XCStrings, key, lang, variationPath string) (string, bool) {
+// currentTranslationUnit returns the existing string unit for a key/lang/variation
+// path in the catalog, or nil when no translation is present at all. It reuses
+// resolveVariationUnit (defined in export.go) so lookup and CSV flattening stay
+// in sync.
+func currentTranslationUnit(xc *xcstrings.XCStrings, key, lang, variationPath string) *xcstrings.StringUnit {
 	def, exists := xc.Strings[key]
 	if !exists {
-		return "", false
+		return nil
 	}
 	loc, exists := def.Localizations[lang]
 	if !exists {
-		return "", false
+		return nil
 	}
+	return localizationUnit(loc, variationPath)
+}
 
+// localizationUnit returns the string unit at variationPath within loc: the
+// plain stringUnit for an empty path, otherwise the variation or
+// substitution leaf. Returns nil when there is no unit at that path.
+func localizationUnit(loc xcstrings.Localization, variationPath string) *xcstrings.StringUnit {
 	if variationPath == "" {
-		if loc.StringUnit == nil {
-			return "", false
-		}
-		return loc.StringUnit.Value, true
+		return loc.StringUnit
 	}
-
-	unit := resolveVariationUnit(loc, variationPath)
-	if unit == nil {
-		return "", false
-	}
-	return unit.Value, true
+	return resolveVariationUnit(loc, variationPath)
 }
 
 // setTranslation sets a translation value, handling both simple and variation keys.
 func setTranslation(xc *xcstrings.XCStrings, key, lang, value, variationPath string) error {
 	if variationPath == "" {
-		if _, exists := xc.Strings[key]; !exists {
+		def, exists := xc.Strings[key]
+		if !exists {
 			return fmt.Errorf("key '%s' not found", key)
 		}
+		// A host string routing arguments through substitutions is updated
+		// in place: SetTranslation would drop the substitutions along with it.
+		if loc, ok := def.Localizations[lang]; ok && len(loc.Substitutions) > 0 {
+			loc.StringUnit = &xcstrings.StringUnit{State: "translated", Value: value}
+			def.Localizations[lang] = loc
+			return nil
+		}
 		_, err := xc.SetTranslation(key, lang, value, "")
 		return err
 	}
@@ -0,0 +1,149 @@
+package command
+
+import (
+	"fmt"
+	"log"
+
+	"xckit/xcstrings"
+)
+
+// Skip reasons reported by `import`, stable for scripts reading --json.
+const (
+	// skipMissingKey: the key isn't in the catalog (--on-missing-key skip).
+	skipMissingKey = "missing-key"
+	// skipWriteFailed: the value couldn't be written at its path, e.g. an
+	// unknown substitution or a plural path on a plain string.
+	skipWriteFailed = "write-failed"
+	// skipMsgidPlural: a PO msgid_plural message, which has no catalog path.
+	skipMsgidPlural = "msgid-plural"
+	// skipInvalidStringsdict: a .stringsdict entry that can't be read as
+	// plural or device variations.
+	skipInvalidStringsdict = "invalid-stringsdict"
+)
+
+// importChange is one translation written, cleared or skipped by an import.
+// Language is empty for a CSV row skipped as a whole, and Path is the
+// variation path ("" for the plain string unit).
+type importChange struct {
+	Key      string `json:"key"`
+	Language string `json:"language,omitempty"`
+	Path     string `json:"path"`
+	Action   string `json:"action"` // created, updated, cleared or skipped
+	OldValue string `json:"oldValue,omitempty"`
+	NewValue string `json:"newValue,omitempty"`
+	OldState string `json:"oldState,omitempty"`
+	NewState string `json:"newState,omitempty"`
+	Reason   string `json:"reason,omitempty"` // skipped only, one of the skip* constants
+	Message  string `json:"message,omitempty"`
+}
+
+// importKeyChange is a key created by --on-missing-key create, or a change
+// to a key's comment or shouldTranslate flag (CSV only).
+type importKeyChange struct {
+	Key    string `json:"key"`
+	Action string `json:"action"`          // created or updated
+	Field  string `json:"field,omitempty"` // comment or shouldTranslate, for updates
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// importJSONOutput is the document printed by `import --json`.
+type importJSONOutput struct {
+	DryRun  bool              `json:"dryRun"`
+	Changes []importChange    `json:"changes"`
+	Keys    []importKeyChange `json:"keys"`
+	Summary struct {
+		Created     int `json:"created"`
+		Updated     int `json:"updated"`
+		Unchanged   int `json:"unchanged"`
+		Cleared     int `json:"cleared"`
+		Skipped     int `json:"skipped"`
+		KeysCreated int `json:"keysCreated"`
+		KeysUpdated int `json:"keysUpdated"`
+	} `json:"summary"`
+}
+
+// record tallies a written or cleared translation and adds it to the
+// change log.
+func (s *importSummary) record(change importChange) {
+	switch change.Action {
+	case "created":
+		s.created++
+	case "updated":
+		s.updated++
+	case "cleared":
+		s.cleared++
+	}
+	s.changes = append(s.changes, change)
+}
+
+// skip tallies a translation that wasn't imported, with one of the skip*
+// reasons. Skips other than missing keys are also logged as warnings, as
+// they point at a problem in the input file.
+func (s *importSummary) skip(key, lang, path, reason, message string) {
+	if reason != skipMissingKey {
+		log.Printf("Warning: skipping key %q lang %q: %s", key, lang, message)
+	}
+	s.skipped++
+	s.changes = append(s.changes, importChange{Key: key, Language: lang, Path: path, Action: "skipped", Reason: reason, Message: message})
+}
+
+// jsonOutput returns the summary as the `import --json` document.
+func (s *importSummary) jsonOutput(dryRun bool) importJSONOutput {
+	out := importJSONOutput{DryRun: dryRun, Changes: s.changes, Keys: s.keyChanges}
+	if out.Changes == nil {
+		out.Changes = []importChange{}
+	}
+	if out.Keys == nil {
+		out.Keys = []importKeyChange{}
+	}
+	out.Summary.Created = s.created
+	out.Summary.Updated = s.updated
+	out.Summary.Unchanged = s.unchanged
+	out.Summary.Cleared = s.cleared
+	out.Summary.Skipped = s.skipped
+	out.Summary.KeysCreated = s.keysCreated
+	out.Summary.KeysUpdated = s.keysUpdated
+	return out
+}
+
+// reportLines renders the change log for --report: key-level changes
+// first, then one line per translation in input order.
+func (s *importSummary) reportLines() []string {
+	lines := make([]string, 0, len(s.keyChanges)+len(s.changes))
+	for _, kc := range s.keyChanges {
+		if kc.Action == "created" {
+			lines = append(lines, fmt.Sprintf("created %s (new key)", kc.Key))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("updated %s %s: %q -> %q", kc.Key, kc.Field, kc.Old, kc.New))
+	}
+	for _, c := range s.changes {
+		label := c.Key
+		if c.Path != "" {
+			label += "[" + c.Path + "]"
+		}
+		if c.Language != "" {
+			label += " (" + c.Language + ")"
+		}
+		if c.Action == "skipped" {
+			lines = append(lines, fmt.Sprintf("skipped %s: %s: %s", label, c.Reason, c.Message))
+			continue
+		}
+		line := fmt.Sprintf("%s %s: %q -> %q", c.Action, label, c.OldValue, c.NewValue)
+		if c.OldState != c.NewState && c.OldState != "" && c.NewState != "" {
+			line += fmt.Sprintf(" [%s -> %s]", c.OldState, c.NewState)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// unitValueAndState returns a string unit's value and state, or empty
+// strings when there is no unit.
+func unitValueAndState(unit *xcstrings.StringUnit) (string, string) {
+	if unit == nil {
+		return "", ""
+	}
+	return unit.Value, unit.State
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+// reportCSV updates a state and a comment of csvMetadataFixture, creates a
+// plural form and names a missing key.
+const reportCSV = "key,comment,shouldTranslate,en:state,en,ja:state,ja\n" +
+	"greeting,Shown on first launch,,translated,Hello,needs_review,こんにちは\n" +
+	"item_count[plural.one],,,translated,%lld item,translated,%lld 個\n" +
+	"missing,,,,,translated,x\n"
+
+func TestImportCommand_Execute_JSONReport(t *testing.T) {
+	xcPath := test.TempFile(t, "test.xcstrings", csvMetadataFixture)
+	csvPath := test.TempFile(t, "translations.csv", "key,comment,shouldTranslate,en:state,en,ja:state,ja\n"+
+		"greeting,,,translated,Hello,,\n"+
+		"item_count[plural.one],,,translated,%lld item,translated,%lld 個\n"+
+		"item_count[plural.other],,,translated,%lld items,new,%lld 個\n"+
+		"missing,,,,,translated,x\n")
+
+	output, status := runCommand(t, &ImportCommand{}, "-f", xcPath, "--format", "csv", "--dry-run", "--json", "--clear-empty", csvPath)
+	test.AssertEqual(t, status, 0)
+
+	var out importJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertEqual(t, out.DryRun, true)
+	want := []importChange{
+		{Key: "greeting", Language: "ja", Action: "cleared", OldValue: "こんにちは", OldState: "translated"},
+		{Key: "item_count", Language: "ja", Path: "plural.one", Action: "created", NewValue: "%lld 個", NewState: "translated"},
+		{Key: "item_count", Language: "ja", Path: "plural.other", Action: "updated", NewValue: "%lld 個", OldState: "new", NewState: "translated"},
+		{Key: "missing", Action: "skipped", Reason: skipMissingKey, Message: "key not found: missing"},
+	}
+	if !slices.Equal(out.Changes, want) {
+		t.Errorf("changes:\n got %+v\nwant %+v", out.Changes, want)
+	}
+	wantKeys := []importKeyChange{
+		{Key: "greeting", Action: "updated", Field: "comment", Old: "Shown on launch"},
+	}
+	if !slices.Equal(out.Keys, wantKeys) {
+		t.Errorf("keys:\n got %+v\nwant %+v", out.Keys, wantKeys)
+	}
+	test.AssertEqual(t, out.Summary.Created, 1)
+	test.AssertEqual(t, out.Summary.Updated, 1)
+	test.AssertEqual(t, out.Summary.Cleared, 1)
+	test.AssertEqual(t, out.Summary.Skipped, 1)
+	test.AssertEqual(t, out.Summary.KeysUpdated, 1)
+
+	// A dry run leaves the catalog alone.
+	xc, err := xcstrings.Load(xcPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["greeting"].Localizations["ja"].StringUnit.Value, "こんにちは")
+}
+
+func TestImportCommand_Execute_Report(t *testing.T) {
+	xcPath := test.TempFile(t, "test.xcstrings", csvMetadataFixture)
+	csvPath := test.TempFile(t, "translations.csv", reportCSV)
+
+	output, status := runCommand(t, &ImportCommand{}, "-f", xcPath, "--format", "csv", "--report", csvPath)
+	test.AssertEqual(t, status, 0)
+	for _, want := range []string{
+		`updated greeting comment: "Shown on launch" -> "Shown on first launch"`,
+		`updated greeting (ja): "こんにちは" -> "こんにちは" [translated -> needs_review]`,
+		`created item_count[plural.one] (ja): "" -> "%lld 個"`,
+		`skipped missing: missing-key: key not found: missing`,
+		"Imported: 1 created, 1 updated, 0 unchanged, 0 cleared, 1 skipped",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in report, got:\n%s", want, output)
+		}
+	}
+
+	// Without --report only the tally is printed.
+	output, status = runCommand(t, &ImportCommand{}, "-f", xcPath, "--format", "csv", csvPath)
+	test.AssertEqual(t, status, 0)
+	if strings.Contains(output, "greeting") {
+		t.Errorf("expected no change log without --report, got:\n%s", output)
+	}
+}
@@ -913,6 +913,228 @@ func TestImportCSV_PartialEdits_CountsUpdatedAndUnchangedSeparately(t *testing.T
 	test.AssertEqual(t, loc.StringUnit.Value, "またね")
 }
 
+const csvMetadataFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"greeting": {
+			"comment": "Shown on launch",
+			"localizations": {
+				"en": {"stringUnit": {"state": "translated", "value": "Hello"}},
+				"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}}
+			}
+		},
+		"item_count": {
+			"localizations": {
+				"en": {"variations": {"plural": {
+					"one": {"stringUnit": {"state": "translated", "value": "%lld item"}},
+					"other": {"stringUnit": {"state": "translated", "value": "%lld items"}}
+				}}},
+				"ja": {"variations": {"plural": {
+					"other": {"stringUnit": {"state": "new", "value": ""}}
+				}}}
+			}
+		},
+		"brand": {
+			"shouldTranslate": false,
+			"localizations": {
+				"en": {"stringUnit": {"state": "translated", "value": "xckit"}}
+			}
+		}
+	},
+	"version": "1.0"
+}`
+
+func TestImportCSV_StateCommentAndShouldTranslate(t *testing.T) {
+	xc, err := xcstrings.Load(test.TempFile(t, "test.xcstrings", csvMetadataFixture))
+	test.AssertNoError(t, err)
+
+	csvContent := "key,comment,shouldTranslate,en:state,en,ja:state,ja\n" +
+		// A state change alone is an update.
+		"greeting,Shown on first launch,,translated,Hello,needs_review,こんにちは\n" +
+		// "new" next to a filled-in value means translated.
+		"item_count[plural.one],Number of items,,translated,%lld item,,\n" +
+		"item_count[plural.other],,,translated,%lld items,new,%lld 個\n" +
+		"brand,,TRUE,translated,xckit,,\n"
+
+	summary, err := importCSV(strings.NewReader(csvContent), xc, "skip", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.updated, 2)
+	test.AssertEqual(t, summary.keysUpdated, 3)
+
+	greeting := xc.Strings["greeting"]
+	test.AssertEqual(t, greeting.Localizations["ja"].StringUnit.State, "needs_review")
+	test.AssertEqual(t, greeting.Comment, "Shown on first launch")
+
+	itemCount := xc.Strings["item_count"]
+	test.AssertEqual(t, itemCount.Comment, "Number of items")
+	other := itemCount.Localizations["ja"].Variations.Plural["other"].StringUnit
+	test.AssertEqual(t, other.Value, "%lld 個")
+	test.AssertEqual(t, other.State, "translated")
+
+	if xc.Strings["brand"].ShouldTranslate != nil {
+		t.Error("shouldTranslate TRUE should clear the false flag")
+	}
+
+	csvContent = "key,comment,shouldTranslate,en:state,en,ja:state,ja\n" +
+		"greeting,,false,translated,Hello,needs_review,こんにちは\n"
+	summary, err = importCSV(strings.NewReader(csvContent), xc, "skip", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.keysUpdated, 1)
+	greeting = xc.Strings["greeting"]
+	test.AssertEqual(t, *greeting.ShouldTranslate, false)
+	// An empty comment only clears with --clear-empty.
+	test.AssertEqual(t, greeting.Comment, "Shown on first launch")
+
+	_, err = importCSV(strings.NewReader(csvContent), xc, "skip", true)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["greeting"].Comment, "")
+}
+
+func TestImportCSV_ExportRoundTripKeepsStateAndMetadata(t *testing.T) {
+	xc, err := xcstrings.Load(test.TempFile(t, "test.xcstrings", csvMetadataFixture))
+	test.AssertNoError(t, err)
+
+	var buf bytes.Buffer
+	test.AssertNoError(t, writeCSV(&buf, xc))
+	summary, err := importCSV(&buf, xc, "skip", true)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.created+summary.updated+summary.cleared+summary.keysUpdated, 0)
+
+	test.AssertEqual(t, xc.Strings["item_count"].Localizations["ja"].Variations.Plural["other"].StringUnit.State, "new")
+	test.AssertEqual(t, *xc.Strings["brand"].ShouldTranslate, false)
+	test.AssertEqual(t, xc.Strings["greeting"].Comment, "Shown on launch")
+}
+
+func TestImportCSV_InvalidRowsAreReportedTogether(t *testing.T) {
+	xcPath := test.TempFile(t, "test.xcstrings", csvMetadataFixture)
+	xc, err := xcstrings.Load(xcPath)
+	test.AssertNoError(t, err)
+
+	csvContent := "key,comment,shouldTranslate,en:state,en,ja:state,ja\n" +
+		"greeting,,,translated,Hello,reviewed,やあ\n" +
+		"brand,,no,translated,xckit,,\n" +
+		"item_count[plural.one],Count,,translated,%lld item,,\n" +
+		"item_count[plural.other],Items,,translated,%lld items,translated,%lld 個\n" +
+		"greeting,\"Shown\non launch\",,translated,Hello,,\n" +
+		"missing,,,,,translated,x\n"
+
+	_, err = importCSV(strings.NewReader(csvContent), xc, "error", false)
+	if err == nil {
+		t.Fatal("expected an error for invalid rows")
+	}
+	for _, want := range []string{
+		"4 invalid CSV rows, nothing was imported",
+		"line 2: invalid ja:state 'reviewed' (valid: translated, needs_review, new, stale)",
+		"line 3: invalid shouldTranslate 'no' (valid: true, false or empty)",
+		"line 5: comment of key item_count conflicts with the one on line 4",
+		"line 8: key not found: missing",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got:\n%v", want, err)
+		}
+	}
+	test.AssertEqual(t, xc.Strings["greeting"].Localizations["ja"].StringUnit.Value, "こんにちは")
+}
+
+func TestImportCSV_OnMissingKeyCreate(t *testing.T) {
+	xc, err := xcstrings.Load(test.TempFile(t, "test.xcstrings", csvMetadataFixture))
+	test.AssertNoError(t, err)
+
+	csvContent := "key,comment,shouldTranslate,en:state,en,ja:state,ja\n" +
+		"welcome,Shown on launch,,,Welcome,translated,ようこそ\n" +
+		"photo_count[plural.one],Photo counter,,,%lld photo,,\n" +
+		"photo_count[plural.other],,,,%lld photos,translated,%lld 枚\n" +
+		"app_name,,false,,xckit,,\n"
+
+	summary, err := importCSV(strings.NewReader(csvContent), xc, "create", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.keysCreated, 3)
+	test.AssertEqual(t, summary.created, 6)
+
+	welcome := xc.Strings["welcome"]
+	test.AssertEqual(t, welcome.ExtractionState, "manual")
+	test.AssertEqual(t, welcome.Comment, "Shown on launch")
+	test.AssertEqual(t, welcome.Localizations["en"].StringUnit.Value, "Welcome")
+	test.AssertEqual(t, welcome.Localizations["ja"].StringUnit.Value, "ようこそ")
+
+	photos := xc.Strings["photo_count"]
+	test.AssertEqual(t, photos.ExtractionState, "manual")
+	test.AssertEqual(t, photos.Comment, "Photo counter")
+	en := photos.Localizations["en"]
+	if en.StringUnit != nil || en.Variations == nil {
+		t.Fatalf("expected photo_count to be a plural variation, got %+v", en)
+	}
+	test.AssertEqual(t, en.Variations.Plural["one"].StringUnit.Value, "%lld photo")
+	test.AssertEqual(t, en.Variations.Plural["other"].StringUnit.Value, "%lld photos")
+	test.AssertEqual(t, photos.Localizations["ja"].Variations.Plural["other"].StringUnit.Value, "%lld 枚")
+
+	appName := xc.Strings["app_name"]
+	if appName.ShouldTranslate == nil || *appName.ShouldTranslate {
+		t.Errorf("expected app_name to have shouldTranslate false, got %v", appName.ShouldTranslate)
+	}
+}
+
+func TestImportCSV_OnMissingKeyCreateInvalidRows(t *testing.T) {
+	xc, err := xcstrings.Load(test.TempFile(t, "test.xcstrings", csvMetadataFixture))
+	test.AssertNoError(t, err)
+
+	csvContent := "key,comment,shouldTranslate,en:state,en,ja:state,ja\n" +
+		"no_source,,,,,translated,訳\n" +
+		"files[substitutions.n.plural.one],,,,%lld file,,\n" +
+		"photos[plural.several],,,,%lld photos,,\n" +
+		"banner[device.tv],,,,Banner,,\n"
+
+	_, err = importCSV(strings.NewReader(csvContent), xc, "create", false)
+	if err == nil {
+		t.Fatal("expected an error for rows that can't create their key")
+	}
+	for _, want := range []string{
+		"4 invalid CSV rows, nothing was imported",
+		"line 2: cannot create key no_source without a source-language (en) value",
+		"line 3: cannot create key files from a substitution row",
+		"line 4: invalid plural category 'several'",
+		"line 5: invalid device 'tv'",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got:\n%v", want, err)
+		}
+	}
+	if _, exists := xc.Strings["no_source"]; exists {
+		t.Error("no key should be created when a row is invalid")
+	}
+}
+
+func TestImportCommand_Execute_OnMissingKeyCreate(t *testing.T) {
+	xcPath := test.TempFile(t, "test.xcstrings", csvMetadataFixture)
+	csvPath := test.TempFile(t, "translations.csv", "key,comment,shouldTranslate,en:state,en,ja:state,ja\nwelcome,,,,Welcome,,ようこそ\n")
+
+	cmd := &ImportCommand{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", "csv", "--on-missing-key", "create", csvPath}))
+
+	output := captureOutput(func() {
+		status := cmd.Execute(context.Background(), flagSet)
+		test.AssertEqual(t, int(status), 0)
+	})
+	if !strings.Contains(output, "Created 1 keys") {
+		t.Errorf("expected the created keys to be reported, got: %s", output)
+	}
+
+	xc, err := xcstrings.Load(xcPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["welcome"].Localizations["ja"].StringUnit.Value, "ようこそ")
+
+	// Only CSV rows carry a source value to create the key from.
+	for _, format := range []string{"xliff", "po"} {
+		cmd := &ImportCommand{}
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		cmd.SetFlags(flagSet)
+		test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", format, "--on-missing-key", "create", csvPath}))
+		test.AssertEqual(t, int(cmd.Execute(context.Background(), flagSet)), 2)
+	}
+}
+
 func TestImportCommand_Execute_OutputFile(t *testing.T) {
 	xcContent := `{
 		"sourceLanguage": "en",
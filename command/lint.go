@@ -6,9 +6,11 @@ import (
 	"flag"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
+	"xckit/glossary"
 	"xckit/xcstrings"
 
 	"github.com/google/subcommands"
@@ -17,10 +19,12 @@ import (
 // LintCommand statically validates an .xcstrings catalog for common
 // inconsistencies (mismatched format specifiers, missing plural categories,
 // empty keys, literal newlines, language-code inconsistencies, and malformed
-// substitutions) that Xcode itself does not flag.
+// substitutions) that Xcode itself does not flag, and translations that
+// depart from a terminology glossary.
 type LintCommand struct {
 	XCStringsCommand
 	jsonOutput bool
+	glossary   string
 }
 
 func (*LintCommand) Name() string {
@@ -32,12 +36,13 @@ func (*LintCommand) Synopsis() string {
 }
 
 func (*LintCommand) Usage() string {
-	return "lint [-f file.xcstrings] [--json]: Detect format-specifier mismatches, missing plural categories, empty keys, literal newlines, language-code inconsistencies, and malformed substitutions\n"
+	return "lint [-f file.xcstrings] [--glossary <file>] [--json]: Detect format-specifier mismatches, missing plural categories, empty keys, literal newlines, language-code inconsistencies, malformed substitutions, and translations that don't follow the glossary\n"
 }
 
 func (c *LintCommand) SetFlags(f *flag.FlagSet) {
 	c.SetXCStringsFlags(f)
 	f.BoolVar(&c.jsonOutput, "json", false, "Output a single JSON document to stdout instead of human-readable text")
+	f.StringVar(&c.glossary, "glossary", "", "Glossary file (CSV or TBX) for the glossary rule (default: lint.glossary from the configuration file)")
 }
 
 // lintSeverity is the severity level of a lint issue.
@@ -48,6 +53,20 @@ const (
 	lintSeverityWarning lintSeverity = "warning"
 )
 
+// lintRules lists every rule name, for validating the project
+// configuration's lint.rules.
+var lintRules = []string{
+	"empty-key",
+	"format-specifier",
+	"glossary",
+	"language-consistency",
+	"literal-newline",
+	"plural-missing-category",
+	"plural-missing-other",
+	"plural-unused-category",
+	"substitution-structure",
+}
+
 // lintIssue is a single detected inconsistency.
 type lintIssue struct {
 	Rule     string
@@ -59,28 +78,104 @@ type lintIssue struct {
 }
 
 func (c *LintCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
-	xcs, err := c.LoadXCStrings()
-	if err != nil {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
-		return subcommands.ExitFailure
+	if c.config != nil {
+		for rule := range c.config.Lint.Rules {
+			if !slices.Contains(lintRules, rule) {
+				_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s: unknown lint rule %q (rules: %s)\n", c.config.Path, rule, strings.Join(lintRules, ", "))
+				return subcommands.ExitUsageError
+			}
+		}
 	}
 
-	issues := runLint(xcs)
-
-	if c.jsonOutput {
-		return c.printJSON(issues)
+	glossaryPath := c.glossary
+	if glossaryPath == "" && c.config != nil {
+		glossaryPath = c.config.GlossaryPath()
+	}
+	var terms *glossary.Glossary
+	if glossaryPath != "" {
+		var err error
+		if terms, err = glossary.Load(glossaryPath); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
 	}
 
-	if len(issues) == 0 {
-		fmt.Println("No issues found")
+	var all []lintIssue
+	var catalogs []lintCatalogJSON
+	status := c.forEachCatalog(c.jsonOutput, func() subcommands.ExitStatus {
+		xcs, err := c.LoadXCStrings()
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+
+		issues := c.applyRuleSettings(runLint(xcs, terms))
+		all = append(all, issues...)
+		catalogs = append(catalogs, lintCatalogJSON{File: c.filePath, Issues: lintJSONIssues(issues)})
+		if c.jsonOutput {
+			return subcommands.ExitSuccess
+		}
+
+		if len(issues) == 0 {
+			fmt.Println("No issues found")
+			return subcommands.ExitSuccess
+		}
+		for _, issue := range issues {
+			fmt.Println(formatLintIssue(issue))
+		}
 		return subcommands.ExitSuccess
+	})
+	if status != subcommands.ExitSuccess {
+		return status
 	}
 
-	for _, issue := range issues {
-		fmt.Println(formatLintIssue(issue))
+	errors, warnings := 0, 0
+	for _, issue := range all {
+		if issue.Severity == lintSeverityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+
+	if c.jsonOutput {
+		var out any = lintJSONOutput{Issues: catalogs[0].Issues}
+		if len(catalogs) > 1 {
+			multi := lintMultiJSONOutput{Catalogs: catalogs}
+			multi.Total.Errors = errors
+			multi.Total.Warnings = warnings
+			out = multi
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		fmt.Println(string(data))
+	} else if len(catalogs) > 1 {
+		fmt.Printf("\n== Total: %d errors, %d warnings in %d catalogs ==\n", errors, warnings, len(catalogs))
 	}
 
-	return exitStatusForLintIssues(issues)
+	return exitStatusForLintIssues(all)
+}
+
+// applyRuleSettings drops the issues of rules the project configuration
+// turns off and applies its severity overrides.
+func (c *LintCommand) applyRuleSettings(issues []lintIssue) []lintIssue {
+	if c.config == nil || len(c.config.Lint.Rules) == 0 {
+		return issues
+	}
+	kept := issues[:0]
+	for _, issue := range issues {
+		switch setting := c.config.Lint.Rules[issue.Rule]; setting {
+		case "off":
+			continue
+		case "error", "warning":
+			issue.Severity = lintSeverity(setting)
+		}
+		kept = append(kept, issue)
+	}
+	return kept
 }
 
 // exitStatusForLintIssues returns ExitFailure when at least one error-level
@@ -125,10 +220,27 @@ type lintJSONIssue struct {
 	Message  string `json:"message"`
 }
 
-func (c *LintCommand) printJSON(issues []lintIssue) subcommands.ExitStatus {
-	out := lintJSONOutput{Issues: make([]lintJSONIssue, 0, len(issues))}
+// lintCatalogJSON is a single catalog's entry in multi-catalog `lint
+// --json` output.
+type lintCatalogJSON struct {
+	File   string          `json:"file"`
+	Issues []lintJSONIssue `json:"issues"`
+}
+
+// lintMultiJSONOutput is the document printed by `lint --json` for several
+// catalogs.
+type lintMultiJSONOutput struct {
+	Catalogs []lintCatalogJSON `json:"catalogs"`
+	Total    struct {
+		Errors   int `json:"errors"`
+		Warnings int `json:"warnings"`
+	} `json:"total"`
+}
+
+func lintJSONIssues(issues []lintIssue) []lintJSONIssue {
+	out := make([]lintJSONIssue, 0, len(issues))
 	for _, issue := range issues {
-		out.Issues = append(out.Issues, lintJSONIssue{
+		out = append(out, lintJSONIssue{
 			Rule:     issue.Rule,
 			Severity: string(issue.Severity),
 			Key:      issue.Key,
@@ -137,24 +249,21 @@ func (c *LintCommand) printJSON(issues []lintIssue) subcommands.ExitStatus {
 			Message:  issue.Message,
 		})
 	}
-
-	data, err := json.MarshalIndent(out, "", "  ")
-	if err != nil {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
-		return subcommands.ExitFailure
-	}
-	fmt.Println(string(data))
-	return exitStatusForLintIssues(issues)
+	return out
 }
 
 // runLint walks the whole catalog and returns every detected issue, sorted
-// by key, then language, then path, then rule for deterministic output.
-func runLint(xcs *xcstrings.XCStrings) []lintIssue {
+// by key, then language, then path, then rule for deterministic output. The
+// glossary rule runs only with a glossary.
+func runLint(xcs *xcstrings.XCStrings, terms *glossary.Glossary) []lintIssue {
 	var issues []lintIssue
 
 	for key, def := range xcs.Strings {
 		issues = append(issues, lintEmptyKey(key)...)
 		issues = append(issues, lintKey(xcs, key, def)...)
+		if terms != nil {
+			issues = append(issues, lintGlossary(xcs, key, def, terms)...)
+		}
 	}
 
 	issues = append(issues, lintLanguageConsistency(xcs)...)
@@ -190,7 +299,8 @@ func lintEmptyKey(key string) []lintIssue {
 }
 
 // lintKey runs the per-localization rules (plural-missing-other,
-// literal-newline, substitution-structure, format-specifier) for a single key.
+// plural-missing-category, plural-unused-category, literal-newline,
+// substitution-structure, format-specifier) for a single key.
 func lintKey(xcs *xcstrings.XCStrings, key string, def xcstrings.StringDefinition) []lintIssue {
 	var issues []lintIssue
 
@@ -203,6 +313,7 @@ func lintKey(xcs *xcstrings.XCStrings, key string, def xcstrings.StringDefinitio
 
 	for lang, loc := range def.Localizations {
 		issues = append(issues, lintPluralMissingOther(key, lang, loc)...)
+		issues = append(issues, lintPluralCategories(key, lang, loc)...)
 		issues = append(issues, lintSubstitutionStructure(key, lang, loc)...)
 
 		leaves := collectLintLeaves(loc)
@@ -249,6 +360,52 @@ func lintKey(xcs *xcstrings.XCStrings, key string, def xcstrings.StringDefinitio
 	return issues
 }
 
+// lintGlossary flags translated leaves whose source leaf uses a glossary
+// term but which lack its approved rendering or use a forbidden one.
+func lintGlossary(xcs *xcstrings.XCStrings, key string, def xcstrings.StringDefinition, terms *glossary.Glossary) []lintIssue {
+	srcLoc := def.Localizations[xcs.SourceLanguage]
+
+	var issues []lintIssue
+	for lang, loc := range def.Localizations {
+		if lang == xcs.SourceLanguage {
+			continue
+		}
+		for _, leaf := range collectLintLeaves(loc) {
+			if leaf.State != "translated" {
+				continue
+			}
+			// xliffSourceText addresses the host string as "".
+			path := leaf.Path
+			if path == "stringUnit" {
+				path = ""
+			}
+			source := xliffSourceText(key, srcLoc, path)
+			for _, v := range terms.Check(xcs.SourceLanguage, source, lang, leaf.Value) {
+				approved := make([]string, len(v.Approved))
+				for i, a := range v.Approved {
+					approved[i] = fmt.Sprintf("%q", a)
+				}
+				msg := fmt.Sprintf("source uses %q but the translation doesn't use %s", v.Term, strings.Join(approved, " or "))
+				if v.Forbidden != "" {
+					msg = fmt.Sprintf("translation uses %q, which the glossary forbids for %q", v.Forbidden, v.Term)
+					if len(approved) > 0 {
+						msg += "; use " + strings.Join(approved, " or ")
+					}
+				}
+				issues = append(issues, lintIssue{
+					Rule:     "glossary",
+					Severity: lintSeverityWarning,
+					Key:      key,
+					Language: lang,
+					Path:     leaf.Path,
+					Message:  msg,
+				})
+			}
+		}
+	}
+	return issues
+}
+
 func hasLiteralNewline(s string) bool {
 	return strings.ContainsAny(s, "\n\r")
 }
@@ -318,7 +475,7 @@ func collectVariationLeaves(v *xcstrings.Variations, prefix string) []lintLeaf {
 	return leaves
 }
 
-func sortedKeys(m map[string]*xcstrings.VariationValue) []string {
+func sortedKeys[V any](m map[string]V) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
 		keys = append(keys, k)
@@ -327,49 +484,134 @@ func sortedKeys(m map[string]*xcstrings.VariationValue) []string {
 	return keys
 }
 
-// lintPluralMissingOther walks every Plural variation map reachable from a
-// Localization (at any nesting depth, including inside substitutions) and
-// flags the ones missing the mandatory "other" category.
-func lintPluralMissingOther(key, lang string, l xcstrings.Localization) []lintIssue {
-	var issues []lintIssue
+// forEachPluralVariation calls fn for every Plural variation map reachable
+// from a Localization, at any nesting depth and including inside
+// substitutions, with the path of the "plural" node.
+func forEachPluralVariation(l xcstrings.Localization, fn func(path string, plural map[xcstrings.PluralCategory]*xcstrings.VariationValue)) {
 	if l.Variations != nil {
-		issues = append(issues, pluralMissingOtherInVariations(key, lang, l.Variations, "")...)
+		forEachPluralVariationIn(l.Variations, "", fn)
 	}
-	for name, sub := range l.Substitutions {
-		issues = append(issues, pluralMissingOtherInVariations(key, lang, &sub.Variations, "substitutions."+name)...)
+	for _, name := range sortedKeys(l.Substitutions) {
+		sub := l.Substitutions[name]
+		forEachPluralVariationIn(&sub.Variations, "substitutions."+name, fn)
 	}
-	return issues
 }
 
-func pluralMissingOtherInVariations(key, lang string, v *xcstrings.Variations, prefix string) []lintIssue {
-	var issues []lintIssue
+func forEachPluralVariationIn(v *xcstrings.Variations, prefix string, fn func(path string, plural map[xcstrings.PluralCategory]*xcstrings.VariationValue)) {
 	if v.Plural != nil {
-		if _, ok := v.Plural["other"]; !ok {
-			issues = append(issues, lintIssue{
-				Rule:     "plural-missing-other",
-				Severity: lintSeverityError,
-				Key:      key,
-				Language: lang,
-				Path:     joinLintPath(prefix, "plural"),
-				Message:  "plural variation is missing the required 'other' category",
-			})
-		}
+		fn(joinLintPath(prefix, "plural"), v.Plural)
 		for _, cat := range sortedKeys(v.Plural) {
 			vv := v.Plural[cat]
 			if vv != nil && vv.Variations != nil {
-				issues = append(issues, pluralMissingOtherInVariations(key, lang, vv.Variations, joinLintPath(prefix, "plural."+cat))...)
+				forEachPluralVariationIn(vv.Variations, joinLintPath(prefix, "plural."+cat), fn)
 			}
 		}
 	}
 	for _, dev := range sortedKeys(v.Device) {
 		vv := v.Device[dev]
 		if vv != nil && vv.Variations != nil {
-			issues = append(issues, pluralMissingOtherInVariations(key, lang, vv.Variations, joinLintPath(prefix, "device."+dev))...)
+			forEachPluralVariationIn(vv.Variations, joinLintPath(prefix, "device."+dev), fn)
 		}
 	}
+}
+
+// lintPluralMissingOther flags plural variations missing the mandatory
+// "other" category.
+func lintPluralMissingOther(key, lang string, l xcstrings.Localization) []lintIssue {
+	var issues []lintIssue
+	forEachPluralVariation(l, func(path string, plural map[xcstrings.PluralCategory]*xcstrings.VariationValue) {
+		if _, ok := plural["other"]; !ok {
+			issues = append(issues, lintIssue{
+				Rule:     "plural-missing-other",
+				Severity: lintSeverityError,
+				Key:      key,
+				Language: lang,
+				Path:     path,
+				Message:  "plural variation is missing the required 'other' category",
+			})
+		}
+	})
+	return issues
+}
+
+// lintPluralCategories checks plural variations against the language's CLDR
+// plural rules: plural-missing-category flags categories the language needs
+// but the variation lacks ("other" is left to plural-missing-other), only
+// warning about those selected just for exact millions, and
+// plural-unused-category flags categories the language never selects, such
+// as "one" in Japanese. Languages without known rules are skipped.
+func lintPluralCategories(key, lang string, l xcstrings.Localization) []lintIssue {
+	required, ok := xcstrings.RequiredPluralCategories(lang)
+	if !ok {
+		return nil
+	}
+
+	var issues []lintIssue
+	forEachPluralVariation(l, func(path string, plural map[xcstrings.PluralCategory]*xcstrings.VariationValue) {
+		var missing, rare []string
+		for _, cat := range required {
+			if _, ok := plural[cat]; ok || cat == "other" {
+				continue
+			}
+			if xcstrings.PluralCategoryRare(lang, cat) {
+				rare = append(rare, cat)
+			} else {
+				missing = append(missing, cat)
+			}
+		}
+		if len(missing) > 0 {
+			issues = append(issues, lintIssue{
+				Rule:     "plural-missing-category",
+				Severity: lintSeverityError,
+				Key:      key,
+				Language: lang,
+				Path:     path,
+				Message:  fmt.Sprintf("plural variation is missing %s required by %s (%s)", quotedCategories(missing), lang, strings.Join(required, ", ")),
+			})
+		}
+		if len(rare) > 0 {
+			issues = append(issues, lintIssue{
+				Rule:     "plural-missing-category",
+				Severity: lintSeverityWarning,
+				Key:      key,
+				Language: lang,
+				Path:     path,
+				Message:  fmt.Sprintf("plural variation is missing %s, which %s uses only for exact millions and compact numbers (%s)", quotedCategories(rare), lang, strings.Join(required, ", ")),
+			})
+		}
+
+		var unused []string
+		for _, cat := range sortedKeys(plural) {
+			if !xcstrings.PluralCategoryUsed(lang, cat) {
+				unused = append(unused, cat)
+			}
+		}
+		if len(unused) > 0 {
+			issues = append(issues, lintIssue{
+				Rule:     "plural-unused-category",
+				Severity: lintSeverityWarning,
+				Key:      key,
+				Language: lang,
+				Path:     path,
+				Message:  fmt.Sprintf("plural variation has %s, which %s never uses (%s)", quotedCategories(unused), lang, strings.Join(required, ", ")),
+			})
+		}
+	})
 	return issues
 }
 
+// quotedCategories renders categories as "'few' and 'many'" for messages.
+func quotedCategories(categories []string) string {
+	quoted := make([]string, len(categories))
+	for i, cat := range categories {
+		quoted[i] = "'" + cat + "'"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
+
 // lintSubstitutionStructure flags malformed substitution definitions: a
 // missing argNum, an empty formatSpecifier, or a substitution whose name is
 // never referenced (as %#@name@) by any top-level string in the same
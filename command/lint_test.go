@@ -184,6 +184,97 @@ func TestLintCommand_PluralMissingOther(t *testing.T) {
 	}
 }
 
+func TestLintCommand_PluralCategories(t *testing.T) {
+	content := `{
+		"sourceLanguage": "en",
+		"strings": {
+			"%lld files": {
+				"localizations": {
+					"en": {"variations": {"plural": {
+						"one": {"stringUnit": {"state": "translated", "value": "%lld file"}},
+						"other": {"stringUnit": {"state": "translated", "value": "%lld files"}}
+					}}},
+					"ja": {"variations": {"plural": {
+						"one": {"stringUnit": {"state": "translated", "value": "%lld ファイル"}},
+						"other": {"stringUnit": {"state": "translated", "value": "%lld ファイル"}}
+					}}},
+					"ru": {"variations": {"device": {
+						"mac": {"variations": {"plural": {
+							"one": {"stringUnit": {"state": "translated", "value": "%lld файл"}},
+							"other": {"stringUnit": {"state": "translated", "value": "%lld файла"}}
+						}}}
+					}}},
+					"tlh": {"variations": {"plural": {
+						"two": {"stringUnit": {"state": "translated", "value": "%lld"}},
+						"other": {"stringUnit": {"state": "translated", "value": "%lld"}}
+					}}}
+				}
+			}
+		},
+		"version": "1.0"
+	}`
+	filePath := test.TempFile(t, "test.xcstrings", content)
+
+	output, status := runLintCommand(t, filePath, "--json")
+	test.AssertEqual(t, status, 1)
+
+	var doc lintJSONOutput
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("expected valid JSON output, got error %v; output: %s", err, output)
+	}
+	if len(doc.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d: %+v", len(doc.Issues), doc.Issues)
+	}
+
+	unused := doc.Issues[0]
+	test.AssertEqual(t, unused.Rule, "plural-unused-category")
+	test.AssertEqual(t, unused.Severity, "warning")
+	test.AssertEqual(t, unused.Language, "ja")
+	test.AssertEqual(t, unused.Path, "plural")
+
+	missing := doc.Issues[1]
+	test.AssertEqual(t, missing.Rule, "plural-missing-category")
+	test.AssertEqual(t, missing.Severity, "error")
+	test.AssertEqual(t, missing.Language, "ru")
+	test.AssertEqual(t, missing.Path, "device.mac.plural")
+	test.AssertEqual(t, missing.Message, "plural variation is missing 'few' and 'many' required by ru (one, few, many, other)")
+}
+
+func TestLintCommand_PluralMissingMillionsCategoryIsWarningOnly(t *testing.T) {
+	content := `{
+		"sourceLanguage": "en",
+		"strings": {
+			"%lld files": {
+				"localizations": {
+					"en": {"variations": {"plural": {
+						"one": {"stringUnit": {"state": "translated", "value": "%lld file"}},
+						"other": {"stringUnit": {"state": "translated", "value": "%lld files"}}
+					}}},
+					"fr": {"variations": {"plural": {
+						"one": {"stringUnit": {"state": "translated", "value": "%lld fichier"}},
+						"other": {"stringUnit": {"state": "translated", "value": "%lld fichiers"}}
+					}}}
+				}
+			}
+		},
+		"version": "1.0"
+	}`
+	filePath := test.TempFile(t, "test.xcstrings", content)
+
+	output, status := runLintCommand(t, filePath, "--json")
+	test.AssertEqual(t, status, 0)
+	var doc lintJSONOutput
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("expected valid JSON output, got error %v; output: %s", err, output)
+	}
+	if len(doc.Issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d: %+v", len(doc.Issues), doc.Issues)
+	}
+	test.AssertEqual(t, doc.Issues[0].Rule, "plural-missing-category")
+	test.AssertEqual(t, doc.Issues[0].Severity, "warning")
+	test.AssertEqual(t, doc.Issues[0].Message, "plural variation is missing 'many', which fr uses only for exact millions and compact numbers (one, many, other)")
+}
+
 func TestLintCommand_LiteralNewlineIsWarningOnly(t *testing.T) {
 	content := `{
 		"sourceLanguage": "en",
@@ -356,12 +447,18 @@ func TestLintCommand_PositionalSubstitutionReferenceIsClean(t *testing.T) {
 							"actions": {
 								"argNum": 1,
 								"formatSpecifier": "lld",
-								"variations": {"plural": {"other": {"stringUnit": {"state": "translated", "value": "%arg actions"}}}}
+								"variations": {"plural": {
+									"one": {"stringUnit": {"state": "translated", "value": "%arg action"}},
+									"other": {"stringUnit": {"state": "translated", "value": "%arg actions"}}
+								}}
 							},
 							"total": {
 								"argNum": 2,
 								"formatSpecifier": "lld",
-								"variations": {"plural": {"other": {"stringUnit": {"state": "translated", "value": "%arg"}}}}
+								"variations": {"plural": {
+									"one": {"stringUnit": {"state": "translated", "value": "%arg"}},
+									"other": {"stringUnit": {"state": "translated", "value": "%arg"}}
+								}}
 							}
 						}
 					},
@@ -371,12 +468,22 @@ func TestLintCommand_PositionalSubstitutionReferenceIsClean(t *testing.T) {
 							"actions": {
 								"argNum": 1,
 								"formatSpecifier": "lld",
-								"variations": {"plural": {"other": {"stringUnit": {"state": "translated", "value": "%arg действий"}}}}
+								"variations": {"plural": {
+									"one": {"stringUnit": {"state": "translated", "value": "%arg действие"}},
+									"few": {"stringUnit": {"state": "translated", "value": "%arg действия"}},
+									"many": {"stringUnit": {"state": "translated", "value": "%arg действий"}},
+									"other": {"stringUnit": {"state": "translated", "value": "%arg действия"}}
+								}}
 							},
 							"total": {
 								"argNum": 2,
 								"formatSpecifier": "lld",
-								"variations": {"plural": {"other": {"stringUnit": {"state": "translated", "value": "%arg"}}}}
+								"variations": {"plural": {
+									"one": {"stringUnit": {"state": "translated", "value": "%arg"}},
+									"few": {"stringUnit": {"state": "translated", "value": "%arg"}},
+									"many": {"stringUnit": {"state": "translated", "value": "%arg"}},
+									"other": {"stringUnit": {"state": "translated", "value": "%arg"}}
+								}}
 							}
 						}
 					}
@@ -468,3 +575,125 @@ func TestLintCommand_WarningOnlyExitsZero(t *testing.T) {
 	_, status := runLintCommand(t, filePath)
 	test.AssertEqual(t, status, 0)
 }
+
+func TestLintCommand_MultipleCatalogs(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"Clean.xcstrings": localizableFixture,
+		"Broken.xcstrings": `{
+			"sourceLanguage": "en",
+			"strings": {
+				"": {"localizations": {"en": {"stringUnit": {"state": "translated", "value": "x"}}}}
+			},
+			"version": "1.0"
+		}`,
+	})
+
+	output, status := runLintCommand(t, dir)
+	test.AssertEqual(t, status, 1)
+	for _, want := range []string{"No issues found", "[error] empty-key", "== Total: 1 errors, 0 warnings in 2 catalogs =="} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	output, _ = runLintCommand(t, dir, "--json")
+	var parsed lintMultiJSONOutput
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("output should be valid JSON, got error %v, output: %q", err, output)
+	}
+	test.AssertEqual(t, len(parsed.Catalogs), 2)
+	test.AssertEqual(t, parsed.Total.Errors, 1)
+	test.AssertEqual(t, parsed.Catalogs[0].Issues[0].Rule, "empty-key")
+}
+
+func TestLintCommand_Glossary(t *testing.T) {
+	content := `{
+		"sourceLanguage": "en",
+		"strings": {
+			"open": {
+				"localizations": {
+					"en": {"stringUnit": {"state": "translated", "value": "Open the workspace"}},
+					"ja": {"stringUnit": {"state": "translated", "value": "作業スペースを開く"}},
+					"de": {"stringUnit": {"state": "translated", "value": "Arbeitsbereich öffnen"}},
+					"fr": {"stringUnit": {"state": "needs_review", "value": "Ouvrir l'espace"}}
+				}
+			},
+			"workspaces": {
+				"localizations": {
+					"en": {"stringUnit": {"state": "translated", "value": "Workspaces are shared"}},
+					"ja": {"stringUnit": {"state": "translated", "value": "共有されています"}}
+				}
+			}
+		},
+		"version": "1.0"
+	}`
+	filePath := test.TempFile(t, "test.xcstrings", content)
+	glossaryPath := test.TempFile(t, "glossary.csv", "en,ja,ja:forbidden,de,fr\nWorkspace,ワークスペース,作業スペース,Arbeitsbereich,espace de travail\n")
+
+	output, status := runLintCommand(t, filePath, "--glossary", glossaryPath, "--json")
+	test.AssertEqual(t, status, 0)
+	var doc lintJSONOutput
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("output should be valid JSON, got error %v, output: %q", err, output)
+	}
+	var messages []string
+	for _, issue := range doc.Issues {
+		test.AssertEqual(t, issue.Rule, "glossary")
+		test.AssertEqual(t, issue.Severity, "warning")
+		messages = append(messages, issue.Key+" "+issue.Language+": "+issue.Message)
+	}
+	// "Workspaces" is not the whole word "Workspace", and fr is not translated.
+	test.AssertSliceEqual(t, messages, []string{
+		`open ja: source uses "Workspace" but the translation doesn't use "ワークスペース"`,
+		`open ja: translation uses "作業スペース", which the glossary forbids for "Workspace"; use "ワークスペース"`,
+	})
+}
+
+func TestLintCommand_GlossaryPluralCategoryMissingFromSource(t *testing.T) {
+	content := `{
+		"sourceLanguage": "en",
+		"strings": {
+			"%lld workspaces": {
+				"localizations": {
+					"en": {"variations": {"plural": {
+						"one": {"stringUnit": {"state": "translated", "value": "%lld workspace"}},
+						"other": {"stringUnit": {"state": "translated", "value": "%lld workspaces"}}
+					}}},
+					"ru": {"variations": {"plural": {
+						"one": {"stringUnit": {"state": "translated", "value": "%lld рабочая область"}},
+						"few": {"stringUnit": {"state": "translated", "value": "%lld рабочих пространства"}},
+						"many": {"stringUnit": {"state": "translated", "value": "%lld рабочих областей"}},
+						"other": {"stringUnit": {"state": "translated", "value": "%lld рабочей области"}}
+					}}}
+				}
+			}
+		},
+		"version": "1.0"
+	}`
+	filePath := test.TempFile(t, "test.xcstrings", content)
+	glossaryPath := test.TempFile(t, "glossary.csv", "en,ru\nworkspace;workspaces,рабочая область;рабочих областей;рабочей области\n")
+
+	output, _ := runLintCommand(t, filePath, "--glossary", glossaryPath, "--json")
+	var doc lintJSONOutput
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("output should be valid JSON, got error %v, output: %q", err, output)
+	}
+	if len(doc.Issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d: %+v", len(doc.Issues), doc.Issues)
+	}
+	test.AssertEqual(t, doc.Issues[0].Language, "ru")
+	test.AssertEqual(t, doc.Issues[0].Path, "plural.few")
+}
+
+func TestLintCommand_GlossaryUnreadable(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", `{"sourceLanguage": "en", "strings": {}, "version": "1.0"}`)
+
+	var status int
+	stderr := captureStderr(func() {
+		_, status = runLintCommand(t, filePath, "--glossary", filePath+".missing.csv")
+	})
+	test.AssertEqual(t, status, 1)
+	if !strings.Contains(stderr, "Error:") || !strings.Contains(stderr, "missing.csv") {
+		t.Errorf("expected an error naming the glossary, got: %s", stderr)
+	}
+}
@@ -2,7 +2,6 @@ package command
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"sort"
@@ -39,7 +38,11 @@ func (c *ListCommand) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&c.jsonOutput, "json", false, "Output a single JSON document to stdout instead of human-readable text")
 }
 
-func (c *ListCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+func (c *ListCommand) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return c.forEachCatalog(c.jsonOutput, func() subcommands.ExitStatus { return c.execute(ctx, f) })
+}
+
+func (c *ListCommand) execute(_ context.Context, f *flag.FlagSet) subcommands.ExitStatus {
 	xcstrings, err := c.LoadXCStrings()
 	if err != nil {
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
@@ -64,7 +67,7 @@ func (c *ListCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface
 	sort.Strings(keysToShow)
 
 	if c.jsonOutput {
-		return c.printJSON(xcstrings, keysToShow)
+		return c.printKeysJSON(xcstrings, keysToShow)
 	}
 
 	if len(keysToShow) == 0 {
@@ -121,8 +124,8 @@ type listJSONUnitEntry struct {
 	Value string `json:"value"`
 }
 
-// printJSON marshals the given keys to a single JSON document and writes it to stdout.
-func (c *ListCommand) printJSON(xcs *xcstrings.XCStrings, keys []string) subcommands.ExitStatus {
+// printKeysJSON prints the given keys as a single JSON document.
+func (c *ListCommand) printKeysJSON(xcs *xcstrings.XCStrings, keys []string) subcommands.ExitStatus {
 	languages := allDisplayLanguages(xcs)
 
 	out := listJSONOutput{Keys: make([]listJSONKeyEntry, 0, len(keys))}
@@ -140,13 +143,7 @@ func (c *ListCommand) printJSON(xcs *xcstrings.XCStrings, keys []string) subcomm
 		out.Keys = append(out.Keys, entry)
 	}
 
-	data, err := json.MarshalIndent(out, "", "  ")
-	if err != nil {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
-		return subcommands.ExitFailure
-	}
-	fmt.Println(string(data))
-	return subcommands.ExitSuccess
+	return c.printJSON(out)
 }
 
 // allDisplayLanguages returns the source language plus all catalog languages, sorted.
@@ -0,0 +1,841 @@
+package command
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"xckit/xcstrings"
+)
+
+// Keys of the NSStringLocalizedFormatKey dictionaries in a .stringsdict file.
+const (
+	stringsdictFormatKey     = "NSStringLocalizedFormatKey"
+	stringsdictSpecTypeKey   = "NSStringFormatSpecTypeKey"
+	stringsdictValueTypeKey  = "NSStringFormatValueTypeKey"
+	stringsdictPluralRule    = "NSStringPluralRuleType"
+	stringsdictDeviceRule    = "NSStringDeviceSpecificRuleType"
+	stringsdictPluralVarName = "value"
+)
+
+// xcodeNoComment is the placeholder comment Xcode writes above entries that
+// have no developer comment; it is not carried over on import.
+const xcodeNoComment = "No comment provided by engineer."
+
+// stringsdictSpecRe matches a format argument in a stringsdict format key:
+// either a %#@name@ variable reference or a standard printf specifier. The
+// first group is the optional positional "N$" prefix, the second the
+// variable name.
+var stringsdictSpecRe = regexp.MustCompile(`%(\d+\$)?(?:#@(\w+)@|[-+ 0#']*\d*(?:\.\d+)?(?:hh|h|ll|l|q|L|z|j|t)?[@dioxXucsfeEgGaAp])`)
+
+// stringsEntry is a single "key" = "value"; pair of a .strings file.
+type stringsEntry struct {
+	key     string
+	value   string
+	comment string
+}
+
+// catalogTableName returns the strings table a catalog corresponds to: its
+// file name without the .xcstrings extension (Localizable.xcstrings ->
+// "Localizable").
+func catalogTableName(xcPath string) string {
+	base := filepath.Base(xcPath)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// writeLproj exports the catalog as legacy localization resources under dir:
+// <lang>.lproj/<table>.strings for plain strings and
+// <lang>.lproj/<table>.stringsdict for plural, device and substitution
+// variations. Every language is written, the source language included, or
+// only language when non-empty. Variations a .stringsdict can't express
+// (a device variation containing plurals, or variations alongside
+// substitutions) are skipped with a warning.
+func writeLproj(dir string, xc *xcstrings.XCStrings, table, language string) error {
+	langs := buildLanguageOrder(xc)
+	if language != "" {
+		langs = []string{language}
+	}
+
+	keys := make([]string, 0, len(xc.Strings))
+	for k := range xc.Strings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, lang := range langs {
+		var entries []stringsEntry
+		var dictKeys []string
+		dicts := make(map[string][]plistMember)
+		for _, key := range keys {
+			def := xc.Strings[key]
+			loc, ok := def.Localizations[lang]
+			if !ok {
+				continue
+			}
+			if loc.Variations == nil && len(loc.Substitutions) == 0 {
+				if loc.StringUnit != nil && loc.StringUnit.Value != "" {
+					entries = append(entries, stringsEntry{key: key, value: loc.StringUnit.Value, comment: def.Comment})
+				}
+				continue
+			}
+			dict, err := stringsdictEntry(loc)
+			if err != nil {
+				log.Printf("Warning: skipping key %q lang %q: %v", key, lang, err)
+				continue
+			}
+			if dict != nil {
+				dictKeys = append(dictKeys, key)
+				dicts[key] = dict
+			}
+		}
+		if len(entries) == 0 && len(dictKeys) == 0 {
+			continue
+		}
+
+		lprojDir := filepath.Join(dir, lang+".lproj")
+		if err := os.MkdirAll(lprojDir, 0755); err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			var buf bytes.Buffer
+			writeStrings(&buf, entries)
+			if err := os.WriteFile(filepath.Join(lprojDir, table+".strings"), buf.Bytes(), 0644); err != nil {
+				return err
+			}
+		}
+		if len(dictKeys) > 0 {
+			root := make([]plistMember, 0, len(dictKeys))
+			for _, key := range dictKeys {
+				root = append(root, plistMember{key: key, value: dicts[key]})
+			}
+			var buf bytes.Buffer
+			writePlist(&buf, root)
+			if err := os.WriteFile(filepath.Join(lprojDir, table+".stringsdict"), buf.Bytes(), 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// stringsdictEntry builds the .stringsdict dictionary for a localization with
+// variations or substitutions. A top-level plural variation becomes a single
+// "%#@value@" variable whose value type is taken from the first format
+// specifier of the "other" form; a device variation becomes an
+// NSStringDeviceSpecificRuleType dictionary; substitutions become one
+// variable per substitution with %arg spelled out as the substitution's
+// format specifier. Returns nil when there is nothing to write.
+func stringsdictEntry(loc xcstrings.Localization) ([]plistMember, error) {
+	if len(loc.Substitutions) > 0 {
+		if loc.Variations != nil {
+			return nil, fmt.Errorf("variations alongside substitutions can't be expressed in a .stringsdict")
+		}
+		if loc.StringUnit == nil || loc.StringUnit.Value == "" {
+			return nil, nil
+		}
+		dict := []plistMember{{key: stringsdictFormatKey, value: loc.StringUnit.Value}}
+		names := make([]string, 0, len(loc.Substitutions))
+		for name := range loc.Substitutions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			sub := loc.Substitutions[name]
+			if len(sub.Variations.Device) > 0 {
+				return nil, fmt.Errorf("substitution %q has device variations, which can't be expressed in a .stringsdict", name)
+			}
+			spec := "%${1}" + sub.FormatSpecifier
+			rule := pluralRuleMembers(sub.FormatSpecifier, sub.Variations.Plural, func(s string) string {
+				return lintArgRe.ReplaceAllString(s, spec)
+			})
+			dict = append(dict, plistMember{key: name, value: rule})
+		}
+		return dict, nil
+	}
+
+	v := loc.Variations
+	if len(v.Device) > 0 {
+		if len(v.Plural) > 0 {
+			return nil, fmt.Errorf("device and plural variations can't both be expressed in a .stringsdict")
+		}
+		var devices []plistMember
+		for _, dev := range xcstrings.ValidDeviceCategories {
+			vv := v.Device[dev]
+			if vv == nil {
+				continue
+			}
+			if vv.Variations != nil {
+				return nil, fmt.Errorf("device %q has nested variations, which can't be expressed in a .stringsdict", dev)
+			}
+			if vv.StringUnit != nil && vv.StringUnit.Value != "" {
+				devices = append(devices, plistMember{key: dev, value: vv.StringUnit.Value})
+			}
+		}
+		if len(devices) == 0 {
+			return nil, nil
+		}
+		return []plistMember{{key: stringsdictDeviceRule, value: devices}}, nil
+	}
+
+	if len(v.Plural) == 0 {
+		return nil, nil
+	}
+	valueType := "lld"
+	if other := v.Plural["other"]; other != nil && other.StringUnit != nil {
+		if m := lintStdSpecRe.FindStringSubmatch(other.StringUnit.Value); m != nil {
+			valueType = m[2] + m[3]
+		}
+	}
+	rule := pluralRuleMembers(valueType, v.Plural, func(s string) string { return s })
+	return []plistMember{
+		{key: stringsdictFormatKey, value: "%#@" + stringsdictPluralVarName + "@"},
+		{key: stringsdictPluralVarName, value: rule},
+	}, nil
+}
+
+// pluralRuleMembers builds an NSStringPluralRuleType variable dictionary from
+// plural variations, listing categories in CLDR order. convert rewrites each
+// form before it is written.
+func pluralRuleMembers(valueType string, plural map[string]*xcstrings.VariationValue, convert func(string) string) []plistMember {
+	rule := []plistMember{
+		{key: stringsdictSpecTypeKey, value: stringsdictPluralRule},
+		{key: stringsdictValueTypeKey, value: valueType},
+	}
+	for _, cat := range xcstrings.ValidPluralCategories {
+		vv := plural[cat]
+		if vv == nil || vv.StringUnit == nil || vv.StringUnit.Value == "" {
+			continue
+		}
+		rule = append(rule, plistMember{key: cat, value: convert(vv.StringUnit.Value)})
+	}
+	return rule
+}
+
+// writeStrings writes entries in .strings syntax, each preceded by its
+// comment when it has one.
+func writeStrings(w *bytes.Buffer, entries []stringsEntry) {
+	for i, e := range entries {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+		if e.comment != "" {
+			fmt.Fprintf(w, "/* %s */\n", strings.ReplaceAll(e.comment, "*/", "* /"))
+		}
+		fmt.Fprintf(w, "%s = %s;\n", quoteStrings(e.key), quoteStrings(e.value))
+	}
+}
+
+// quoteStrings quotes s as a .strings literal.
+func quoteStrings(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// plistMember is one key/value pair of a property list dictionary. value is
+// either a string or a nested []plistMember dictionary.
+type plistMember struct {
+	key   string
+	value any
+}
+
+// writePlist writes root as an XML property list dictionary, indented with
+// tabs the way Xcode writes .stringsdict files.
+func writePlist(w *bytes.Buffer, root []plistMember) {
+	w.WriteString(xml.Header)
+	w.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	w.WriteString(`<plist version="1.0">` + "\n")
+	writePlistDict(w, root, 0)
+	w.WriteString("</plist>\n")
+}
+
+var plistEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func writePlistDict(w *bytes.Buffer, members []plistMember, depth int) {
+	indent := strings.Repeat("\t", depth)
+	w.WriteString(indent + "<dict>\n")
+	for _, m := range members {
+		fmt.Fprintf(w, "%s\t<key>%s</key>\n", indent, plistEscaper.Replace(m.key))
+		switch v := m.value.(type) {
+		case string:
+			fmt.Fprintf(w, "%s\t<string>%s</string>\n", indent, plistEscaper.Replace(v))
+		case []plistMember:
+			writePlistDict(w, v, depth+1)
+		}
+	}
+	w.WriteString(indent + "</dict>\n")
+}
+
+// importLproj migrates a tree of <lang>.lproj directories into the catalog,
+// reading <table>.strings and <table>.stringsdict from each. Base.lproj is
+// imported as the source language. Unlike the other import formats, keys
+// missing from the catalog are created (with extractionState "migrated", as
+// Xcode marks keys it migrates), and the source language is imported too.
+// A key present in both files takes its .stringsdict form, matching how
+// Foundation resolves them at runtime.
+func importLproj(dir string, xc *xcstrings.XCStrings, table string, clearEmpty bool) (*importSummary, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &importSummary{}
+	found := false
+	for _, de := range dirEntries {
+		if !de.IsDir() || !strings.HasSuffix(de.Name(), ".lproj") {
+			continue
+		}
+		lang := strings.TrimSuffix(de.Name(), ".lproj")
+		if lang == "Base" {
+			lang = xc.SourceLanguage
+		}
+
+		stringsPath := filepath.Join(dir, de.Name(), table+".strings")
+		if data, err := os.ReadFile(stringsPath); err == nil {
+			found = true
+			entries, err := parseStrings(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", stringsPath, err)
+			}
+			for _, e := range entries {
+				ensureMigratedKey(xc, e.key, e.comment)
+				applyImportValue(xc, summary, e.key, lang, "", e.value, "", clearEmpty)
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		dictPath := filepath.Join(dir, de.Name(), table+".stringsdict")
+		if data, err := os.ReadFile(dictPath); err == nil {
+			found = true
+			if err := importStringsdict(bytes.NewReader(data), xc, summary, lang, clearEmpty); err != nil {
+				return nil, fmt.Errorf("%s: %w", dictPath, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no %s.strings or %s.stringsdict found in any .lproj directory under %s", table, table, dir)
+	}
+	return summary, nil
+}
+
+// ensureMigratedKey creates key with extractionState "migrated" when the
+// catalog doesn't have it yet. comment is only used for new keys.
+func ensureMigratedKey(xc *xcstrings.XCStrings, key, comment string) {
+	if _, exists := xc.Strings[key]; exists {
+		return
+	}
+	if comment == xcodeNoComment {
+		comment = ""
+	}
+	xc.Strings[key] = xcstrings.StringDefinition{
+		Comment:         comment,
+		ExtractionState: "migrated",
+		Localizations:   make(map[string]xcstrings.Localization),
+	}
+}
+
+// importStringsdict applies the entries of a .stringsdict file for lang.
+// A format key consisting of a single plural variable becomes top-level
+// plural variations; any other format key becomes the host string, with each
+// plural variable written as a substitution via SetSubstitutionTranslation.
+// NSStringDeviceSpecificRuleType entries become device variations.
+func importStringsdict(r io.Reader, xc *xcstrings.XCStrings, summary *importSummary, lang string, clearEmpty bool) error {
+	root, err := parsePlist(r)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(root))
+	for k := range root {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entry, ok := root[key].(map[string]any)
+		if !ok {
+			summary.skip(key, lang, "", skipInvalidStringsdict, "entry is not a dictionary")
+			continue
+		}
+
+		if devices, ok := entry[stringsdictDeviceRule].(map[string]any); ok {
+			ensureMigratedKey(xc, key, "")
+			for _, dev := range xcstrings.ValidDeviceCategories {
+				if value, ok := devices[dev].(string); ok {
+					applyImportValue(xc, summary, key, lang, "device."+dev, value, "", clearEmpty)
+				}
+			}
+			continue
+		}
+
+		format, ok := entry[stringsdictFormatKey].(string)
+		if !ok {
+			summary.skip(key, lang, "", skipInvalidStringsdict, "no "+stringsdictFormatKey)
+			continue
+		}
+
+		// Every variable is checked before anything is written, so an
+		// invalid one doesn't leave a host string without its substitution.
+		vars := stringsdictVariables(format)
+		rules := make([]map[string]any, len(vars))
+		invalid := ""
+		for i, v := range vars {
+			rule, ok := stringsdictPluralVariable(entry, v.name)
+			valueType, _ := rule[stringsdictValueTypeKey].(string)
+			switch {
+			case !ok:
+				invalid = fmt.Sprintf("variable %q is not a plural rule", v.name)
+			case valueType == "" && !v.whole:
+				invalid = fmt.Sprintf("variable %q has no %s", v.name, stringsdictValueTypeKey)
+			}
+			if invalid != "" {
+				break
+			}
+			rules[i] = rule
+		}
+		if invalid != "" {
+			summary.skip(key, lang, "", skipInvalidStringsdict, invalid)
+			continue
+		}
+		ensureMigratedKey(xc, key, "")
+
+		if len(vars) == 1 && vars[0].whole {
+			rule := rules[0]
+			for _, cat := range xcstrings.ValidPluralCategories {
+				if value, ok := rule[cat].(string); ok {
+					applyImportValue(xc, summary, key, lang, "plural."+cat, value, "", clearEmpty)
+				}
+			}
+			continue
+		}
+
+		applyImportValue(xc, summary, key, lang, "", format, "", clearEmpty)
+		for i, v := range vars {
+			rule := rules[i]
+			valueType := rule[stringsdictValueTypeKey].(string)
+			specRe := regexp.MustCompile(`%(\d+\$)?` + regexp.QuoteMeta(valueType))
+			for _, cat := range xcstrings.ValidPluralCategories {
+				value, ok := rule[cat].(string)
+				if !ok {
+					continue
+				}
+				value = specRe.ReplaceAllString(value, "%${1}arg")
+				applySubstitutionValue(xc, summary, key, lang, v.name, cat, value, v.argNum, valueType)
+			}
+		}
+	}
+	return nil
+}
+
+// applySubstitutionValue writes one plural form of a substitution, defining
+// the substitution with argNum/formatSpecifier when the key doesn't have it
+// yet, and tallies the outcome in summary like applyImportValue does.
+func applySubstitutionValue(xc *xcstrings.XCStrings, summary *importSummary, key, lang, subName, category, value string, argNum int, formatSpecifier string) {
+	path := "substitutions." + subName + ".plural." + category
+	existing := currentTranslationUnit(xc, key, lang, path)
+	if existing != nil && existing.Value == value {
+		summary.unchanged++
+		return
+	}
+	opts := xcstrings.VariationOptions{Plural: category}
+	if _, err := xc.SetSubstitutionTranslation(key, lang, subName, value, opts, argNum, formatSpecifier); err != nil {
+		summary.skip(key, lang, path, skipWriteFailed, err.Error())
+		return
+	}
+	change := importChange{Key: key, Language: lang, Path: path, Action: "created"}
+	change.OldValue, change.OldState = unitValueAndState(existing)
+	change.NewValue, change.NewState = unitValueAndState(currentTranslationUnit(xc, key, lang, path))
+	if existing != nil {
+		change.Action = "updated"
+	}
+	summary.record(change)
+}
+
+// stringsdictVariables returns the %#@name@ variables referenced by a
+// stringsdict format key along with the argument number each consumes.
+// whole is set when the variable is the entire format key.
+func stringsdictVariables(format string) []stringsdictVariable {
+	masked := strings.ReplaceAll(format, "%%", "\x00\x00")
+	var vars []stringsdictVariable
+	next := 1
+	for _, m := range stringsdictSpecRe.FindAllStringSubmatchIndex(masked, -1) {
+		argNum := next
+		if m[2] >= 0 {
+			argNum, _ = strconv.Atoi(masked[m[2] : m[3]-1])
+		} else {
+			next++
+		}
+		if m[4] < 0 {
+			continue
+		}
+		vars = append(vars, stringsdictVariable{
+			name:   masked[m[4]:m[5]],
+			argNum: argNum,
+			whole:  m[0] == 0 && m[1] == len(masked),
+		})
+	}
+	return vars
+}
+
+type stringsdictVariable struct {
+	name   string
+	argNum int
+	whole  bool
+}
+
+// stringsdictPluralVariable returns the variable dictionary for name when it
+// is an NSStringPluralRuleType rule.
+func stringsdictPluralVariable(entry map[string]any, name string) (map[string]any, bool) {
+	rule, ok := entry[name].(map[string]any)
+	if !ok || rule[stringsdictSpecTypeKey] != stringsdictPluralRule {
+		return nil, false
+	}
+	return rule, true
+}
+
+// parsePlist reads an XML property list whose root is a dictionary. Nested
+// dictionaries decode to map[string]any, arrays to []any, and every scalar
+// (string, integer, real, date, data) to its text; booleans decode to bool.
+func parsePlist(r io.Reader) (map[string]any, error) {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("property list has no root dictionary")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read property list: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local == "plist" {
+			continue
+		}
+		if start.Name.Local != "dict" {
+			return nil, fmt.Errorf("property list root is <%s>, expected <dict>", start.Name.Local)
+		}
+		v, err := readPlistValue(dec, start)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read property list: %w", err)
+		}
+		return v.(map[string]any), nil
+	}
+}
+
+func readPlistValue(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		dict := make(map[string]any)
+		key := ""
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if err := dec.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				v, err := readPlistValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				dict[key] = v
+			case xml.EndElement:
+				return dict, nil
+			}
+		}
+	case "array":
+		var arr []any
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				v, err := readPlistValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			case xml.EndElement:
+				return arr, nil
+			}
+		}
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	default:
+		var s string
+		if err := dec.DecodeElement(&s, &start); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+}
+
+// parseStrings parses the contents of a .strings file. UTF-16 files (as
+// older Xcode versions wrote them) are recognized by their byte order mark.
+// Both quoted and bare-word keys and values are accepted, as is the
+// shorthand "key"; for an entry whose value is its key. The most recent
+// block or line comment before an entry is returned as its comment.
+func parseStrings(data []byte) ([]stringsEntry, error) {
+	text, err := decodeStringsText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &stringsParser{src: []rune(text)}
+	var entries []stringsEntry
+	for {
+		comment, err := p.skipSpaceAndComments()
+		if err != nil {
+			return nil, err
+		}
+		if p.eof() {
+			return entries, nil
+		}
+
+		key, err := p.readString()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.skipSpaceAndComments(); err != nil {
+			return nil, err
+		}
+		value := key
+		if p.peek() == '=' {
+			p.pos++
+			if _, err := p.skipSpaceAndComments(); err != nil {
+				return nil, err
+			}
+			if value, err = p.readString(); err != nil {
+				return nil, err
+			}
+			if _, err := p.skipSpaceAndComments(); err != nil {
+				return nil, err
+			}
+		}
+		if p.peek() != ';' {
+			return nil, p.errorf("expected ';' after %q", key)
+		}
+		p.pos++
+		entries = append(entries, stringsEntry{key: key, value: value, comment: comment})
+	}
+}
+
+// decodeStringsText converts .strings file bytes to a string, honoring a
+// UTF-8 or UTF-16 byte order mark.
+func decodeStringsText(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		data = data[3:]
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		littleEndian := data[0] == 0xFF
+		data = data[2:]
+		if len(data)%2 != 0 {
+			return "", fmt.Errorf("truncated UTF-16 data")
+		}
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			if littleEndian {
+				units[i] = uint16(data[2*i]) | uint16(data[2*i+1])<<8
+			} else {
+				units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+			}
+		}
+		return string(utf16.Decode(units)), nil
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("file is neither UTF-8 nor UTF-16 with a byte order mark")
+	}
+	return string(data), nil
+}
+
+// stringsParser is a cursor over the runes of a .strings file.
+type stringsParser struct {
+	src []rune
+	pos int
+}
+
+func (p *stringsParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *stringsParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *stringsParser) errorf(format string, args ...any) error {
+	line := 1
+	for _, r := range p.src[:min(p.pos, len(p.src))] {
+		if r == '\n' {
+			line++
+		}
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skipSpaceAndComments advances past whitespace and comments, returning the
+// text of the last comment skipped.
+func (p *stringsParser) skipSpaceAndComments() (string, error) {
+	comment := ""
+	for !p.eof() {
+		r := p.peek()
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			p.pos++
+		case r == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '*':
+			end := -1
+			for i := p.pos + 2; i+1 < len(p.src); i++ {
+				if p.src[i] == '*' && p.src[i+1] == '/' {
+					end = i
+					break
+				}
+			}
+			if end < 0 {
+				return "", p.errorf("unterminated comment")
+			}
+			comment = strings.TrimSpace(string(p.src[p.pos+2 : end]))
+			p.pos = end + 2
+		case r == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '/':
+			start := p.pos + 2
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+			comment = strings.TrimSpace(string(p.src[start:p.pos]))
+		default:
+			return comment, nil
+		}
+	}
+	return comment, nil
+}
+
+// readString reads a quoted string literal or a bare word.
+func (p *stringsParser) readString() (string, error) {
+	if p.peek() != '"' {
+		start := p.pos
+		for !p.eof() && isStringsBareRune(p.peek()) {
+			p.pos++
+		}
+		if p.pos == start {
+			return "", p.errorf("expected a string, found %q", p.peek())
+		}
+		return string(p.src[start:p.pos]), nil
+	}
+
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		r := p.src[p.pos]
+		p.pos++
+		switch r {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			esc := p.src[p.pos]
+			p.pos++
+			switch esc {
+			case 'n':
+				b.WriteRune('\n')
+			case 'r':
+				b.WriteRune('\r')
+			case 't':
+				b.WriteRune('\t')
+			case 'a':
+				b.WriteRune('\a')
+			case 'b':
+				b.WriteRune('\b')
+			case 'f':
+				b.WriteRune('\f')
+			case 'v':
+				b.WriteRune('\v')
+			case 'U', 'u':
+				if p.pos+4 > len(p.src) {
+					return "", p.errorf("truncated \\%c escape", esc)
+				}
+				n, err := strconv.ParseUint(string(p.src[p.pos:p.pos+4]), 16, 16)
+				if err != nil {
+					return "", p.errorf("invalid \\%c escape", esc)
+				}
+				p.pos += 4
+				u := rune(n)
+				// A surrogate pair is spelled as two consecutive escapes.
+				if utf16.IsSurrogate(u) && p.pos+6 <= len(p.src) && p.src[p.pos] == '\\' && (p.src[p.pos+1] == 'U' || p.src[p.pos+1] == 'u') {
+					if lo, err := strconv.ParseUint(string(p.src[p.pos+2:p.pos+6]), 16, 16); err == nil {
+						if d := utf16.DecodeRune(u, rune(lo)); d != utf8.RuneError {
+							u = d
+							p.pos += 6
+						}
+					}
+				}
+				b.WriteRune(u)
+			default:
+				if esc >= '0' && esc <= '7' {
+					end := p.pos - 1
+					for end < len(p.src) && end < p.pos+2 && p.src[end] >= '0' && p.src[end] <= '7' {
+						end++
+					}
+					n, _ := strconv.ParseUint(string(p.src[p.pos-1:end]), 8, 32)
+					b.WriteRune(rune(n))
+					p.pos = end
+					continue
+				}
+				b.WriteRune(esc)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+// isStringsBareRune reports whether r may appear in an unquoted .strings
+// word.
+func isStringsBareRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		strings.ContainsRune("_$+/:.-", r)
+}
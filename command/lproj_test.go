@@ -0,0 +1,311 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+func TestWriteLproj(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	dir := t.TempDir()
+	test.AssertNoError(t, writeLproj(dir, xc, "Localizable", ""))
+
+	enStrings, err := os.ReadFile(filepath.Join(dir, "en.lproj", "Localizable.strings"))
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(enStrings), "\"debug\" = \"Debug\";\n\n/* Shown on the home screen */\n\"greeting\" = \"Hello & welcome\";\n")
+
+	enDict, err := os.ReadFile(filepath.Join(dir, "en.lproj", "Localizable.stringsdict"))
+	test.AssertNoError(t, err)
+	expected := []string{
+		`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">`,
+		"\t<key>files</key>\n\t<dict>\n\t\t<key>NSStringLocalizedFormatKey</key>\n\t\t<string>%#@files@</string>\n\t\t<key>files</key>\n\t\t<dict>\n\t\t\t<key>NSStringFormatSpecTypeKey</key>\n\t\t\t<string>NSStringPluralRuleType</string>\n\t\t\t<key>NSStringFormatValueTypeKey</key>\n\t\t\t<string>lld</string>\n\t\t\t<key>one</key>\n\t\t\t<string>%lld file</string>",
+		"\t<key>item_count</key>\n\t<dict>\n\t\t<key>NSStringLocalizedFormatKey</key>\n\t\t<string>%#@value@</string>",
+		"<key>other</key>\n\t\t\t<string>%lld items</string>",
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(enDict), want) {
+			t.Errorf("expected stringsdict to contain %q, got:\n%s", want, enDict)
+		}
+	}
+
+	jaStrings, err := os.ReadFile(filepath.Join(dir, "ja.lproj", "Localizable.strings"))
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(jaStrings), "/* Shown on the home screen */\n\"greeting\" = \"こんにちは\";\n")
+}
+
+func TestLproj_RoundTrip(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	dir := t.TempDir()
+	test.AssertNoError(t, writeLproj(dir, xc, "Localizable", ""))
+
+	migrated := &xcstrings.XCStrings{SourceLanguage: "en", Strings: map[string]xcstrings.StringDefinition{}}
+	summary, err := importLproj(dir, migrated, "Localizable", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.skipped, 0)
+
+	greeting := migrated.Strings["greeting"]
+	test.AssertEqual(t, greeting.ExtractionState, "migrated")
+	test.AssertEqual(t, greeting.Comment, "Shown on the home screen")
+	test.AssertEqual(t, greeting.Localizations["ja"].StringUnit.Value, "こんにちは")
+
+	plural := migrated.Strings["item_count"].Localizations["en"].Variations.Plural
+	test.AssertEqual(t, plural["one"].StringUnit.Value, "%lld item")
+	test.AssertEqual(t, plural["other"].StringUnit.Value, "%lld items")
+
+	// A substitution spanning the whole string is the same thing as a
+	// top-level plural, and comes back as one.
+	files := migrated.Strings["files"].Localizations["en"].Variations.Plural
+	test.AssertEqual(t, files["one"].StringUnit.Value, "%lld file")
+
+	// Importing the same tree again changes nothing.
+	summary, err = importLproj(dir, migrated, "Localizable", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.created, 0)
+	test.AssertEqual(t, summary.updated, 0)
+}
+
+func TestImportLproj_LegacyFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(rel string, data []byte) {
+		path := filepath.Join(dir, rel)
+		test.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		test.AssertNoError(t, os.WriteFile(path, data, 0644))
+	}
+
+	writeFile("Base.lproj/Localizable.strings", []byte(`/* Greeting */
+"hello" = "Hello\n\"world\"";
+// line comment
+bare_key = bare.value;
+"same";
+"escaped" = "\U00e9😀";
+/* No comment provided by engineer. */
+"placeholder" = "%@ and %d";
+`))
+	// UTF-16LE with a byte order mark, as older Xcode versions wrote.
+	utf16 := []byte{0xFF, 0xFE}
+	for _, r := range `"hello" = "こんにちは";` {
+		utf16 = append(utf16, byte(r), byte(r>>8))
+	}
+	writeFile("ja.lproj/Localizable.strings", utf16)
+	writeFile("ja.lproj/Localizable.stringsdict", []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>%d files in %@</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%2$@ の %1$#@files@</string>
+		<key>files</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>other</key>
+			<string>%d 個のファイル</string>
+		</dict>
+	</dict>
+	<key>tap</key>
+	<dict>
+		<key>NSStringDeviceSpecificRuleType</key>
+		<dict>
+			<key>iphone</key>
+			<string>タップ</string>
+			<key>mac</key>
+			<string>クリック</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`))
+	writeFile("fr.lproj/Other.strings", []byte(`"hello" = "Bonjour";`))
+
+	xc := &xcstrings.XCStrings{SourceLanguage: "en", Strings: map[string]xcstrings.StringDefinition{}}
+	_, err := importLproj(dir, xc, "Localizable", false)
+	test.AssertNoError(t, err)
+
+	hello := xc.Strings["hello"]
+	test.AssertEqual(t, hello.Comment, "Greeting")
+	test.AssertEqual(t, hello.Localizations["en"].StringUnit.Value, "Hello\n\"world\"")
+	test.AssertEqual(t, hello.Localizations["ja"].StringUnit.Value, "こんにちは")
+	if _, ok := hello.Localizations["fr"]; ok {
+		t.Error("files of another table should not be imported")
+	}
+	test.AssertEqual(t, xc.Strings["bare_key"].Localizations["en"].StringUnit.Value, "bare.value")
+	test.AssertEqual(t, xc.Strings["same"].Localizations["en"].StringUnit.Value, "same")
+	test.AssertEqual(t, xc.Strings["escaped"].Localizations["en"].StringUnit.Value, "é😀")
+	test.AssertEqual(t, xc.Strings["placeholder"].Comment, "")
+
+	files := xc.Strings["%d files in %@"].Localizations["ja"]
+	test.AssertEqual(t, files.StringUnit.Value, "%2$@ の %1$#@files@")
+	sub := files.Substitutions["files"]
+	test.AssertEqual(t, sub.ArgNum, 1)
+	test.AssertEqual(t, sub.FormatSpecifier, "d")
+	test.AssertEqual(t, sub.Variations.Plural["other"].StringUnit.Value, "%arg 個のファイル")
+
+	device := xc.Strings["tap"].Localizations["ja"].Variations.Device
+	test.AssertEqual(t, device["iphone"].StringUnit.Value, "タップ")
+	test.AssertEqual(t, device["mac"].StringUnit.Value, "クリック")
+}
+
+func TestImportLproj_StringsdictWithoutValueType(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ja.lproj", "Localizable.stringsdict")
+	test.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	test.AssertNoError(t, os.WriteFile(path, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>%lld files</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@folders@ %#@files@</string>
+		<key>folders</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>lld</string>
+			<key>other</key>
+			<string>%lld 個のフォルダ</string>
+		</dict>
+		<key>files</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>other</key>
+			<string>%lld 個のファイル</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`), 0644))
+
+	xc := &xcstrings.XCStrings{SourceLanguage: "en", Strings: map[string]xcstrings.StringDefinition{}}
+	summary, err := importLproj(dir, xc, "Localizable", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.skipped, 1)
+	test.AssertEqual(t, len(summary.changes), 1)
+	test.AssertEqual(t, summary.changes[0].Reason, skipInvalidStringsdict)
+	// Nothing of the entry is written, not even the host string.
+	if _, ok := xc.Strings["%lld files"]; ok {
+		t.Errorf("an entry with an invalid variable should be skipped whole, got %+v", xc.Strings["%lld files"])
+	}
+}
+
+func TestImportLproj_NoTableFound(t *testing.T) {
+	dir := t.TempDir()
+	test.AssertNoError(t, os.MkdirAll(filepath.Join(dir, "en.lproj"), 0755))
+	xc := &xcstrings.XCStrings{SourceLanguage: "en", Strings: map[string]xcstrings.StringDefinition{}}
+	_, err := importLproj(dir, xc, "Localizable", false)
+	test.AssertError(t, err)
+}
+
+func TestParseStrings_Errors(t *testing.T) {
+	tests := []string{
+		`"a" = "b"`,
+		`"a" = "b`,
+		`/* open`,
+		`"a" = ;`,
+	}
+	for _, input := range tests {
+		if _, err := parseStrings([]byte(input)); err == nil {
+			t.Errorf("expected an error for %q", input)
+		}
+	}
+}
+
+func TestExportImportCommand_Strings(t *testing.T) {
+	xcPath := test.TempFile(t, "Localizable.xcstrings", xliffFixture)
+	dir := t.TempDir()
+
+	exportCmd := &ExportCommand{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	exportCmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", "strings", "--lang", "ja", "-o", dir}))
+	test.AssertEqual(t, int(exportCmd.Execute(context.Background(), flagSet)), 0)
+
+	if _, err := os.Stat(filepath.Join(dir, "en.lproj")); !os.IsNotExist(err) {
+		t.Error("--lang ja should only export ja.lproj")
+	}
+	stringsPath := filepath.Join(dir, "ja.lproj", "Localizable.strings")
+	test.AssertNoError(t, os.WriteFile(stringsPath, []byte(`"greeting" = "やあ";`), 0644))
+
+	importCmd := &ImportCommand{}
+	flagSet = flag.NewFlagSet("test", flag.ContinueOnError)
+	importCmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", "strings", dir}))
+
+	output := captureOutput(func() {
+		test.AssertEqual(t, int(importCmd.Execute(context.Background(), flagSet)), 0)
+	})
+	if !strings.Contains(output, "1 updated") {
+		t.Errorf("expected 1 updated, got: %q", output)
+	}
+
+	xc, err := xcstrings.Load(xcPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["greeting"].Localizations["ja"].StringUnit.Value, "やあ")
+}
+
+func TestExportCommand_StringsRequiresOutputDir(t *testing.T) {
+	xcPath := test.TempFile(t, "Localizable.xcstrings", xliffFixture)
+
+	cmd := &ExportCommand{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", "strings"}))
+	test.AssertEqual(t, int(cmd.Execute(context.Background(), flagSet)), 1)
+}
+
+func TestExportImportCommand_StringsMultipleCatalogs(t *testing.T) {
+	project := writeProject(t, map[string]string{
+		"App/Localizable.xcstrings": localizableFixture,
+		"App/InfoPlist.xcstrings":   infoPlistFixture,
+		"Other/InfoPlist.xcstrings": infoPlistFixture,
+	})
+	dir := t.TempDir()
+
+	run := func(cmd subcommands.Command, args ...string) int {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		cmd.SetFlags(flagSet)
+		test.AssertNoError(t, flagSet.Parse(args))
+		var status subcommands.ExitStatus
+		captureOutput(func() { status = cmd.Execute(context.Background(), flagSet) })
+		return int(status)
+	}
+
+	test.AssertEqual(t, run(&ExportCommand{}, "-f", filepath.Join(project, "App"), "--format", "strings", "-o", dir), 0)
+	for _, name := range []string{"Localizable.strings", "InfoPlist.strings"} {
+		if _, err := os.Stat(filepath.Join(dir, "ja.lproj", name)); err != nil {
+			t.Errorf("expected ja.lproj/%s: %v", name, err)
+		}
+	}
+
+	// Two catalogs exported as the same table would overwrite each other.
+	test.AssertEqual(t, run(&ExportCommand{}, "-f", filepath.Join(project, "App"), "-f", filepath.Join(project, "Other"), "--format", "strings", "-o", dir), 1)
+	// The file-based formats take a single catalog.
+	test.AssertEqual(t, run(&ExportCommand{}, "-f", filepath.Join(project, "App"), "--format", "po", "-o", dir), 1)
+
+	test.AssertNoError(t, os.WriteFile(filepath.Join(dir, "ja.lproj", "Localizable.strings"), []byte(`"bye" = "さようなら";`), 0644))
+	test.AssertEqual(t, run(&ImportCommand{}, "-f", filepath.Join(project, "App"), "--format", "strings", dir), 0)
+	xc, err := xcstrings.Load(filepath.Join(project, "App", "Localizable.xcstrings"))
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["bye"].Localizations["ja"].StringUnit.Value, "さようなら")
+
+	test.AssertEqual(t, run(&ImportCommand{}, "-f", filepath.Join(project, "App"), "--format", "po", filepath.Join(dir, "x.po")), 1)
+}
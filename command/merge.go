@@ -0,0 +1,144 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+type MergeCommand struct {
+	output     string
+	onConflict string
+	dryRun     bool
+	jsonOutput bool
+}
+
+func (*MergeCommand) Name() string {
+	return "merge"
+}
+
+func (*MergeCommand) Synopsis() string {
+	return "Combine several catalogs into one"
+}
+
+func (*MergeCommand) Usage() string {
+	return `merge [-o <out.xcstrings>] [--on-conflict error|first|last|merge] [--dry-run] [--json] <catalog> <catalog> ...: Merge the catalogs, in order, into -o (default: the first catalog)
+A key defined differently in several catalogs is resolved by --on-conflict: error (default) reports it, first keeps the earliest definition, last the latest, and merge combines their translations, reporting string units translated differently.
+`
+}
+
+func (c *MergeCommand) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.output, "o", "", "Output file path (default: the first catalog)")
+	f.StringVar(&c.onConflict, "on-conflict", string(xcstrings.ConflictError), "How to resolve keys the catalogs define differently: error, first, last or merge")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show what would be merged without writing the file")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output a structured JSON document describing the merge")
+}
+
+// mergeJSONInput lists what was taken from one catalog.
+type mergeJSONInput struct {
+	File     string   `json:"file"`
+	Added    []string `json:"added"`
+	Kept     []string `json:"kept,omitempty"`
+	Replaced []string `json:"replaced,omitempty"`
+	Merged   []string `json:"merged,omitempty"`
+}
+
+// mergeJSONOutput is the top-level document printed by `merge --json`.
+type mergeJSONOutput struct {
+	DryRun  bool             `json:"dryRun"`
+	Output  string           `json:"output"`
+	Inputs  []mergeJSONInput `json:"inputs"`
+	Summary struct {
+		Keys int `json:"keys"`
+	} `json:"summary"`
+}
+
+func (c *MergeCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	policy := xcstrings.ConflictPolicy(c.onConflict)
+	if f.NArg() < 2 || !slices.Contains(xcstrings.ConflictPolicies, policy) {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: at least two catalogs and a valid --on-conflict are required\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+	paths := f.Args()
+	output := c.output
+	if output == "" {
+		output = paths[0]
+	} else if _, err := os.Stat(output); err == nil && !slices.ContainsFunc(paths, func(path string) bool { return samePath(path, output) }) {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s already exists and is not one of the catalogs being merged\n", output)
+		return subcommands.ExitFailure
+	}
+
+	merged, err := xcstrings.Load(paths[0])
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s: %v\n", paths[0], err)
+		return subcommands.ExitFailure
+	}
+	out := mergeJSONOutput{DryRun: c.dryRun, Output: output}
+	out.Inputs = append(out.Inputs, mergeJSONInput{File: paths[0], Added: merged.Keys()})
+	slices.Sort(out.Inputs[0].Added)
+
+	var conflicts []string
+	for _, path := range paths[1:] {
+		other, err := xcstrings.Load(path)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s: %v\n", path, err)
+			return subcommands.ExitFailure
+		}
+		result, err := merged.MergeCatalog(other, policy)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s: %v\n", path, err)
+			return subcommands.ExitFailure
+		}
+		for _, conflict := range result.Conflicts {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s", path, conflict))
+		}
+		input := mergeJSONInput{File: path, Added: result.Added, Kept: result.Kept, Replaced: result.Replaced, Merged: result.Merged}
+		if input.Added == nil {
+			input.Added = []string{}
+		}
+		out.Inputs = append(out.Inputs, input)
+	}
+	if len(conflicts) > 0 {
+		for _, conflict := range conflicts {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s differs from an earlier catalog\n", conflict)
+		}
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Nothing was written; choose how to resolve conflicts with --on-conflict first, last or merge\n")
+		return subcommands.ExitFailure
+	}
+	out.Summary.Keys = len(merged.Strings)
+
+	if !c.dryRun {
+		if err := merged.SaveToFile(output); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	if c.jsonOutput {
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		fmt.Println(string(data))
+		return subcommands.ExitSuccess
+	}
+
+	prefix := ""
+	if c.dryRun {
+		prefix = "[dry-run] "
+	}
+	for _, input := range out.Inputs {
+		fmt.Printf("%s%s: %d added, %d kept, %d replaced, %d merged\n", prefix, input.File, len(input.Added), len(input.Kept), len(input.Replaced), len(input.Merged))
+	}
+	fmt.Printf("%sWrote %d keys to %s\n", prefix, out.Summary.Keys, output)
+	return subcommands.ExitSuccess
+}
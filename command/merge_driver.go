@@ -0,0 +1,94 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"xckit/helper/atomicwrite"
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+type MergeDriverCommand struct{}
+
+func (*MergeDriverCommand) Name() string {
+	return "merge-driver"
+}
+
+func (*MergeDriverCommand) Synopsis() string {
+	return "Three-way merge .xcstrings files per key, language and variation (git merge driver)"
+}
+
+func (*MergeDriverCommand) Usage() string {
+	return "merge-driver <base> <ours> <theirs> [<path>]: Merge <theirs> into <ours> using their common ancestor <base>, writing the result to <ours>. Meant to be run by git as a merge driver with %O %A %B %P; exits 1 when a leaf changed differently on both sides, leaving conflict markers in its value\n"
+}
+
+func (*MergeDriverCommand) SetFlags(f *flag.FlagSet) {
+}
+
+func (c *MergeDriverCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() < 3 || f.NArg() > 4 {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: base, ours and theirs files are required\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+	basePath, oursPath, theirsPath := f.Arg(0), f.Arg(1), f.Arg(2)
+	name := oursPath
+	if f.NArg() == 4 {
+		name = f.Arg(3)
+	}
+
+	var contents [3][]byte
+	for i, path := range []string{basePath, oursPath, theirsPath} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		contents[i] = data
+	}
+
+	merged, conflicts, err := xcstrings.Merge(contents[0], contents[1], contents[2])
+	if err != nil {
+		// ours is left as it is, so git reports an ordinary conflict.
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s: %v\n", name, err)
+		return subcommands.ExitFailure
+	}
+	if err := atomicwrite.WriteFile(oursPath, merged, 0644); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	if len(conflicts) == 0 {
+		return subcommands.ExitSuccess
+	}
+	// git shows the driver's output while merging, so the report goes to
+	// stderr alongside git's own CONFLICT lines.
+	for _, conflict := range conflicts {
+		_, _ = fmt.Fprintf(os.Stderr, "CONFLICT (xcstrings): %s: %s\n  ours:   %q\n  theirs: %q\n", name, mergeConflictLabel(conflict), conflict.Ours, conflict.Theirs)
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "%d conflicts in %s; resolve the values between <<<<<<< and >>>>>>> markers\n", len(conflicts), name)
+	return subcommands.ExitFailure
+}
+
+// mergeConflictLabel names a conflict the way the import report names a
+// translation: key[path] (lang), or key and property for key-level ones.
+func mergeConflictLabel(c xcstrings.MergeConflict) string {
+	switch {
+	case c.Key == "":
+		return c.Path
+	case c.Language == "" && c.Path != "":
+		return c.Key + " " + c.Path
+	}
+	label := c.Key
+	if c.Path != "" {
+		label += "[" + c.Path + "]"
+	}
+	if c.Language != "" {
+		label += " (" + c.Language + ")"
+	}
+	return label
+}
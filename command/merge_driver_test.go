@@ -0,0 +1,107 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+func runMergeDriver(t *testing.T, args ...string) (string, int) {
+	t.Helper()
+	var status int
+	stderr := captureStderr(func() {
+		_, status = runCommand(t, &MergeDriverCommand{}, args...)
+	})
+	return stderr, status
+}
+
+// writeMergeSides writes base, ours and theirs versions of localizableFixture
+// with the given replacements applied to ours and theirs.
+func writeMergeSides(t *testing.T, ours, theirs [2]string) (string, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, 3)
+	for i, content := range []string{
+		localizableFixture,
+		strings.Replace(localizableFixture, ours[0], ours[1], 1),
+		strings.Replace(localizableFixture, theirs[0], theirs[1], 1),
+	} {
+		paths[i] = filepath.Join(dir, []string{"base", "ours", "theirs"}[i])
+		test.AssertNoError(t, os.WriteFile(paths[i], []byte(content), 0644))
+	}
+	return paths[0], paths[1], paths[2]
+}
+
+func TestMergeDriverCommand_Clean(t *testing.T) {
+	base, ours, theirs := writeMergeSides(t,
+		[2]string{`"Hello"`, `"Hello there"`},
+		[2]string{`"Bye"`, `"Bye now"`},
+	)
+
+	_, status := runMergeDriver(t, base, ours, theirs, "Localizable.xcstrings")
+	test.AssertEqual(t, status, 0)
+
+	xcs, err := xcstrings.Load(ours)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xcs.Strings["hello"].Localizations["en"].StringUnit.Value, "Hello there")
+	test.AssertEqual(t, xcs.Strings["bye"].Localizations["en"].StringUnit.Value, "Bye now")
+}
+
+func TestMergeDriverCommand_Conflict(t *testing.T) {
+	base, ours, theirs := writeMergeSides(t,
+		[2]string{`"Hello"`, `"Hi"`},
+		[2]string{`"Hello"`, `"Hey"`},
+	)
+
+	stderr, status := runMergeDriver(t, base, ours, theirs, "Localizable.xcstrings")
+	test.AssertEqual(t, status, 1)
+	for _, want := range []string{
+		"CONFLICT (xcstrings): Localizable.xcstrings: hello (en)",
+		`ours:   "Hi"`,
+		`theirs: "Hey"`,
+		"1 conflicts in Localizable.xcstrings",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("expected %q in report, got:\n%s", want, stderr)
+		}
+	}
+
+	// The result is still a catalog, with both versions in the value.
+	xcs, err := xcstrings.Load(ours)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xcs.Strings["hello"].Localizations["en"].StringUnit.Value, "<<<<<<< ours\nHi\n=======\nHey\n>>>>>>> theirs")
+}
+
+func TestMergeDriverCommand_Errors(t *testing.T) {
+	base, ours, theirs := writeMergeSides(t, [2]string{"", ""}, [2]string{"{", "["})
+
+	_, status := runMergeDriver(t, base, ours)
+	test.AssertEqual(t, status, 2)
+
+	// An unreadable side leaves ours untouched.
+	before, err := os.ReadFile(ours)
+	test.AssertNoError(t, err)
+	_, status = runMergeDriver(t, base, ours, theirs)
+	test.AssertEqual(t, status, 1)
+	after, err := os.ReadFile(ours)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(after), string(before))
+}
+
+func TestMergeConflictLabel(t *testing.T) {
+	for _, tc := range []struct {
+		conflict xcstrings.MergeConflict
+		want     string
+	}{
+		{xcstrings.MergeConflict{Path: "version"}, "version"},
+		{xcstrings.MergeConflict{Key: "greeting", Path: "comment"}, "greeting comment"},
+		{xcstrings.MergeConflict{Key: "greeting", Language: "ja"}, "greeting (ja)"},
+		{xcstrings.MergeConflict{Key: "items", Language: "ja", Path: "plural.one"}, "items[plural.one] (ja)"},
+	} {
+		test.AssertEqual(t, mergeConflictLabel(tc.conflict), tc.want)
+	}
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+const mergeSettingsFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"settings.title": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Settings"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "設定"}}
+		}},
+		"hello": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Hello"}},
+			"fr": {"stringUnit": {"state": "translated", "value": "Bonjour"}}
+		}}
+	},
+	"version": "1.0"
+}`
+
+func TestMergeCommand_ConflictPolicies(t *testing.T) {
+	appPath := test.TempFile(t, "Localizable.xcstrings", localizableFixture)
+	settingsPath := test.TempFile(t, "Settings.xcstrings", mergeSettingsFixture)
+
+	// hello differs between the catalogs.
+	_, status := runCommand(t, &MergeCommand{}, appPath, settingsPath)
+	test.AssertEqual(t, status, 1)
+	data, err := os.ReadFile(appPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(data), localizableFixture)
+
+	output, status := runCommand(t, &MergeCommand{}, "--on-conflict", "merge", appPath, settingsPath)
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, appPath+": 2 added, 0 kept, 0 replaced, 0 merged\n"+
+		settingsPath+": 1 added, 0 kept, 0 replaced, 1 merged\n"+
+		"Wrote 3 keys to "+appPath+"\n")
+
+	xcs, err := xcstrings.Load(appPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xcs.Strings["settings.title"].Localizations["ja"].StringUnit.Value, "設定")
+	hello := xcs.Strings["hello"].Localizations
+	test.AssertEqual(t, hello["ja"].StringUnit.Value, "こんにちは")
+	test.AssertEqual(t, hello["fr"].StringUnit.Value, "Bonjour")
+}
+
+func TestMergeCommand_OutputFile(t *testing.T) {
+	appPath := test.TempFile(t, "Localizable.xcstrings", localizableFixture)
+	settingsPath := test.TempFile(t, "Settings.xcstrings", strings.Replace(mergeSettingsFixture, `"Hello"`, `"Hi"`, 1))
+	outPath := filepath.Join(t.TempDir(), "All.xcstrings")
+
+	// merge can't reconcile two English values.
+	_, status := runCommand(t, &MergeCommand{}, "-o", outPath, "--on-conflict", "merge", appPath, settingsPath)
+	test.AssertEqual(t, status, 1)
+
+	output, status := runCommand(t, &MergeCommand{}, "-o", outPath, "--on-conflict", "last", "--dry-run", "--json", appPath, settingsPath)
+	test.AssertEqual(t, status, 0)
+	var out mergeJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertEqual(t, out.Summary.Keys, 3)
+	test.AssertSliceEqual(t, out.Inputs[1].Replaced, []string{"hello"})
+	if _, err := os.Stat(outPath); err == nil {
+		t.Error("--dry-run should not write the output")
+	}
+
+	_, status = runCommand(t, &MergeCommand{}, "-o", outPath, "--on-conflict", "first", appPath, settingsPath)
+	test.AssertEqual(t, status, 0)
+	xcs, err := xcstrings.Load(outPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xcs.Strings["hello"].Localizations["en"].StringUnit.Value, "Hello")
+	test.AssertEqual(t, len(xcs.Strings), 3)
+
+	// An unrelated existing file is not overwritten.
+	_, status = runCommand(t, &MergeCommand{}, "-o", outPath, appPath, settingsPath)
+	test.AssertEqual(t, status, 1)
+}
+
+func TestMergeCommand_UsageErrors(t *testing.T) {
+	appPath := test.TempFile(t, "Localizable.xcstrings", localizableFixture)
+
+	_, status := runCommand(t, &MergeCommand{}, appPath)
+	test.AssertEqual(t, status, 2)
+	_, status = runCommand(t, &MergeCommand{}, "--on-conflict", "newest", appPath, appPath)
+	test.AssertEqual(t, status, 2)
+}
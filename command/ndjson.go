@@ -0,0 +1,105 @@
+package command
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"sort"
+
+	"xckit/xcstrings"
+)
+
+// writeNDJSON writes one setStdinRow per leaf string unit, the schema
+// `set --stdin` reads, so the output can be edited and fed straight back.
+// Rows are ordered by key, then language (source first), with a
+// localization's host string ahead of its variations so that replaying
+// them recreates substitutions after the host string that references them.
+// language and state, when non-empty, keep only rows of that language and
+// string units in that translation state. A row's "state" is the key's
+// extractionState, as `set --stdin` interprets it.
+func writeNDJSON(w io.Writer, xc *xcstrings.XCStrings, language, state string) error {
+	langs := buildLanguageOrder(xc)
+	if language != "" {
+		langs = []string{language}
+	}
+
+	keys := make([]string, 0, len(xc.Strings))
+	for k := range xc.Strings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, key := range keys {
+		def := xc.Strings[key]
+		for _, lang := range langs {
+			for _, row := range ndjsonRows(key, def, lang, state) {
+				if err := enc.Encode(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ndjsonRows returns the rows for the leaf string units of one key and
+// language, keeping only units in state when it is non-empty. Substitution
+// variations under a device can't be expressed as a row and are skipped with
+// a warning.
+func ndjsonRows(key string, def xcstrings.StringDefinition, lang, state string) []setStdinRow {
+	loc, ok := def.Localizations[lang]
+	if !ok {
+		return nil
+	}
+
+	var comment *string
+	if def.Comment != "" {
+		comment = &def.Comment
+	}
+
+	paths := collectVariationSuffixes(def, []string{lang})
+	sort.Strings(paths)
+	if loc.StringUnit != nil {
+		paths = append([]string{""}, paths...)
+	}
+
+	var rows []setStdinRow
+	for _, path := range paths {
+		unit := localizationUnit(loc, path)
+		if unit == nil || (state != "" && unit.State != state) {
+			continue
+		}
+		row := setStdinRow{
+			Key:     key,
+			Lang:    lang,
+			Value:   unit.Value,
+			State:   def.ExtractionState,
+			Comment: comment,
+		}
+
+		parts := splitPath(path)
+		if len(parts) >= 2 && parts[0] == "substitutions" {
+			opts, err := parseVariationOpts(parts[2:])
+			if err != nil || opts.Device != "" {
+				log.Printf("Warning: skipping key %q lang %q path %q: substitution device variations can't be written as set --stdin rows", key, lang, path)
+				continue
+			}
+			sub := loc.Substitutions[parts[1]]
+			row.Substitution = parts[1]
+			row.Plural = opts.Plural
+			row.ArgNum = sub.ArgNum
+			row.FormatSpecifier = sub.FormatSpecifier
+		} else if path != "" {
+			opts, err := parseVariationOpts(parts)
+			if err != nil {
+				continue
+			}
+			row.Plural = opts.Plural
+			row.Device = opts.Device
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
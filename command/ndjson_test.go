@@ -0,0 +1,81 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+func TestWriteNDJSON(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	var buf bytes.Buffer
+	test.AssertNoError(t, writeNDJSON(&buf, xc, "", ""))
+
+	want := `{"key":"debug","lang":"en","value":"Debug"}
+{"key":"files","lang":"en","value":"%#@files@"}
+{"key":"files","lang":"en","value":"%arg file","plural":"one","substitution":"files","argNum":1,"formatSpecifier":"lld"}
+{"key":"files","lang":"en","value":"%arg files","plural":"other","substitution":"files","argNum":1,"formatSpecifier":"lld"}
+{"key":"greeting","lang":"en","value":"Hello & welcome","comment":"Shown on the home screen"}
+{"key":"greeting","lang":"ja","value":"こんにちは","comment":"Shown on the home screen"}
+{"key":"item_count","lang":"en","value":"%lld item","plural":"one"}
+{"key":"item_count","lang":"en","value":"%lld items","plural":"other"}
+{"key":"item_count","lang":"ja","value":"%lld 個","plural":"other"}
+`
+	test.AssertEqual(t, buf.String(), want)
+}
+
+func TestWriteNDJSON_Filters(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	var buf bytes.Buffer
+	test.AssertNoError(t, writeNDJSON(&buf, xc, "ja", "needs_review"))
+	test.AssertEqual(t, buf.String(), `{"key":"greeting","lang":"ja","value":"こんにちは","comment":"Shown on the home screen"}`+"\n")
+}
+
+func TestExportNDJSON_FeedsBackIntoSetStdin(t *testing.T) {
+	srcPath := test.TempFile(t, "src.xcstrings", xliffFixture)
+
+	exportCmd := &ExportCommand{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	exportCmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", srcPath, "--format", "ndjson"}))
+	rows := captureOutput(func() {
+		test.AssertEqual(t, int(exportCmd.Execute(context.Background(), flagSet)), 0)
+	})
+
+	dstPath := test.TempFile(t, "dst.xcstrings", `{"sourceLanguage": "en", "strings": {}, "version": "1.0"}`)
+	setCmd := &SetCommand{}
+	flagSet = flag.NewFlagSet("test", flag.ContinueOnError)
+	setCmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", dstPath, "--stdin", "--allow-new-language"}))
+	withStdin(t, rows, func() {
+		captureOutput(func() {
+			test.AssertEqual(t, int(setCmd.Execute(context.Background(), flagSet)), 0)
+		})
+	})
+
+	dst, err := xcstrings.Load(dstPath)
+	test.AssertNoError(t, err)
+	var replayed bytes.Buffer
+	test.AssertNoError(t, writeNDJSON(&replayed, dst, "", ""))
+	test.AssertEqual(t, replayed.String(), rows)
+}
+
+func TestExportCommand_StateRequiresNDJSON(t *testing.T) {
+	xcPath := test.TempFile(t, "test.xcstrings", xliffFixture)
+
+	cmd := &ExportCommand{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", "csv", "--state", "new"}))
+	test.AssertEqual(t, int(cmd.Execute(context.Background(), flagSet)), 1)
+}
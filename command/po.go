@@ -0,0 +1,376 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"xckit/xcstrings"
+)
+
+// poEntry is a single message of a gettext PO file.
+type poEntry struct {
+	comments    []string // extracted comments (#.)
+	flags       []string // flags (#,)
+	msgctxt     string
+	hasMsgctxt  bool
+	msgid       string
+	msgidPlural string
+	msgstr      string
+	// plural is set when the entry carries msgid_plural/msgstr[N] forms,
+	// which catalogs express as plural variations instead.
+	plural bool
+}
+
+func (e *poEntry) fuzzy() bool {
+	for _, f := range e.flags {
+		if f == "fuzzy" {
+			return true
+		}
+	}
+	return false
+}
+
+// writePO exports the catalog as gettext files under dir: a <table>.pot
+// template holding the source strings, plus one <lang>.po per target
+// language (only language, when non-empty). Each variation leaf is its own
+// message. msgctxt carries the key, with the variation path in CSV bracket
+// notation (e.g. "item_count[plural.one]"), so messages sharing a source text
+// stay distinct. Keys marked shouldTranslate: false are left out.
+func writePO(dir string, xc *xcstrings.XCStrings, table, language string) error {
+	langs := buildLanguageOrder(xc)[1:]
+	if language != "" {
+		langs = []string{language}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	writePOFile(&buf, xc, table, "")
+	if err := os.WriteFile(filepath.Join(dir, table+".pot"), buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	for _, lang := range langs {
+		buf.Reset()
+		writePOFile(&buf, xc, table, lang)
+		if err := os.WriteFile(filepath.Join(dir, lang+".po"), buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePOFile writes the PO file for lang, or the POT template when lang is
+// empty. needs_review translations are flagged fuzzy.
+func writePOFile(w *bytes.Buffer, xc *xcstrings.XCStrings, table, lang string) {
+	header := "Project-Id-Version: " + table + "\n" +
+		"MIME-Version: 1.0\n" +
+		"Content-Type: text/plain; charset=UTF-8\n" +
+		"Content-Transfer-Encoding: 8bit\n"
+	if lang != "" {
+		header += "Language: " + lang + "\n"
+	}
+	header += "X-Generator: xckit " + Version + "\n"
+	writePOField(w, "msgid", "")
+	writePOField(w, "msgstr", header)
+
+	target := lang
+	if target == "" {
+		target = xc.SourceLanguage
+	}
+
+	keys := make([]string, 0, len(xc.Strings))
+	for k := range xc.Strings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		def := xc.Strings[key]
+		if def.ShouldTranslate != nil && !*def.ShouldTranslate {
+			continue
+		}
+		srcLoc := def.Localizations[xc.SourceLanguage]
+		for _, path := range translationPaths(def, xc.SourceLanguage, target) {
+			w.WriteString("\n")
+			if def.Comment != "" {
+				for _, line := range strings.Split(def.Comment, "\n") {
+					w.WriteString(strings.TrimRight("#. "+line, " ") + "\n")
+				}
+			}
+
+			msgstr := ""
+			if lang != "" {
+				if su := localizationUnit(def.Localizations[lang], path); su != nil {
+					msgstr = su.Value
+					if su.State == "needs_review" {
+						w.WriteString("#, fuzzy\n")
+					}
+				}
+			}
+
+			ctxt := key
+			if path != "" {
+				ctxt = key + "[" + path + "]"
+			}
+			writePOField(w, "msgctxt", ctxt)
+			writePOField(w, "msgid", xliffSourceText(key, srcLoc, path))
+			writePOField(w, "msgstr", msgstr)
+		}
+	}
+}
+
+// writePOField writes a keyword and its quoted string. Values spanning
+// several lines are written gettext-style: an empty first string followed by
+// one string per line.
+func writePOField(w *bytes.Buffer, keyword, value string) {
+	if !strings.Contains(strings.TrimSuffix(value, "\n"), "\n") {
+		fmt.Fprintf(w, "%s %s\n", keyword, quotePO(value))
+		return
+	}
+	fmt.Fprintf(w, "%s \"\"\n", keyword)
+	for _, line := range strings.SplitAfter(value, "\n") {
+		if line != "" {
+			w.WriteString(quotePO(line) + "\n")
+		}
+	}
+}
+
+// quotePO quotes s as a PO string literal.
+func quotePO(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// unquotePO reverses quotePO, also accepting the other C escapes gettext
+// tools may write.
+func unquotePO(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("expected a quoted string, got %s", s)
+	}
+	s = s[1 : len(s)-1]
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// parsePO reads the messages of a PO or POT file, including the header
+// entry. Obsolete (#~) messages are dropped.
+func parsePO(r io.Reader) ([]poEntry, error) {
+	var entries []poEntry
+	var cur poEntry
+	var field *string
+	started := false // cur has a msgid
+	seenMsgstr := false
+
+	flush := func() {
+		if started {
+			entries = append(entries, cur)
+		}
+		cur = poEntry{}
+		field = nil
+		started = false
+		seenMsgstr = false
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#~"):
+			continue
+		case strings.HasPrefix(line, "#"):
+			if seenMsgstr {
+				flush()
+			}
+			switch {
+			case strings.HasPrefix(line, "#."):
+				cur.comments = append(cur.comments, strings.TrimSpace(line[2:]))
+			case strings.HasPrefix(line, "#,"):
+				for _, f := range strings.Split(line[2:], ",") {
+					cur.flags = append(cur.flags, strings.TrimSpace(f))
+				}
+			}
+			continue
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				return nil, fmt.Errorf("line %d: string without a keyword", lineNum)
+			}
+			s, err := unquotePO(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			*field += s
+			continue
+		}
+
+		keyword, rest, _ := strings.Cut(line, " ")
+		value, err := unquotePO(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if (keyword == "msgctxt" || keyword == "msgid") && seenMsgstr {
+			flush()
+		}
+		switch {
+		case keyword == "msgctxt":
+			cur.msgctxt, cur.hasMsgctxt = value, true
+			field = &cur.msgctxt
+		case keyword == "msgid":
+			cur.msgid, started = value, true
+			field = &cur.msgid
+		case keyword == "msgid_plural":
+			cur.msgidPlural, cur.plural = value, true
+			field = &cur.msgidPlural
+		case keyword == "msgstr":
+			cur.msgstr, seenMsgstr = value, true
+			field = &cur.msgstr
+		case strings.HasPrefix(keyword, "msgstr["):
+			// Plural forms are not mapped; keep the text only to consume
+			// continuation lines.
+			cur.plural, seenMsgstr = true, true
+			discard := value
+			field = &discard
+		default:
+			return nil, fmt.Errorf("line %d: unknown keyword %q", lineNum, keyword)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return entries, nil
+}
+
+// poHeaderField returns the value of a "Name: value" line of a PO header.
+func poHeaderField(header, name string) string {
+	for _, line := range strings.Split(header, "\n") {
+		k, v, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(k), name) {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// importPO reads a PO file (as produced by writePO, or edited by a gettext
+// tool) and applies every translated message to the language named in its
+// Language header. msgctxt is read as the key with an optional bracketed
+// variation path, like a CSV key column; a message without msgctxt uses its
+// msgid as the key. Fuzzy messages are imported as needs_review, others as
+// translated, and an empty msgstr is treated like an empty CSV cell.
+func importPO(r io.Reader, xc *xcstrings.XCStrings, onMissingKey string, clearEmpty bool) (*importSummary, error) {
+	entries, err := parsePO(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PO: %w", err)
+	}
+
+	lang := ""
+	for _, e := range entries {
+		if !e.hasMsgctxt && e.msgid == "" {
+			lang = strings.ReplaceAll(poHeaderField(e.msgstr, "Language"), "_", "-")
+			break
+		}
+	}
+	if lang == "" {
+		return nil, fmt.Errorf("PO file has no Language header (is it a .pot template?)")
+	}
+
+	summary := &importSummary{}
+	if lang == xc.SourceLanguage {
+		return summary, nil
+	}
+
+	for _, e := range entries {
+		rawKey := e.msgid
+		if e.hasMsgctxt {
+			rawKey = e.msgctxt
+		} else if e.msgid == "" {
+			continue // header
+		}
+		key, variationPath := parseKeyBracket(rawKey)
+
+		if _, exists := xc.Strings[key]; !exists {
+			if onMissingKey == "error" {
+				return nil, fmt.Errorf("key not found: %s", key)
+			}
+			summary.skip(key, lang, variationPath, skipMissingKey, "key not found: "+key)
+			continue
+		}
+		if e.plural {
+			summary.skip(key, lang, variationPath, skipMsgidPlural, fmt.Sprintf("msgid_plural messages are not supported; use msgctxt %q instead", key+"[plural.<category>]"))
+			continue
+		}
+
+		state := "translated"
+		if e.fuzzy() {
+			state = "needs_review"
+		}
+		if e.msgstr == "" {
+			state = ""
+		}
+		applyImportValue(xc, summary, key, lang, variationPath, e.msgstr, state, clearEmpty)
+	}
+
+	return summary, nil
+}
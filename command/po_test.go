@@ -0,0 +1,202 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+func TestWritePOFile(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	var buf bytes.Buffer
+	writePOFile(&buf, xc, "Localizable", "ja")
+	out := buf.String()
+
+	expected := []string{
+		"msgid \"\"\nmsgstr \"\"\n\"Project-Id-Version: Localizable\\n\"\n",
+		"\"Language: ja\\n\"\n",
+		"\n#. Shown on the home screen\n#, fuzzy\nmsgctxt \"greeting\"\nmsgid \"Hello & welcome\"\nmsgstr \"こんにちは\"\n",
+		"\nmsgctxt \"item_count[plural.other]\"\nmsgid \"%lld items\"\nmsgstr \"%lld 個\"\n",
+		"\nmsgctxt \"item_count[plural.one]\"\nmsgid \"%lld item\"\nmsgstr \"\"\n",
+		"\nmsgctxt \"files[substitutions.files.plural.one]\"\nmsgid \"%arg file\"\nmsgstr \"\"\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected PO output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"debug"`) {
+		t.Errorf("shouldTranslate: false keys should be left out:\n%s", out)
+	}
+}
+
+func TestWritePO_TemplateAndLanguages(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	dir := t.TempDir()
+	test.AssertNoError(t, writePO(dir, xc, "Localizable", ""))
+
+	pot, err := os.ReadFile(filepath.Join(dir, "Localizable.pot"))
+	test.AssertNoError(t, err)
+	if strings.Contains(string(pot), "Language:") || strings.Contains(string(pot), "こんにちは") {
+		t.Errorf("template should carry no language or translations:\n%s", pot)
+	}
+	if !strings.Contains(string(pot), "msgctxt \"greeting\"\nmsgid \"Hello & welcome\"\nmsgstr \"\"\n") {
+		t.Errorf("template is missing the source strings:\n%s", pot)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "ja.po")); err != nil {
+		t.Errorf("expected ja.po: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "en.po")); !os.IsNotExist(err) {
+		t.Error("the source language should not get a .po")
+	}
+}
+
+func TestWritePOField_Multiline(t *testing.T) {
+	var buf bytes.Buffer
+	writePOField(&buf, "msgid", "line one\nline \"two\"\n")
+	test.AssertEqual(t, buf.String(), "msgid \"\"\n\"line one\\n\"\n\"line \\\"two\\\"\\n\"\n")
+}
+
+func TestImportPO(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	po := `# Translator comment
+msgid ""
+msgstr ""
+"Language: ja\n"
+"Plural-Forms: nplurals=1; plural=0;\n"
+
+#. Shown on the home screen
+msgctxt "greeting"
+msgid "Hello & welcome"
+msgstr ""
+"ようこそ\n"
+"こんにちは"
+
+#, fuzzy, c-format
+msgctxt "item_count[plural.one]"
+msgid "%lld item"
+msgstr "%lld 個"
+msgctxt "files[substitutions.files.plural.other]"
+msgid "%arg files"
+msgstr "%arg 個のファイル"
+
+msgctxt "missing"
+msgid "Missing"
+msgstr "欠落"
+
+msgctxt "item_count"
+msgid "plural"
+msgid_plural "plurals"
+msgstr[0] "複数"
+
+#~ msgctxt "obsolete"
+#~ msgid "Old"
+#~ msgstr "古い"
+`
+	summary, err := importPO(strings.NewReader(po), xc, "skip", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.created, 2)
+	test.AssertEqual(t, summary.updated, 1)
+	test.AssertEqual(t, summary.skipped, 2)
+	var reasons []string
+	for _, c := range summary.changes {
+		if c.Action == "skipped" {
+			reasons = append(reasons, c.Key+": "+c.Reason)
+		}
+	}
+	test.AssertSliceEqual(t, reasons, []string{"missing: missing-key", "item_count: msgid-plural"})
+
+	ja := xc.Strings["greeting"].Localizations["ja"].StringUnit
+	test.AssertEqual(t, ja.Value, "ようこそ\nこんにちは")
+	test.AssertEqual(t, ja.State, "translated")
+
+	one := xc.Strings["item_count"].Localizations["ja"].Variations.Plural["one"].StringUnit
+	test.AssertEqual(t, one.Value, "%lld 個")
+	test.AssertEqual(t, one.State, "needs_review")
+
+	sub := xc.Strings["files"].Localizations["ja"].Substitutions["files"]
+	test.AssertEqual(t, sub.Variations.Plural["other"].StringUnit.Value, "%arg 個のファイル")
+}
+
+func TestImportPO_RequiresLanguage(t *testing.T) {
+	xc := &xcstrings.XCStrings{SourceLanguage: "en", Strings: map[string]xcstrings.StringDefinition{}}
+	_, err := importPO(strings.NewReader("msgid \"\"\nmsgstr \"Content-Type: text/plain; charset=UTF-8\\n\"\n"), xc, "skip", false)
+	test.AssertError(t, err)
+}
+
+func TestImportPO_NormalizesLanguage(t *testing.T) {
+	xc := &xcstrings.XCStrings{SourceLanguage: "en", Strings: map[string]xcstrings.StringDefinition{
+		"greeting": {Localizations: map[string]xcstrings.Localization{}},
+	}}
+	po := "msgid \"\"\nmsgstr \"Language: pt_BR\\n\"\n\nmsgctxt \"greeting\"\nmsgid \"Hello\"\nmsgstr \"Olá\"\n"
+	_, err := importPO(strings.NewReader(po), xc, "error", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["greeting"].Localizations["pt-BR"].StringUnit.Value, "Olá")
+}
+
+func TestPO_RoundTrip_NoEditsAreAllUnchanged(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	var buf bytes.Buffer
+	writePOFile(&buf, xc, "test", "ja")
+
+	summary, err := importPO(&buf, xc, "error", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.created, 0)
+	test.AssertEqual(t, summary.updated, 0)
+	test.AssertEqual(t, summary.skipped, 0)
+}
+
+func TestExportImportCommand_PO(t *testing.T) {
+	xcPath := test.TempFile(t, "Localizable.xcstrings", xliffFixture)
+	dir := t.TempDir()
+
+	exportCmd := &ExportCommand{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	exportCmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", "po", "-o", dir}))
+	test.AssertEqual(t, int(exportCmd.Execute(context.Background(), flagSet)), 0)
+
+	poPath := filepath.Join(dir, "ja.po")
+	data, err := os.ReadFile(poPath)
+	test.AssertNoError(t, err)
+	edited := strings.Replace(string(data), "#, fuzzy\nmsgctxt \"greeting\"\nmsgid \"Hello & welcome\"\nmsgstr \"こんにちは\"", "msgctxt \"greeting\"\nmsgid \"Hello & welcome\"\nmsgstr \"やあ\"", 1)
+	test.AssertNoError(t, os.WriteFile(poPath, []byte(edited), 0644))
+
+	importCmd := &ImportCommand{}
+	flagSet = flag.NewFlagSet("test", flag.ContinueOnError)
+	importCmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", "po", poPath}))
+
+	output := captureOutput(func() {
+		test.AssertEqual(t, int(importCmd.Execute(context.Background(), flagSet)), 0)
+	})
+	if !strings.Contains(output, "1 updated") {
+		t.Errorf("expected 1 updated, got: %q", output)
+	}
+
+	xc, err := xcstrings.Load(xcPath)
+	test.AssertNoError(t, err)
+	unit := xc.Strings["greeting"].Localizations["ja"].StringUnit
+	test.AssertEqual(t, unit.Value, "やあ")
+	test.AssertEqual(t, unit.State, "translated")
+}
@@ -0,0 +1,199 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"xckit/tm"
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+type PrefillCommand struct {
+	XCStringsCommand
+	memories   stringsFlag
+	language   string
+	prefix     string
+	minScore   float64
+	dryRun     bool
+	jsonOutput bool
+}
+
+func (*PrefillCommand) Name() string {
+	return "prefill"
+}
+
+func (*PrefillCommand) Synopsis() string {
+	return "Fill untranslated strings from a translation memory"
+}
+
+func (*PrefillCommand) Usage() string {
+	return "prefill [-f file.xcstrings] [--tm other.xcstrings ...] [--lang <language>] [--prefix <prefix>] [--min-score <0-1>] [--dry-run] [--json]: Fill untranslated strings with exact (translated) and fuzzy (needs_review) matches from translated strings of this and other catalogs\n"
+}
+
+func (c *PrefillCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.Var(&c.memories, "tm", "Additional .xcstrings file to use as translation memory (repeatable)")
+	f.StringVar(&c.language, "lang", "", "Only fill this language (default: every language of the catalog)")
+	f.StringVar(&c.prefix, "prefix", "", "Only fill keys with this prefix")
+	f.Float64Var(&c.minScore, "min-score", 0.8, "Minimum similarity (0-1) of a fuzzy match")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show the matches without writing the file")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output a structured JSON document describing the matches")
+}
+
+// prefillJSONResult is a single filled unit in `prefill --json` output.
+type prefillJSONResult struct {
+	Key         string  `json:"key"`
+	Path        string  `json:"path,omitempty"`
+	Language    string  `json:"language"`
+	Source      string  `json:"source"`
+	Translation string  `json:"translation"`
+	Match       string  `json:"match"` // "exact" or "fuzzy"
+	Score       float64 `json:"score"`
+	MatchedKey  string  `json:"matchedKey"`
+	MatchedText string  `json:"matchedSource"`
+	Origin      string  `json:"origin"`
+}
+
+// prefillJSONOutput is the top-level document printed by `prefill --json`.
+type prefillJSONOutput struct {
+	DryRun  bool                `json:"dryRun"`
+	Results []prefillJSONResult `json:"results"`
+	Summary struct {
+		Exact     int `json:"exact"`
+		Fuzzy     int `json:"fuzzy"`
+		Unmatched int `json:"unmatched"`
+	} `json:"summary"`
+}
+
+func (c *PrefillCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.minScore <= 0 || c.minScore > 1 {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --min-score must be greater than 0 and at most 1\n")
+		return subcommands.ExitUsageError
+	}
+
+	return c.forEachCatalog(c.jsonOutput, c.execute)
+}
+
+func (c *PrefillCommand) execute() subcommands.ExitStatus {
+	xcs, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	if c.language != "" && c.language == xcs.SourceLanguage {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --lang %s is the source language\n", c.language)
+		return subcommands.ExitUsageError
+	}
+
+	filePath := c.filePath
+	if filePath == "" {
+		filePath = c.findXCStringsFile()
+	}
+
+	// The catalog's own translations come first, so they win exact-match
+	// ties against the other memories.
+	memory := tm.New()
+	memory.AddCatalog(xcs, filePath)
+	for _, path := range c.memories {
+		if samePath(path, filePath) {
+			continue
+		}
+		other, err := xcstrings.Load(path)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s: %v\n", path, err)
+			return subcommands.ExitFailure
+		}
+		if other.SourceLanguage != xcs.SourceLanguage {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Warning: %s has source language %s, not %s; none of its translations can match\n", path, other.SourceLanguage, xcs.SourceLanguage)
+		}
+		memory.AddCatalog(other, path)
+	}
+
+	languages := []string{c.language}
+	if c.language == "" {
+		languages = c.targetLanguages(xcs)
+	}
+
+	out := prefillJSONOutput{DryRun: c.dryRun, Results: []prefillJSONResult{}}
+	for _, lang := range languages {
+		for _, u := range collectTranslateUnits(xcs, lang, c.prefix) {
+			match, ok := memory.Lookup(xcs.SourceLanguage, lang, tm.Category(u.path), u.source, c.minScore)
+			if !ok {
+				out.Summary.Unmatched++
+				continue
+			}
+			if err := setTranslation(xcs, u.key, lang, match.Target, u.path); err != nil {
+				_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Warning: %s (%s): %v\n", unitLabel(u.key, u.path), lang, err)
+				out.Summary.Unmatched++
+				continue
+			}
+			kind := "exact"
+			if match.Exact() {
+				out.Summary.Exact++
+			} else {
+				kind = "fuzzy"
+				if unit := currentTranslationUnit(xcs, u.key, lang, u.path); unit != nil {
+					unit.State = "needs_review"
+				}
+				out.Summary.Fuzzy++
+			}
+			out.Results = append(out.Results, prefillJSONResult{
+				Key:         u.key,
+				Path:        u.path,
+				Language:    lang,
+				Source:      u.source,
+				Translation: match.Target,
+				Match:       kind,
+				Score:       match.Score,
+				MatchedKey:  match.Key,
+				MatchedText: match.Source,
+				Origin:      match.Origin,
+			})
+		}
+	}
+
+	if !c.dryRun && len(out.Results) > 0 {
+		if err := xcs.SaveToFile(filePath); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	if c.jsonOutput {
+		return c.printJSON(out)
+	}
+
+	prefix := ""
+	if c.dryRun {
+		prefix = "[dry-run] "
+	}
+	for _, r := range out.Results {
+		if r.Match == "exact" {
+			fmt.Printf("%s%s (%s): exact %q (from %s in %s)\n", prefix, unitLabel(r.Key, r.Path), r.Language, r.Translation, r.MatchedKey, r.Origin)
+			continue
+		}
+		fmt.Printf("%s%s (%s): fuzzy %.0f%% %q (from %s in %s, source %q)\n", prefix, unitLabel(r.Key, r.Path), r.Language, r.Score*100, r.Translation, r.MatchedKey, r.Origin, r.MatchedText)
+	}
+	fmt.Printf("%sSummary: %d exact (translated), %d fuzzy (needs_review), %d unmatched\n", prefix, out.Summary.Exact, out.Summary.Fuzzy, out.Summary.Unmatched)
+	return subcommands.ExitSuccess
+}
+
+// unitLabel formats a key and variation path as key[path], or just key for
+// the plain string.
+func unitLabel(key, path string) string {
+	if path == "" {
+		return key
+	}
+	return key + "[" + path + "]"
+}
+
+// samePath reports whether a and b name the same file.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,141 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+// prefillMemoryFixture holds French translations whose source texts match
+// xliffFixture's exactly (item_count, the files substitution's "one" form)
+// or nearly (greeting).
+const prefillMemoryFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"welcome": {
+			"localizations": {
+				"en": {"stringUnit": {"state": "translated", "value": "Hello & welcome!"}},
+				"fr": {"stringUnit": {"state": "translated", "value": "Bonjour et bienvenue !"}}
+			}
+		},
+		"rows": {
+			"localizations": {
+				"en": {"variations": {"plural": {
+					"one": {"stringUnit": {"state": "translated", "value": "%lld item"}},
+					"other": {"stringUnit": {"state": "translated", "value": "%lld items"}}
+				}}},
+				"fr": {"variations": {"plural": {
+					"one": {"stringUnit": {"state": "translated", "value": "%lld élément"}},
+					"other": {"stringUnit": {"state": "translated", "value": "%lld éléments"}}
+				}}}
+			}
+		},
+		"attachment": {
+			"localizations": {
+				"en": {"variations": {"plural": {
+					"one": {"stringUnit": {"state": "translated", "value": "%arg file"}}
+				}}},
+				"fr": {"variations": {"plural": {
+					"one": {"stringUnit": {"state": "translated", "value": "%arg fichier"}}
+				}}}
+			}
+		}
+	},
+	"version": "1.0"
+}`
+
+func TestPrefillCommand_ExactAndFuzzyMatches(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	memoryPath := test.TempFile(t, "memory.xcstrings", prefillMemoryFixture)
+
+	output, status := runCommand(t, &PrefillCommand{}, "-f", filePath, "--tm", memoryPath, "--lang", "fr")
+	test.AssertEqual(t, status, 0)
+	if !strings.Contains(output, "Summary: 3 exact (translated), 1 fuzzy (needs_review), 2 unmatched") {
+		t.Errorf("unexpected summary: %q", output)
+	}
+
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	greeting := xc.Strings["greeting"].Localizations["fr"].StringUnit
+	test.AssertEqual(t, greeting.Value, "Bonjour et bienvenue !")
+	test.AssertEqual(t, greeting.State, "needs_review")
+
+	other := xc.Strings["item_count"].Localizations["fr"].Variations.Plural["other"].StringUnit
+	test.AssertEqual(t, other.Value, "%lld éléments")
+	test.AssertEqual(t, other.State, "translated")
+
+	// A plural form matches across variation structures: the substitution's
+	// "one" form comes from a top-level plural.
+	files := xc.Strings["files"].Localizations["fr"]
+	test.AssertEqual(t, files.Substitutions["files"].Variations.Plural["one"].StringUnit.Value, "%arg fichier")
+	test.AssertEqual(t, files.Substitutions["files"].ArgNum, 1)
+}
+
+func TestPrefillCommand_MinScore(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	memoryPath := test.TempFile(t, "memory.xcstrings", prefillMemoryFixture)
+
+	output, status := runCommand(t, &PrefillCommand{}, "-f", filePath, "--tm", memoryPath, "--lang", "fr", "--min-score", "1", "--dry-run")
+	test.AssertEqual(t, status, 0)
+	if !strings.Contains(output, "[dry-run] Summary: 3 exact (translated), 0 fuzzy (needs_review), 3 unmatched") {
+		t.Errorf("unexpected summary: %q", output)
+	}
+
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	if _, ok := xc.Strings["item_count"].Localizations["fr"]; ok {
+		t.Error("--dry-run should not write the file")
+	}
+}
+
+func TestPrefillCommand_UsesOwnCatalog(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", `{
+	"sourceLanguage": "en",
+	"strings": {
+		"alert.ok": {
+			"localizations": {
+				"en": {"stringUnit": {"state": "translated", "value": "OK"}},
+				"ja": {"stringUnit": {"state": "translated", "value": "了解"}}
+			}
+		},
+		"dialog.ok": {
+			"localizations": {
+				"en": {"stringUnit": {"state": "translated", "value": "OK"}}
+			}
+		}
+	},
+	"version": "1.0"
+}`)
+
+	output, status := runCommand(t, &PrefillCommand{}, "-f", filePath, "--json")
+	test.AssertEqual(t, status, 0)
+	var out prefillJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertEqual(t, len(out.Results), 1)
+	r := out.Results[0]
+	test.AssertEqual(t, r.Key, "dialog.ok")
+	test.AssertEqual(t, r.Language, "ja")
+	test.AssertEqual(t, r.Match, "exact")
+	test.AssertEqual(t, r.MatchedKey, "alert.ok")
+	test.AssertEqual(t, r.Translation, "了解")
+
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["dialog.ok"].Localizations["ja"].StringUnit.Value, "了解")
+}
+
+func TestPrefillCommand_InvalidMinScore(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	for _, score := range []string{"0", "1.5"} {
+		cmd := &PrefillCommand{}
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		cmd.SetFlags(flagSet)
+		test.AssertNoError(t, flagSet.Parse([]string{"-f", filePath, "--min-score", score}))
+		test.AssertEqual(t, int(cmd.Execute(context.Background(), flagSet)), 2)
+	}
+}
@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+	"sort"
+
+	"xckit/translator"
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+type PseudoCommand struct {
+	XCStringsCommand
+	language   string
+	expansion  float64
+	brackets   bool
+	rtl        bool
+	dryRun     bool
+	force      bool
+	jsonOutput bool
+}
+
+func (*PseudoCommand) Name() string {
+	return "pseudo"
+}
+
+func (*PseudoCommand) Synopsis() string {
+	return "Generate a pseudo-localization to test layouts before translation"
+}
+
+func (*PseudoCommand) Usage() string {
+	return "pseudo [-f file.xcstrings] --lang <language> [--expand 0.3] [--brackets=false] [--rtl] [--force] [--dry-run] [--json]: Write a pseudo-localized copy of every active key's source text to <language> (e.g. en-XA): accented, lengthened and bracketed, with format specifiers, substitutions and plural and device variations kept, so hard-coded strings, truncation and clipping stand out when the app runs in that language\n"
+}
+
+func (c *PseudoCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.StringVar(&c.language, "lang", "", "Pseudo-locale language code, e.g. en-XA (required)")
+	f.Float64Var(&c.expansion, "expand", 0.3, "Lengthen each string by this fraction of its letters")
+	f.BoolVar(&c.brackets, "brackets", true, "Wrap each string in [ and ] to show where it is clipped")
+	f.BoolVar(&c.rtl, "rtl", false, "Force right-to-left text, to check mirrored layouts")
+	f.BoolVar(&c.force, "force", false, "Replace the language's translations even if they aren't a pseudo-localization")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show what would be generated without writing the file")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output a structured JSON document describing the result")
+}
+
+// pseudoJSONOutput is the top-level document printed by `pseudo --json`.
+type pseudoJSONOutput struct {
+	DryRun   bool   `json:"dryRun"`
+	Language string `json:"language"`
+	Summary  struct {
+		Keys    int `json:"keys"`
+		Strings int `json:"strings"`
+	} `json:"summary"`
+}
+
+func (c *PseudoCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.language == "" || c.expansion < 0 || f.NArg() > 0 {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --lang is required and --expand must not be negative\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+	// The pseudo-locale is rewritten from scratch, which would wipe a real
+	// translation.
+	if c.config != nil && slices.Contains(c.config.Languages, c.language) {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --lang %s is a configured translation language\n", c.language)
+		return subcommands.ExitUsageError
+	}
+
+	return c.forEachCatalog(c.jsonOutput, c.execute)
+}
+
+func (c *PseudoCommand) execute() subcommands.ExitStatus {
+	xcs, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	if c.language == xcs.SourceLanguage {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --lang %s is the source language\n", c.language)
+		return subcommands.ExitUsageError
+	}
+	if key, value, ok := c.realTranslation(xcs); ok && !c.force {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --lang %s has real translations (%s: %q); pass --force to replace them\n", c.language, key, value)
+		return subcommands.ExitUsageError
+	}
+
+	pseudo := translator.Pseudo{Expansion: c.expansion, Brackets: c.brackets, RTL: c.rtl}
+	out := pseudoJSONOutput{DryRun: c.dryRun, Language: c.language}
+	keys := xcs.ActiveKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if def := xcs.Strings[key]; def.ShouldTranslate != nil && !*def.ShouldTranslate {
+			continue
+		}
+		n, err := xcs.DeriveLocalization(key, c.language, pseudo.Localize)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		out.Summary.Keys++
+		out.Summary.Strings += n
+	}
+
+	if !c.dryRun && out.Summary.Keys > 0 {
+		filePath := c.filePath
+		if filePath == "" {
+			filePath = c.findXCStringsFile()
+		}
+		if err := xcs.SaveToFile(filePath); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	if c.jsonOutput {
+		return c.printJSON(out)
+	}
+
+	prefix := ""
+	if c.dryRun {
+		prefix = "[dry-run] "
+	}
+	fmt.Printf("%sSummary: %d string units of %d keys pseudo-localized into %s\n", prefix, out.Summary.Strings, out.Summary.Keys, c.language)
+	return subcommands.ExitSuccess
+}
+
+// realTranslation returns a translated string of the pseudo-locale's
+// language that isn't a pseudo-localization of its source text, which a run
+// would wipe.
+func (c *PseudoCommand) realTranslation(xcs *xcstrings.XCStrings) (key, value string, ok bool) {
+	for _, key := range sortedKeys(xcs.Strings) {
+		def := xcs.Strings[key]
+		loc, exists := def.Localizations[c.language]
+		if !exists {
+			continue
+		}
+		srcLoc := def.Localizations[xcs.SourceLanguage]
+		for _, path := range translationPaths(def, xcs.SourceLanguage, c.language) {
+			su := localizationUnit(loc, path)
+			if su != nil && su.State == "translated" && !translator.IsPseudoLocalization(xliffSourceText(key, srcLoc, path), su.Value) {
+				return key, su.Value, true
+			}
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"xckit/config"
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+func TestPseudoCommand_Execute(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+
+	output, status := runCommand(t, &PseudoCommand{}, "-f", filePath, "--lang", "en-XA")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "Summary: 6 string units of 3 keys pseudo-localized into en-XA\n")
+
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	greeting := xc.Strings["greeting"].Localizations["en-XA"].StringUnit
+	test.AssertEqual(t, greeting.State, "translated")
+	test.AssertEqual(t, greeting.Value, "[Ĥéļļö & ŵéļçöṁé ~~~~]")
+	test.AssertEqual(t, xc.Strings["item_count"].Localizations["en-XA"].Variations.Plural["one"].StringUnit.Value, "[%lld îţéṁ ~~]")
+	files := xc.Strings["files"].Localizations["en-XA"]
+	test.AssertEqual(t, files.StringUnit.Value, "[%#@files@]")
+	test.AssertEqual(t, files.Substitutions["files"].FormatSpecifier, "lld")
+	test.AssertEqual(t, files.Substitutions["files"].Variations.Plural["other"].StringUnit.Value, "[%arg ƒîļéš ~~]")
+	if _, ok := xc.Strings["debug"].Localizations["en-XA"]; ok {
+		t.Error("keys with shouldTranslate false should be skipped")
+	}
+
+	// A rerun with other options replaces the pseudo-locale.
+	output, status = runCommand(t, &PseudoCommand{}, "-f", filePath, "--lang", "en-XA", "--expand", "0", "--brackets=false", "--dry-run", "--json")
+	test.AssertEqual(t, status, 0)
+	var out pseudoJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertEqual(t, out.DryRun, true)
+	test.AssertEqual(t, out.Summary.Strings, 6)
+
+	_, status = runCommand(t, &PseudoCommand{}, "-f", filePath, "--lang", "ar-XB", "--expand", "0", "--brackets=false", "--rtl")
+	test.AssertEqual(t, status, 0)
+	xc, err = xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["greeting"].Localizations["en-XA"].StringUnit.Value, "[Ĥéļļö & ŵéļçöṁé ~~~~]")
+	test.AssertEqual(t, xc.Strings["greeting"].Localizations["ar-XB"].StringUnit.Value, "\u202EĤéļļö & ŵéļçöṁé\u202C")
+}
+
+func TestPseudoCommand_NonLatinSource(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", `{"sourceLanguage": "ja", "strings": {
+		"hello": {"localizations": {
+			"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}}
+		}},
+		"files": {"localizations": {
+			"ja": {"variations": {"plural": {
+				"other": {"stringUnit": {"state": "translated", "value": "%lld 個のファイル"}}
+			}}}
+		}},
+		"ようこそ": {}
+	}, "version": "1.0"}`)
+
+	_, status := runCommand(t, &PseudoCommand{}, "-f", filePath, "--lang", "en-XA")
+	test.AssertEqual(t, status, 0)
+	// Rerunning replaces the pseudo-locale without --force, with other options too.
+	_, status = runCommand(t, &PseudoCommand{}, "-f", filePath, "--lang", "en-XA")
+	test.AssertEqual(t, status, 0)
+	_, status = runCommand(t, &PseudoCommand{}, "-f", filePath, "--lang", "en-XA", "--expand", "0", "--brackets=false", "--rtl")
+	test.AssertEqual(t, status, 0)
+	_, status = runCommand(t, &PseudoCommand{}, "-f", filePath, "--lang", "en-XA", "--expand", "0", "--brackets=false")
+	test.AssertEqual(t, status, 0)
+
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["hello"].Localizations["en-XA"].StringUnit.Value, "こんにちは")
+	test.AssertEqual(t, xc.Strings["files"].Localizations["en-XA"].Variations.Plural["other"].StringUnit.Value, "%lld 個のファイル")
+	test.AssertEqual(t, xc.Strings["ようこそ"].Localizations["en-XA"].StringUnit.Value, "ようこそ")
+}
+
+func TestPseudoCommand_Errors(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+
+	_, status := runCommand(t, &PseudoCommand{}, "-f", filePath)
+	test.AssertEqual(t, status, 2)
+	_, status = runCommand(t, &PseudoCommand{}, "-f", filePath, "--lang", "en")
+	test.AssertEqual(t, status, 2)
+
+	// A language with real translations is kept unless --force is given.
+	realPath := test.TempFile(t, "real.xcstrings", `{"sourceLanguage": "en", "strings": {
+		"count": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "%lld"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "%lld"}}
+		}},
+		"hello": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Hello"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}}
+		}}
+	}, "version": "1.0"}`)
+	stderr := captureStderr(func() {
+		_, status = runCommand(t, &PseudoCommand{}, "-f", realPath, "--lang", "ja")
+	})
+	test.AssertEqual(t, status, 2)
+	test.AssertEqual(t, stderr, "Error: --lang ja has real translations (hello: \"こんにちは\"); pass --force to replace them\n")
+	xc, err := xcstrings.Load(realPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xc.Strings["hello"].Localizations["ja"].StringUnit.Value, "こんにちは")
+	_, status = runCommand(t, &PseudoCommand{}, "-f", realPath, "--lang", "ja", "--force")
+	test.AssertEqual(t, status, 0)
+
+	// A configured language holds real translations.
+	cmd := &PseudoCommand{}
+	cmd.config = &config.Config{Languages: []string{"ja"}}
+	_, status = runCommand(t, cmd, "-f", filePath, "--lang", "ja")
+	test.AssertEqual(t, status, 2)
+}
@@ -33,7 +33,11 @@ func (c *RemoveCommand) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&c.dryRun, "dry-run", false, "Print what would be removed without modifying the file")
 }
 
-func (c *RemoveCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+func (c *RemoveCommand) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return c.forEachCatalog(false, func() subcommands.ExitStatus { return c.execute(ctx, f) })
+}
+
+func (c *RemoveCommand) execute(_ context.Context, f *flag.FlagSet) subcommands.ExitStatus {
 	hasKey := f.NArg() >= 1
 	if !hasKey && c.state == "" {
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: either <key> or --state is required\n")
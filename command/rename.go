@@ -0,0 +1,140 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/google/subcommands"
+)
+
+type RenameCommand struct {
+	XCStringsCommand
+	pattern    string
+	replace    string
+	dryRun     bool
+	jsonOutput bool
+}
+
+func (*RenameCommand) Name() string {
+	return "rename"
+}
+
+func (*RenameCommand) Synopsis() string {
+	return "Rename keys, keeping their localizations, comment and extraction state"
+}
+
+func (*RenameCommand) Usage() string {
+	return `rename [-f file.xcstrings] [--dry-run] [--json] <old-key> <new-key>: Rename a key
+rename [-f file.xcstrings] --pattern <regexp> --replace <replacement> [--dry-run] [--json]: Rename every key matching <regexp> to <replacement>, where $1 or ${1} stands for the first group (use ${1} when a letter, digit or _ follows)
+Also available as mv. Nothing is renamed when a new key is already taken or two keys would get the same name.
+`
+}
+
+func (c *RenameCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.StringVar(&c.pattern, "pattern", "", "Rename every key matching this regular expression")
+	f.StringVar(&c.replace, "replace", "", "Replacement for --pattern matches ($1, ${name} expand groups)")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show the renames without writing the file")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output a structured JSON document describing the renames")
+}
+
+// renameJSONRename is one key renamed.
+type renameJSONRename struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// renameJSONOutput is the top-level document printed by `rename --json`.
+type renameJSONOutput struct {
+	DryRun  bool               `json:"dryRun"`
+	Renames []renameJSONRename `json:"renames"`
+	Summary struct {
+		Renamed int `json:"renamed"`
+	} `json:"summary"`
+}
+
+func (c *RenameCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	var re *regexp.Regexp
+	switch {
+	case c.pattern != "" && f.NArg() == 0:
+		var err error
+		re, err = regexp.Compile(c.pattern)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: invalid --pattern: %v\n", err)
+			return subcommands.ExitUsageError
+		}
+	case c.pattern == "" && c.replace == "" && f.NArg() == 2:
+	default:
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: either <old-key> <new-key> or --pattern with --replace is required\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	return c.forEachCatalog(c.jsonOutput, func() subcommands.ExitStatus {
+		return c.execute(re, f.Args())
+	})
+}
+
+func (c *RenameCommand) execute(re *regexp.Regexp, args []string) subcommands.ExitStatus {
+	xcs, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	renames := make(map[string]string)
+	if re == nil {
+		if args[0] != args[1] {
+			renames[args[0]] = args[1]
+		}
+	} else {
+		for key := range xcs.Strings {
+			if !re.MatchString(key) {
+				continue
+			}
+			if to := re.ReplaceAllString(key, c.replace); to != key {
+				renames[key] = to
+			}
+		}
+	}
+
+	if err := xcs.RenameKeys(renames); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	out := renameJSONOutput{DryRun: c.dryRun, Renames: []renameJSONRename{}}
+	for from, to := range renames {
+		out.Renames = append(out.Renames, renameJSONRename{From: from, To: to})
+	}
+	sort.Slice(out.Renames, func(i, j int) bool { return out.Renames[i].From < out.Renames[j].From })
+	out.Summary.Renamed = len(out.Renames)
+
+	if !c.dryRun && len(renames) > 0 {
+		filePath := c.filePath
+		if filePath == "" {
+			filePath = c.findXCStringsFile()
+		}
+		if err := xcs.SaveToFile(filePath); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	if c.jsonOutput {
+		return c.printJSON(out)
+	}
+
+	prefix := ""
+	if c.dryRun {
+		prefix = "[dry-run] "
+	}
+	for _, r := range out.Renames {
+		fmt.Printf("%s%s -> %s\n", prefix, r.From, r.To)
+	}
+	fmt.Printf("%sRenamed %d key(s)\n", prefix, out.Summary.Renamed)
+	return subcommands.ExitSuccess
+}
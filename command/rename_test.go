@@ -0,0 +1,111 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+const renameFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"settings.title": {"comment": "Settings screen title", "extractionState": "manual", "localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Settings"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "設定"}}
+		}},
+		"settings.done": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Done"}}
+		}},
+		"prefs.done": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Finished"}}
+		}}
+	},
+	"version": "1.0"
+}`
+
+func TestRenameCommand_SingleKey(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", renameFixture)
+
+	output, status := runCommand(t, &RenameCommand{}, "-f", filePath, "settings.title", "preferences.title")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "settings.title -> preferences.title\nRenamed 1 key(s)\n")
+
+	xcs, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	if _, ok := xcs.Strings["settings.title"]; ok {
+		t.Error("old key should be gone")
+	}
+	def := xcs.Strings["preferences.title"]
+	test.AssertEqual(t, def.Comment, "Settings screen title")
+	test.AssertEqual(t, def.ExtractionState, "manual")
+	test.AssertEqual(t, def.Localizations["ja"].StringUnit.Value, "設定")
+}
+
+func TestRenameCommand_Pattern(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", strings.Replace(renameFixture, `"prefs.done"`, `"other.done"`, 1))
+
+	output, status := runCommand(t, &RenameCommand{}, "-f", filePath, "--pattern", `^settings\.(.*)`, "--replace", "prefs.${1}")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "settings.done -> prefs.done\nsettings.title -> prefs.title\nRenamed 2 key(s)\n")
+
+	xcs, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xcs.Strings["prefs.done"].Localizations["en"].StringUnit.Value, "Done")
+	test.AssertEqual(t, xcs.Strings["prefs.title"].Comment, "Settings screen title")
+	test.AssertEqual(t, len(xcs.Strings), 3)
+}
+
+func TestRenameCommand_CollisionLeavesFileUntouched(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", renameFixture)
+
+	// settings.done would overwrite the existing prefs.done.
+	_, status := runCommand(t, &RenameCommand{}, "-f", filePath, "--pattern", `^settings\.`, "--replace", "prefs.")
+	test.AssertEqual(t, status, 1)
+	_, status = runCommand(t, &RenameCommand{}, "-f", filePath, "settings.done", "prefs.done")
+	test.AssertEqual(t, status, 1)
+	_, status = runCommand(t, &RenameCommand{}, "-f", filePath, "missing", "other")
+	test.AssertEqual(t, status, 1)
+
+	data, err := os.ReadFile(filePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(data), renameFixture)
+}
+
+func TestRenameCommand_DryRunJSON(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", renameFixture)
+
+	output, status := runCommand(t, &RenameCommand{}, "-f", filePath, "--dry-run", "settings.title", "title")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "[dry-run] settings.title -> title\n[dry-run] Renamed 1 key(s)\n")
+
+	output, status = runCommand(t, &RenameCommand{}, "-f", filePath, "--dry-run", "--json", "--pattern", `done$`, "--replace", "close")
+	test.AssertEqual(t, status, 0)
+	var out renameJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertEqual(t, out.DryRun, true)
+	test.AssertEqual(t, out.Summary.Renamed, 2)
+	test.AssertEqual(t, out.Renames[0], renameJSONRename{From: "prefs.done", To: "prefs.close"})
+	test.AssertEqual(t, out.Renames[1], renameJSONRename{From: "settings.done", To: "settings.close"})
+
+	data, err := os.ReadFile(filePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(data), renameFixture)
+}
+
+func TestRenameCommand_UsageErrors(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", renameFixture)
+
+	for _, args := range [][]string{
+		{"settings.title"},
+		{"--pattern", "^settings", "settings.title", "title"},
+		{"--pattern", "(", "--replace", "x"},
+		{"--replace", "x", "settings.title", "title"},
+	} {
+		_, status := runCommand(t, &RenameCommand{}, append([]string{"-f", filePath}, args...)...)
+		test.AssertEqual(t, status, 2)
+	}
+}
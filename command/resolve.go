@@ -0,0 +1,137 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"xckit/resolve"
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+type ResolveCommand struct {
+	XCStringsCommand
+	language string
+	device   string
+	table    bool
+}
+
+func (*ResolveCommand) Name() string {
+	return "resolve"
+}
+
+func (*ResolveCommand) Synopsis() string {
+	return "Show the string a key resolves to at runtime for given argument values"
+}
+
+func (*ResolveCommand) Usage() string {
+	return "resolve [-f file.xcstrings] [--lang <language>] [--device <device>] [--table] <key> [<arg> ...]: Render a key as Foundation would for the argument values: select the device variation and the CLDR plural category, expand %#@name@ substitutions and %arg, and apply printf-style formatting; --table shows the result for counts covering every plural category\n"
+}
+
+func (c *ResolveCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.StringVar(&c.language, "lang", "", "Language to resolve in (default: the source language)")
+	f.StringVar(&c.device, "device", "", "Device variation to use: iphone, ipad, ipod, mac, applewatch, appletv or applevision (default: other)")
+	f.BoolVar(&c.table, "table", false, "Show the result for representative counts of every plural category; the arguments then omit the counts")
+}
+
+func (c *ResolveCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if f.NArg() < 1 {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: a key is required\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+	key, args := f.Arg(0), f.Args()[1:]
+
+	if err := c.singleCatalog(); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	xcs, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	language := c.language
+	if language == "" {
+		language = xcs.SourceLanguage
+	}
+	opts := resolve.Options{Language: language, Device: c.device, Args: args}
+
+	if !c.table {
+		result, err := resolve.Resolve(xcs, key, opts)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		c.warnFallback(key, language, result.Language)
+		fmt.Println(result.Text)
+		return subcommands.ExitSuccess
+	}
+	return c.executeTable(xcs, key, opts)
+}
+
+// executeTable resolves key once per sample count, passed to every argument
+// that selects a plural variation; the given values fill the others.
+func (c *ResolveCommand) executeTable(xcs *xcstrings.XCStrings, key string, opts resolve.Options) subcommands.ExitStatus {
+	positions, language, err := resolve.PluralArguments(xcs, key, opts.Language)
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	if len(positions) == 0 {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s has no plural variations\n", key)
+		return subcommands.ExitFailure
+	}
+
+	given := opts.Args
+	type row struct {
+		count, category, text string
+	}
+	var rows []row
+	for _, count := range resolve.SampleCounts(language) {
+		n := strconv.FormatInt(count, 10)
+		var args []string
+		next := 0
+		for position := 1; next < len(given) || position <= positions[len(positions)-1]; position++ {
+			switch {
+			case slices.Contains(positions, position):
+				args = append(args, n)
+			case next < len(given):
+				args = append(args, given[next])
+				next++
+			default:
+				_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s needs a value for argument %d\n", key, position)
+				return subcommands.ExitUsageError
+			}
+		}
+		opts.Args = args
+		result, err := resolve.Resolve(xcs, key, opts)
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		rows = append(rows, row{n, xcstrings.PluralCategoryOf(result.Language, count), result.Text})
+	}
+	c.warnFallback(key, opts.Language, language)
+
+	countWidth := 0
+	for _, r := range rows {
+		countWidth = max(countWidth, len(r.count))
+	}
+	fmt.Printf("%s (%s):\n", key, language)
+	for _, r := range rows {
+		fmt.Printf("  %*s  %-5s  %s\n", countWidth, r.count, r.category, r.text)
+	}
+	return subcommands.ExitSuccess
+}
+
+func (c *ResolveCommand) warnFallback(key, requested, used string) {
+	if requested != used {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Warning: %s has no %s translation; the app shows the %s text\n", key, requested, used)
+	}
+}
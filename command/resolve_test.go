@@ -0,0 +1,87 @@
+package command
+
+import (
+	"testing"
+
+	"xckit/helper/test"
+)
+
+const resolveCommandFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"%@ sent %lld photos": {"localizations": {
+			"en": {
+				"stringUnit": {"state": "translated", "value": "%1$@ sent %2$#@photos@"},
+				"substitutions": {"photos": {"argNum": 2, "formatSpecifier": "lld", "variations": {"plural": {
+					"one": {"stringUnit": {"state": "translated", "value": "a photo"}},
+					"other": {"stringUnit": {"state": "translated", "value": "%arg photos"}}
+				}}}}
+			},
+			"ru": {
+				"stringUnit": {"state": "translated", "value": "%1$@: %2$#@photos@"},
+				"substitutions": {"photos": {"argNum": 2, "formatSpecifier": "lld", "variations": {"plural": {
+					"one": {"stringUnit": {"state": "translated", "value": "%arg фото"}},
+					"few": {"stringUnit": {"state": "translated", "value": "%arg фотографии"}},
+					"many": {"stringUnit": {"state": "translated", "value": "%arg фотографий"}},
+					"other": {"stringUnit": {"state": "translated", "value": "%arg фотографии"}}
+				}}}}
+			}
+		}},
+		"Settings": {}
+	},
+	"version": "1.0"
+}`
+
+func runResolve(t *testing.T, args ...string) (string, string, int) {
+	t.Helper()
+	var output string
+	var status int
+	stderr := captureStderr(func() {
+		output, status = runCommand(t, &ResolveCommand{}, args...)
+	})
+	return output, stderr, status
+}
+
+func TestResolveCommand(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", resolveCommandFixture)
+
+	output, _, status := runResolve(t, "-f", filePath, "--lang", "ru", "%@ sent %lld photos", "Аня", "22")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "Аня: 22 фотографии\n")
+
+	output, stderr, status := runResolve(t, "-f", filePath, "--lang", "ja", "%@ sent %lld photos", "Ann", "1")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "Ann sent a photo\n")
+	test.AssertEqual(t, stderr, "Warning: %@ sent %lld photos has no ja translation; the app shows the en text\n")
+
+	output, _, status = runResolve(t, "-f", filePath, "Settings")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "Settings\n")
+
+	_, _, status = runResolve(t, "-f", filePath, "%@ sent %lld photos", "Ann", "many")
+	test.AssertEqual(t, status, 1)
+	_, _, status = runResolve(t, "-f", filePath)
+	test.AssertEqual(t, status, 2)
+}
+
+func TestResolveCommand_Table(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", resolveCommandFixture)
+
+	output, _, status := runResolve(t, "-f", filePath, "--lang", "ru", "--table", "%@ sent %lld photos", "Аня")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "%@ sent %lld photos (ru):\n"+
+		"        0  many   Аня: 0 фотографий\n"+
+		"        1  one    Аня: 1 фото\n"+
+		"        2  few    Аня: 2 фотографии\n"+
+		"        3  few    Аня: 3 фотографии\n"+
+		"        5  many   Аня: 5 фотографий\n"+
+		"       21  one    Аня: 21 фото\n"+
+		"  1000000  many   Аня: 1000000 фотографий\n")
+
+	_, stderr, status := runResolve(t, "-f", filePath, "--table", "%@ sent %lld photos")
+	test.AssertEqual(t, status, 2)
+	test.AssertEqual(t, stderr, "Error: %@ sent %lld photos needs a value for argument 1\n")
+
+	_, _, status = runResolve(t, "-f", filePath, "--table", "Settings")
+	test.AssertEqual(t, status, 1)
+}
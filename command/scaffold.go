@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/subcommands"
+)
+
+type ScaffoldCommand struct {
+	XCStringsCommand
+	languages  stringsFlag
+	prefix     string
+	dryRun     bool
+	jsonOutput bool
+}
+
+func (*ScaffoldCommand) Name() string {
+	return "scaffold"
+}
+
+func (*ScaffoldCommand) Synopsis() string {
+	return "Create a language's plural, device and substitution skeleton from the source"
+}
+
+func (*ScaffoldCommand) Usage() string {
+	return "scaffold [-f file.xcstrings] --lang <language> [--lang ...] [--prefix <prefix>] [--dry-run] [--json] [key ...]: Give every key (or the listed keys) the source language's structure in <language>, with every CLDR plural category the language needs and every substitution, as \"new\" string units holding the source text\n"
+}
+
+func (c *ScaffoldCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.Var(&c.languages, "lang", "Target language code (repeatable; default: the configured languages)")
+	f.StringVar(&c.prefix, "prefix", "", "Only scaffold keys with this prefix")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show the string units that would be added without writing the file")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output a structured JSON document describing the added string units")
+}
+
+// scaffoldJSONResult lists the string units added to one key and language.
+type scaffoldJSONResult struct {
+	Key      string   `json:"key"`
+	Language string   `json:"language"`
+	Paths    []string `json:"paths"`
+}
+
+// scaffoldJSONOutput is the top-level document printed by `scaffold --json`.
+type scaffoldJSONOutput struct {
+	DryRun  bool                 `json:"dryRun"`
+	Results []scaffoldJSONResult `json:"results"`
+	Summary struct {
+		Keys    int `json:"keys"`
+		Strings int `json:"strings"`
+	} `json:"summary"`
+}
+
+func (c *ScaffoldCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if len(c.languages) == 0 && (c.config == nil || len(c.config.Languages) == 0) {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --lang is required\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+
+	keys := f.Args()
+	return c.forEachCatalog(c.jsonOutput, func() subcommands.ExitStatus { return c.execute(keys) })
+}
+
+func (c *ScaffoldCommand) execute(keys []string) subcommands.ExitStatus {
+	xcs, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	languages := []string(c.languages)
+	if len(languages) == 0 {
+		languages = c.targetLanguages(xcs)
+	}
+	for _, lang := range languages {
+		if lang == xcs.SourceLanguage {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --lang %s is the source language\n", lang)
+			return subcommands.ExitUsageError
+		}
+	}
+
+	if len(keys) == 0 {
+		for key, def := range xcs.Strings {
+			if def.ExtractionState == "stale" || (def.ShouldTranslate != nil && !*def.ShouldTranslate) {
+				continue
+			}
+			if strings.HasPrefix(key, c.prefix) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+	}
+
+	out := scaffoldJSONOutput{DryRun: c.dryRun, Results: []scaffoldJSONResult{}}
+	scaffolded := make(map[string]bool)
+	for _, lang := range languages {
+		for _, key := range keys {
+			paths, err := xcs.ScaffoldLocalization(key, lang)
+			if err != nil {
+				_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+				return subcommands.ExitFailure
+			}
+			if len(paths) == 0 {
+				continue
+			}
+			out.Results = append(out.Results, scaffoldJSONResult{Key: key, Language: lang, Paths: paths})
+			scaffolded[key] = true
+			out.Summary.Strings += len(paths)
+		}
+	}
+
+	out.Summary.Keys = len(scaffolded)
+
+	if !c.dryRun && len(out.Results) > 0 {
+		filePath := c.filePath
+		if filePath == "" {
+			filePath = c.findXCStringsFile()
+		}
+		if err := xcs.SaveToFile(filePath); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	if c.jsonOutput {
+		return c.printJSON(out)
+	}
+
+	prefix := ""
+	if c.dryRun {
+		prefix = "[dry-run] "
+	}
+	for _, r := range out.Results {
+		labels := make([]string, len(r.Paths))
+		for i, path := range r.Paths {
+			labels[i] = path
+			if path == "" {
+				labels[i] = "(string)"
+			}
+		}
+		fmt.Printf("%s%s (%s): %s\n", prefix, r.Key, r.Language, strings.Join(labels, ", "))
+	}
+	fmt.Printf("%sSummary: %d string units added to %d keys\n", prefix, out.Summary.Strings, out.Summary.Keys)
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+func TestScaffoldCommand_Metadata(t *testing.T) {
+	cmd := &ScaffoldCommand{}
+	test.AssertEqual(t, cmd.Name(), "scaffold")
+	if !strings.Contains(cmd.Usage(), "scaffold") {
+		t.Errorf("usage should contain 'scaffold', got: %q", cmd.Usage())
+	}
+}
+
+func TestScaffoldCommand_Execute(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+
+	output, status := runCommand(t, &ScaffoldCommand{}, "-f", filePath, "--lang", "ru")
+	test.AssertEqual(t, status, 0)
+	for _, want := range []string{
+		"greeting (ru): (string)\n",
+		"item_count (ru): plural.few, plural.many, plural.one, plural.other\n",
+		"Summary: 10 string units added to 3 keys",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "debug") {
+		t.Errorf("keys with shouldTranslate false should be skipped, got:\n%s", output)
+	}
+
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	few := xc.Strings["item_count"].Localizations["ru"].Variations.Plural["few"].StringUnit
+	test.AssertEqual(t, few.State, "new")
+	test.AssertEqual(t, few.Value, "%lld items")
+	files := xc.Strings["files"].Localizations["ru"].Substitutions["files"]
+	test.AssertEqual(t, files.ArgNum, 1)
+	test.AssertEqual(t, files.FormatSpecifier, "lld")
+	test.AssertEqual(t, len(files.Variations.Plural), 4)
+
+	// The scaffolded units are untranslated and lint clean.
+	test.AssertEqual(t, len(xc.UntranslatedDetailsForLanguage("ru")), 10)
+	output, status = runLintCommand(t, filePath)
+	test.AssertEqual(t, status, 0)
+	if strings.Contains(output, "ru") {
+		t.Errorf("scaffolded localizations should be lint clean, got:\n%s", output)
+	}
+}
+
+func TestScaffoldCommand_KeysDryRunJSON(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+
+	output, status := runCommand(t, &ScaffoldCommand{}, "-f", filePath, "--lang", "ja", "--lang", "fr", "--dry-run", "--json", "item_count")
+	test.AssertEqual(t, status, 0)
+
+	var out scaffoldJSONOutput
+	if err := json.Unmarshal([]byte(output), &out); err != nil {
+		t.Fatalf("output should be valid JSON, got error %v, output: %q", err, output)
+	}
+	test.AssertEqual(t, out.DryRun, true)
+	// Japanese already has its only category; French needs one, many and other.
+	test.AssertEqual(t, len(out.Results), 1)
+	test.AssertEqual(t, out.Results[0].Language, "fr")
+	test.AssertSliceEqual(t, out.Results[0].Paths, []string{"plural.many", "plural.one", "plural.other"})
+	test.AssertEqual(t, out.Summary.Keys, 1)
+	test.AssertEqual(t, out.Summary.Strings, 3)
+
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	if _, ok := xc.Strings["item_count"].Localizations["fr"]; ok {
+		t.Error("--dry-run should not write the file")
+	}
+}
+
+func TestScaffoldCommand_Errors(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+
+	_, status := runCommand(t, &ScaffoldCommand{}, "-f", filePath)
+	test.AssertEqual(t, status, 2)
+	_, status = runCommand(t, &ScaffoldCommand{}, "-f", filePath, "--lang", "en")
+	test.AssertEqual(t, status, 2)
+	_, status = runCommand(t, &ScaffoldCommand{}, "-f", filePath, "--lang", "ru", "no_such_key")
+	test.AssertEqual(t, status, 1)
+}
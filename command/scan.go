@@ -0,0 +1,248 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"xckit/scan"
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+type ScanCommand struct {
+	XCStringsCommand
+	sources    stringsFlag
+	addMissing bool
+	markStale  bool
+	dryRun     bool
+	jsonOutput bool
+	failIfAny  bool
+}
+
+func (*ScanCommand) Name() string {
+	return "scan"
+}
+
+func (*ScanCommand) Synopsis() string {
+	return "Compare the catalog with the keys Swift and Objective-C sources use"
+}
+
+func (*ScanCommand) Usage() string {
+	return "scan [-f file.xcstrings] [--src <dir> ...] [--add-missing] [--mark-stale] [--dry-run] [--json] [--fail-if-any]: Find String(localized:), Text(\"...\"), LocalizedStringResource, NSLocalizedString and similar usages in .swift, .m and .mm files, and report keys the code uses that the catalog lacks and catalog keys the code no longer uses\n"
+}
+
+func (c *ScanCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.Var(&c.sources, "src", "Directory or file to scan (repeatable; default: the current directory)")
+	f.BoolVar(&c.addMissing, "add-missing", false, "Add keys the code uses to the catalog: with a \"new\" source-language string unit for a defaultValue:, otherwise as \"key\" : { }")
+	f.BoolVar(&c.markStale, "mark-stale", false, "Mark keys the code no longer uses stale, and clear stale on keys it uses again")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show the changes without writing the file")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output a single JSON document to stdout instead of human-readable text")
+	f.BoolVar(&c.failIfAny, "fail-if-any", false, "Exit with status 1 if any key is missing from the catalog or unused")
+}
+
+// scanJSONMissing is a key the code uses that the catalog lacks.
+type scanJSONMissing struct {
+	Key          string `json:"key"`
+	File         string `json:"file"`
+	Line         int    `json:"line"`
+	Interpolated bool   `json:"interpolated,omitempty"`
+	Added        bool   `json:"added"`
+}
+
+// scanJSONOutput is the top-level document printed by `scan --json`.
+type scanJSONOutput struct {
+	DryRun bool   `json:"dryRun"`
+	Table  string `json:"table"`
+	// Skipped is set for InfoPlist.xcstrings, which isn't scanned.
+	Skipped bool              `json:"skipped,omitempty"`
+	Usages  int               `json:"usages"`
+	Missing []scanJSONMissing `json:"missing"`
+	Unused  []string          `json:"unused"`
+	// Revived are stale keys the code uses again.
+	Revived []string `json:"revived"`
+	Summary struct {
+		Missing int `json:"missing"`
+		Unused  int `json:"unused"`
+		Revived int `json:"revived"`
+	} `json:"summary"`
+}
+
+func (c *ScanCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	roots := []string(c.sources)
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+	usages, err := scan.Sources(roots)
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	return c.forEachCatalog(c.jsonOutput, func() subcommands.ExitStatus { return c.execute(usages) })
+}
+
+func (c *ScanCommand) execute(usages []scan.Usage) subcommands.ExitStatus {
+	xcs, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	filePath := c.filePath
+	if filePath == "" {
+		filePath = c.findXCStringsFile()
+	}
+
+	table := catalogTableName(filePath)
+	out := scanJSONOutput{DryRun: c.dryRun, Table: table, Missing: []scanJSONMissing{}, Unused: []string{}, Revived: []string{}}
+	if table == "InfoPlist" {
+		if c.jsonOutput {
+			out.Skipped = true
+			return c.printJSON(out)
+		}
+		fmt.Println("Skipped: InfoPlist.xcstrings holds Info.plist keys, which code doesn't use")
+		return subcommands.ExitSuccess
+	}
+
+	used := make(map[string]bool)
+	reported := make(map[string]bool)
+	for _, u := range usages {
+		if u.TableName() != table {
+			continue
+		}
+		out.Usages++
+		found := false
+		if _, ok := xcs.Strings[u.Key]; ok && !u.Interpolated {
+			used[u.Key] = true
+			found = true
+		} else if u.Interpolated {
+			for key := range xcs.Strings {
+				if u.Matches(key) {
+					used[key] = true
+					found = true
+				}
+			}
+		}
+		if found || reported[u.Key] {
+			continue
+		}
+		reported[u.Key] = true
+
+		missing := scanJSONMissing{Key: u.Key, File: u.File, Line: u.Line, Interpolated: u.Interpolated}
+		// An interpolation's format specifier depends on its type, which
+		// only the compiler knows, so those keys are left to Xcode.
+		if c.addMissing && !u.Interpolated {
+			addScannedKey(xcs, u)
+			used[u.Key] = true
+			missing.Added = true
+		}
+		out.Missing = append(out.Missing, missing)
+	}
+
+	for _, key := range sortedKeys(xcs.Strings) {
+		definition := xcs.Strings[key]
+		switch {
+		case definition.ExtractionState == "manual":
+			// Manual keys are looked up at runtime, not extracted.
+		case definition.ExtractionState == "stale" && used[key]:
+			out.Revived = append(out.Revived, key)
+			if c.markStale {
+				definition.ExtractionState = ""
+				xcs.Strings[key] = definition
+			}
+		case definition.ExtractionState != "stale" && !used[key]:
+			out.Unused = append(out.Unused, key)
+			if c.markStale {
+				definition.ExtractionState = "stale"
+				xcs.Strings[key] = definition
+			}
+		}
+	}
+	out.Summary.Missing = len(out.Missing)
+	out.Summary.Unused = len(out.Unused)
+	out.Summary.Revived = len(out.Revived)
+
+	changed := (c.markStale && len(out.Unused)+len(out.Revived) > 0) || out.added() > 0
+	if !c.dryRun && changed {
+		if err := xcs.SaveToFile(filePath); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	status := subcommands.ExitSuccess
+	if c.failIfAny && len(out.Missing)+len(out.Unused) > 0 {
+		status = subcommands.ExitFailure
+	}
+
+	if c.jsonOutput {
+		return max(status, c.printJSON(out))
+	}
+
+	prefix := ""
+	if c.dryRun {
+		prefix = "[dry-run] "
+	}
+	if len(out.Missing) > 0 {
+		fmt.Printf("Missing from the catalog (%d):\n", len(out.Missing))
+		for _, m := range out.Missing {
+			note := ""
+			switch {
+			case m.Added:
+				note = " (added)"
+			case m.Interpolated:
+				note = " (interpolated; build in Xcode to add it)"
+			}
+			fmt.Printf("  %s  %s:%d%s\n", m.Key, m.File, m.Line, note)
+		}
+	}
+	if len(out.Unused) > 0 {
+		fmt.Printf("Not used in code (%d):\n", len(out.Unused))
+		for _, key := range out.Unused {
+			note := ""
+			if c.markStale {
+				note = " (marked stale)"
+			}
+			fmt.Printf("  %s%s\n", key, note)
+		}
+	}
+	if len(out.Revived) > 0 {
+		fmt.Printf("Stale but used in code (%d):\n", len(out.Revived))
+		for _, key := range out.Revived {
+			note := ""
+			if c.markStale {
+				note = " (no longer stale)"
+			}
+			fmt.Printf("  %s%s\n", key, note)
+		}
+	}
+	fmt.Printf("%sSummary: %d usages, %d missing, %d unused, %d stale but used\n", prefix, out.Usages, out.Summary.Missing, out.Summary.Unused, out.Summary.Revived)
+	return status
+}
+
+func (o *scanJSONOutput) added() int {
+	n := 0
+	for _, m := range o.Missing {
+		if m.Added {
+			n++
+		}
+	}
+	return n
+}
+
+// addScannedKey adds u's key as Xcode's extraction would: with its comment,
+// and only for a default value, a "new" source-language string holding it.
+// A key without one is written as "key" : {}, which Xcode reads as its own
+// source text.
+func addScannedKey(xcs *xcstrings.XCStrings, u scan.Usage) {
+	definition := xcstrings.StringDefinition{Comment: u.Comment}
+	if u.DefaultValue != "" {
+		definition.ExtractionState = "extracted_with_value"
+		definition.Localizations = map[string]xcstrings.Localization{
+			xcs.SourceLanguage: {StringUnit: &xcstrings.StringUnit{State: "new", Value: u.DefaultValue}},
+		}
+	}
+	xcs.Strings[u.Key] = definition
+}
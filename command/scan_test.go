@@ -0,0 +1,158 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+const scanCatalogFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"%lld items": {"localizations": {
+			"en": {"variations": {"plural": {
+				"one": {"stringUnit": {"state": "translated", "value": "%lld item"}},
+				"other": {"stringUnit": {"state": "translated", "value": "%lld items"}}
+			}}}
+		}},
+		"settings.title": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Settings"}}
+		}},
+		"old.banner": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Old"}}
+		}},
+		"back.again": {"extractionState": "stale", "localizations": {}},
+		"dynamic.key": {"extractionState": "manual", "localizations": {}}
+	},
+	"version": "1.0"
+}`
+
+const scanSwiftFixture = `import SwiftUI
+
+struct ContentView: View {
+    var body: some View {
+        Text("settings.title")
+        Text("\(count) items")
+        Text("back.again")
+        Text("Hello \(name)")
+        Button("welcome.button") {}
+        Text(String(localized: "saved", defaultValue: "Saved!", comment: "After saving"))
+        Text("other.table", tableName: "Settings")
+    }
+}
+`
+
+func writeScanProject(t *testing.T) (string, string) {
+	t.Helper()
+	dir := writeProject(t, map[string]string{
+		"Localizable.xcstrings":     scanCatalogFixture,
+		"Sources/ContentView.swift": scanSwiftFixture,
+	})
+	return filepath.Join(dir, "Localizable.xcstrings"), filepath.Join(dir, "Sources")
+}
+
+func TestScanCommand_Report(t *testing.T) {
+	catalogPath, srcDir := writeScanProject(t)
+
+	output, status := runCommand(t, &ScanCommand{}, "-f", catalogPath, "--src", srcDir, "--fail-if-any")
+	test.AssertEqual(t, status, 1)
+	swiftPath := filepath.Join(srcDir, "ContentView.swift")
+	test.AssertEqual(t, output, "Missing from the catalog (3):\n"+
+		"  Hello %@  "+swiftPath+":8 (interpolated; build in Xcode to add it)\n"+
+		"  welcome.button  "+swiftPath+":9\n"+
+		"  saved  "+swiftPath+":10\n"+
+		"Not used in code (1):\n"+
+		"  old.banner\n"+
+		"Stale but used in code (1):\n"+
+		"  back.again\n"+
+		"Summary: 6 usages, 3 missing, 1 unused, 1 stale but used\n")
+
+	data, err := os.ReadFile(catalogPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(data), scanCatalogFixture)
+}
+
+func TestScanCommand_Fix(t *testing.T) {
+	catalogPath, srcDir := writeScanProject(t)
+
+	output, status := runCommand(t, &ScanCommand{}, "-f", catalogPath, "--src", srcDir, "--add-missing", "--mark-stale", "--json")
+	test.AssertEqual(t, status, 0)
+	var out scanJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertEqual(t, out.Table, "Localizable")
+	test.AssertEqual(t, out.Summary.Missing, 3)
+	test.AssertEqual(t, out.Missing[0].Added, false)
+	test.AssertEqual(t, out.Missing[1].Added, true)
+
+	xcs, err := xcstrings.Load(catalogPath)
+	test.AssertNoError(t, err)
+	saved := xcs.Strings["saved"]
+	test.AssertEqual(t, saved.ExtractionState, "extracted_with_value")
+	test.AssertEqual(t, saved.Comment, "After saving")
+	test.AssertEqual(t, saved.Localizations["en"].StringUnit.State, "new")
+	test.AssertEqual(t, saved.Localizations["en"].StringUnit.Value, "Saved!")
+	welcome := xcs.Strings["welcome.button"]
+	test.AssertEqual(t, welcome.ExtractionState, "")
+	test.AssertEqual(t, len(welcome.Localizations), 0)
+	data, err := os.ReadFile(catalogPath)
+	test.AssertNoError(t, err)
+	if !strings.Contains(string(data), `"welcome.button" : {
+
+    }`) {
+		t.Errorf("expected welcome.button written as an empty object, got:\n%s", data)
+	}
+	test.AssertEqual(t, xcs.Strings["old.banner"].ExtractionState, "stale")
+	test.AssertEqual(t, xcs.Strings["back.again"].ExtractionState, "")
+	test.AssertEqual(t, xcs.Strings["dynamic.key"].ExtractionState, "manual")
+	if _, ok := xcs.Strings["other.table"]; ok {
+		t.Error("a key of another table should not be added")
+	}
+
+	// Only the interpolated key, whose specifier Xcode decides, is left.
+	output, status = runCommand(t, &ScanCommand{}, "-f", catalogPath, "--src", srcDir, "--fail-if-any")
+	test.AssertEqual(t, status, 1)
+	test.AssertEqual(t, output, "Missing from the catalog (1):\n"+
+		"  Hello %@  "+filepath.Join(srcDir, "ContentView.swift")+":8 (interpolated; build in Xcode to add it)\n"+
+		"Summary: 6 usages, 1 missing, 0 unused, 0 stale but used\n")
+}
+
+func TestScanCommand_DryRunAndTables(t *testing.T) {
+	catalogPath, srcDir := writeScanProject(t)
+
+	_, status := runCommand(t, &ScanCommand{}, "-f", catalogPath, "--src", srcDir, "--add-missing", "--mark-stale", "--dry-run")
+	test.AssertEqual(t, status, 0)
+	data, err := os.ReadFile(catalogPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(data), scanCatalogFixture)
+
+	// Settings.xcstrings only sees the usage naming its table.
+	settingsPath := test.TempFile(t, "Settings.xcstrings", `{"sourceLanguage": "en", "strings": {"other.table": {}}, "version": "1.0"}`)
+	output, status := runCommand(t, &ScanCommand{}, "-f", settingsPath, "--src", srcDir, "--fail-if-any")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "Summary: 1 usages, 0 missing, 0 unused, 0 stale but used\n")
+
+	_, status = runCommand(t, &ScanCommand{}, "-f", catalogPath, "--src", filepath.Join(srcDir, "missing"))
+	test.AssertEqual(t, status, 1)
+}
+
+func TestScanCommand_SkipsInfoPlist(t *testing.T) {
+	_, srcDir := writeScanProject(t)
+	catalogPath := filepath.Join(filepath.Dir(srcDir), "InfoPlist.xcstrings")
+	test.AssertNoError(t, os.WriteFile(catalogPath, []byte(infoPlistFixture), 0644))
+
+	output, status := runCommand(t, &ScanCommand{}, "-f", catalogPath, "--src", srcDir)
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "Skipped: InfoPlist.xcstrings holds Info.plist keys, which code doesn't use\n")
+
+	output, status = runCommand(t, &ScanCommand{}, "-f", catalogPath, "--src", srcDir, "--json")
+	test.AssertEqual(t, status, 0)
+	var out scanJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertEqual(t, out.Skipped, true)
+	test.AssertEqual(t, out.Table, "InfoPlist")
+}
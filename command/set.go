@@ -97,6 +97,11 @@ type setJSONOutput struct {
 }
 
 func (c *SetCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if err := c.singleCatalog(); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
 	commentSet := false
 	f.Visit(func(fl *flag.Flag) {
 		if fl.Name == "comment" {
@@ -173,6 +178,7 @@ func (c *SetCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{
 	if migrated && !c.force {
 		fmt.Fprintf(os.Stderr, "Warning: existing plain stringUnit for key '%s' in language '%s' was migrated to variations; the original value was preserved under the 'other' fallback\n", key, c.language)
 	}
+	warnUnusedPluralCategory(c.language, c.plural)
 
 	filePath := c.filePath
 	if filePath == "" {
@@ -255,6 +261,7 @@ func (c *SetCommand) executeStdin(f *flag.FlagSet) subcommands.ExitStatus {
 	// referencing the same language, without requiring every single line to
 	// pass --allow-new-language independently.
 	knownLangs := append(append([]string{}, xcs.Languages()...), xcs.SourceLanguage)
+	knownLangs = append(knownLangs, c.configuredLanguages()...)
 
 	// Field-level and cross-line validation runs even when some lines failed
 	// to parse as JSON, so that every problem in the batch is reported
@@ -327,6 +334,7 @@ func (c *SetCommand) executeStdin(f *flag.FlagSet) subcommands.ExitStatus {
 		if migrated && !c.force {
 			fmt.Fprintf(os.Stderr, "Warning: existing plain stringUnit for key '%s' in language '%s' was migrated to variations; the original value was preserved under the 'other' fallback\n", row.Key, row.Lang)
 		}
+		warnUnusedPluralCategory(row.Lang, row.Plural)
 		results = append(results, result)
 	}
 
@@ -552,11 +560,12 @@ func summarizeSetResults(results []setResult) setJSONSummary {
 }
 
 // validateLanguage ensures c.language refers to a language already present in
-// the catalog (or the catalog's source language), unless the catalog has no
+// the catalog, listed in the project configuration, or the catalog's source
+// language, unless the catalog has no
 // languages yet (nothing to compare against, so the first language addition
 // is never blocked) or --allow-new-language was explicitly passed.
 func (c *SetCommand) validateLanguage(xcs *xcstrings.XCStrings) error {
-	existing := xcs.Languages()
+	existing := append(xcs.Languages(), c.configuredLanguages()...)
 	if len(existing) == 0 {
 		// Nothing to validate against yet; do not block initial catalog setup.
 		return nil
@@ -582,6 +591,26 @@ func (c *SetCommand) validateLanguage(xcs *xcstrings.XCStrings) error {
 	return fmt.Errorf("unknown language '%s' is not present in the catalog. Use --allow-new-language to add a new language", c.language)
 }
 
+// warnUnusedPluralCategory warns when plural is a category the language's
+// CLDR plural rules never select (e.g. "one" in Japanese), so the value
+// would never be shown.
+func warnUnusedPluralCategory(lang, plural string) {
+	if plural == "" || xcstrings.PluralCategoryUsed(lang, plural) {
+		return
+	}
+	categories, _ := xcstrings.RequiredPluralCategories(lang)
+	fmt.Fprintf(os.Stderr, "Warning: plural category '%s' is never used by language '%s' (categories: %s)\n", plural, lang, strings.Join(categories, ", "))
+}
+
+// configuredLanguages returns the languages listed in the project
+// configuration, which are known even before the catalog has any of them.
+func (c *SetCommand) configuredLanguages() []string {
+	if c.config == nil {
+		return nil
+	}
+	return c.config.Languages
+}
+
 // caseInsensitiveLanguageMatch returns the candidate that matches input
 // case-insensitively (e.g. "JA" matching existing "ja"), or "" if none.
 func caseInsensitiveLanguageMatch(input string, candidates []string) string {
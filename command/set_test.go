@@ -415,6 +415,45 @@ func TestSetCommand_Execute_MigrationWarning(t *testing.T) {
 	}
 }
 
+func TestSetCommand_Execute_UnusedPluralCategoryWarning(t *testing.T) {
+	testContent := `{
+		"sourceLanguage": "en",
+		"strings": {},
+		"version": "1.0"
+	}`
+
+	for _, tc := range []struct {
+		lang, plural string
+		warn         bool
+	}{
+		{"ja", "one", true},
+		{"ja", "other", false},
+		{"ja", "zero", false},
+		{"ru", "few", false},
+	} {
+		filePath := test.TempFile(t, "test.xcstrings", testContent)
+
+		cmd := &SetCommand{}
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		cmd.SetFlags(flagSet)
+		err := flagSet.Parse([]string{"-f", filePath, "--lang", tc.lang, "--plural", tc.plural, "count", "%lld"})
+		test.AssertNoError(t, err)
+
+		var stderrOutput string
+		captureOutput(func() {
+			stderrOutput = captureStderr(func() {
+				status := cmd.Execute(context.Background(), flagSet)
+				test.AssertEqual(t, int(status), 0)
+			})
+		})
+
+		warned := strings.Contains(stderrOutput, "is never used by language")
+		if warned != tc.warn {
+			t.Errorf("--lang %s --plural %s: expected warning %v, got stderr: %q", tc.lang, tc.plural, tc.warn, stderrOutput)
+		}
+	}
+}
+
 func TestSetCommand_Execute_ForceFlag(t *testing.T) {
 	testContent := `{
 		"sourceLanguage": "en",
@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/google/subcommands"
+)
+
+type SplitCommand struct {
+	XCStringsCommand
+	output     string
+	prefixes   stringsFlag
+	pattern    string
+	keep       bool
+	dryRun     bool
+	jsonOutput bool
+}
+
+func (*SplitCommand) Name() string {
+	return "split"
+}
+
+func (*SplitCommand) Synopsis() string {
+	return "Move keys matching a prefix or regular expression into a new catalog"
+}
+
+func (*SplitCommand) Usage() string {
+	return "split [-f file.xcstrings] -o <new.xcstrings> (--prefix <prefix> ... | --pattern <regexp>) [--keep] [--dry-run] [--json]: Move every key starting with a --prefix or matching --pattern into <new.xcstrings>, which gets the catalog's sourceLanguage and version\n"
+}
+
+func (c *SplitCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.StringVar(&c.output, "o", "", "Path of the new .xcstrings file (must not exist)")
+	f.Var(&c.prefixes, "prefix", "Move keys with this prefix (repeatable)")
+	f.StringVar(&c.pattern, "pattern", "", "Move keys matching this regular expression")
+	f.BoolVar(&c.keep, "keep", false, "Copy the keys instead of removing them from the source catalog")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show the keys that would be moved without writing any file")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output a structured JSON document describing the moved keys")
+}
+
+// splitJSONOutput is the top-level document printed by `split --json`.
+type splitJSONOutput struct {
+	DryRun  bool     `json:"dryRun"`
+	Output  string   `json:"output"`
+	Removed bool     `json:"removed"`
+	Keys    []string `json:"keys"`
+}
+
+func (c *SplitCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.output == "" || (len(c.prefixes) == 0 && c.pattern == "") || f.NArg() > 0 {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: -o and --prefix or --pattern are required\n")
+		_, _ = fmt.Fprint(flag.CommandLine.Output(), c.Usage())
+		return subcommands.ExitUsageError
+	}
+	var re *regexp.Regexp
+	if c.pattern != "" {
+		var err error
+		if re, err = regexp.Compile(c.pattern); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: invalid --pattern: %v\n", err)
+			return subcommands.ExitUsageError
+		}
+	}
+	if _, err := os.Stat(c.output); err == nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %s already exists; use merge to add keys to an existing catalog\n", c.output)
+		return subcommands.ExitFailure
+	}
+
+	if err := c.singleCatalog(); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	xcs, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	matched := make(map[string]bool)
+	for _, prefix := range c.prefixes {
+		for _, key := range xcs.FilterKeysByPrefix(xcs.Keys(), prefix) {
+			matched[key] = true
+		}
+	}
+	if re != nil {
+		for key := range xcs.Strings {
+			if re.MatchString(key) {
+				matched[key] = true
+			}
+		}
+	}
+	keys := make([]string, 0, len(matched))
+	for key := range matched {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: no keys match\n")
+		return subcommands.ExitFailure
+	}
+
+	extracted := xcs.Extract(keys, !c.keep)
+	if !c.dryRun {
+		// The new catalog is written first, so that a failure leaves the
+		// keys in the source.
+		if err := extracted.SaveToFile(c.output); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		if !c.keep {
+			filePath := c.filePath
+			if filePath == "" {
+				filePath = c.findXCStringsFile()
+			}
+			if err := xcs.SaveToFile(filePath); err != nil {
+				_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+				return subcommands.ExitFailure
+			}
+		}
+	}
+
+	if c.jsonOutput {
+		data, err := json.MarshalIndent(splitJSONOutput{DryRun: c.dryRun, Output: c.output, Removed: !c.keep, Keys: keys}, "", "  ")
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		fmt.Println(string(data))
+		return subcommands.ExitSuccess
+	}
+
+	prefix := ""
+	if c.dryRun {
+		prefix = "[dry-run] "
+	}
+	for _, key := range keys {
+		fmt.Printf("%s%s\n", prefix, key)
+	}
+	verb := "Moved"
+	if c.keep {
+		verb = "Copied"
+	}
+	fmt.Printf("%s%s %d keys to %s\n", prefix, verb, len(keys), c.output)
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+const splitFixture = `{
+	"sourceLanguage": "de",
+	"strings": {
+		"settings.title": {"comment": "Title", "localizations": {
+			"de": {"stringUnit": {"state": "translated", "value": "Einstellungen"}}
+		}},
+		"settings.done": {"localizations": {
+			"de": {"stringUnit": {"state": "translated", "value": "Fertig"}}
+		}},
+		"profile.title": {"localizations": {
+			"de": {"stringUnit": {"state": "translated", "value": "Profil"}}
+		}},
+		"home.title": {"localizations": {
+			"de": {"stringUnit": {"state": "translated", "value": "Start"}}
+		}}
+	},
+	"version": "1.1"
+}`
+
+func TestSplitCommand_Prefixes(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", splitFixture)
+	outPath := filepath.Join(t.TempDir(), "Settings.xcstrings")
+
+	output, status := runCommand(t, &SplitCommand{}, "-f", filePath, "-o", outPath, "--prefix", "settings.", "--prefix", "profile.")
+	test.AssertEqual(t, status, 0)
+	test.AssertEqual(t, output, "profile.title\nsettings.done\nsettings.title\nMoved 3 keys to "+outPath+"\n")
+
+	split, err := xcstrings.Load(outPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, split.SourceLanguage, "de")
+	test.AssertEqual(t, split.Version, "1.1")
+	test.AssertEqual(t, len(split.Strings), 3)
+	test.AssertEqual(t, split.Strings["settings.title"].Comment, "Title")
+
+	source, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, source.Keys(), []string{"home.title"})
+}
+
+func TestSplitCommand_PatternKeepDryRun(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", splitFixture)
+	outPath := filepath.Join(t.TempDir(), "Titles.xcstrings")
+
+	output, status := runCommand(t, &SplitCommand{}, "-f", filePath, "-o", outPath, "--pattern", `\.title$`, "--keep", "--dry-run", "--json")
+	test.AssertEqual(t, status, 0)
+	var out splitJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertSliceEqual(t, out.Keys, []string{"home.title", "profile.title", "settings.title"})
+	test.AssertEqual(t, out.Removed, false)
+	if _, err := os.Stat(outPath); err == nil {
+		t.Error("--dry-run should not write the new catalog")
+	}
+
+	_, status = runCommand(t, &SplitCommand{}, "-f", filePath, "-o", outPath, "--pattern", `\.title$`, "--keep")
+	test.AssertEqual(t, status, 0)
+	data, err := os.ReadFile(filePath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(data), splitFixture)
+}
+
+func TestSplitCommand_Errors(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", splitFixture)
+	outPath := filepath.Join(t.TempDir(), "New.xcstrings")
+
+	for _, args := range [][]string{
+		{"--prefix", "settings."},
+		{"-o", outPath},
+		{"-o", outPath, "--pattern", "("},
+	} {
+		_, status := runCommand(t, &SplitCommand{}, append([]string{"-f", filePath}, args...)...)
+		test.AssertEqual(t, status, 2)
+	}
+
+	_, status := runCommand(t, &SplitCommand{}, "-f", filePath, "-o", outPath, "--prefix", "missing.")
+	test.AssertEqual(t, status, 1)
+	// An existing catalog is not overwritten.
+	_, status = runCommand(t, &SplitCommand{}, "-f", filePath, "-o", filePath, "--prefix", "settings.")
+	test.AssertEqual(t, status, 1)
+}
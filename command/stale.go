@@ -35,7 +35,11 @@ func (c *StaleCommand) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&c.dryRun, "dry-run", false, "Show what would be removed without modifying the file (use with --remove)")
 }
 
-func (c *StaleCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+func (c *StaleCommand) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	return c.forEachCatalog(false, func() subcommands.ExitStatus { return c.execute(ctx, f) })
+}
+
+func (c *StaleCommand) execute(_ context.Context, f *flag.FlagSet) subcommands.ExitStatus {
 	xcstrings, err := c.LoadXCStrings()
 	if err != nil {
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
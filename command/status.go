@@ -6,7 +6,9 @@ import (
 	"flag"
 	"fmt"
 	"math"
+	"slices"
 	"sort"
+	"strings"
 
 	xcstringspkg "xckit/xcstrings"
 
@@ -36,47 +38,153 @@ func (c *StatusCommand) SetFlags(f *flag.FlagSet) {
 }
 
 func (c *StatusCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
-	xcstrings, err := c.LoadXCStrings()
-	if err != nil {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
-		return subcommands.ExitFailure
+	var files []string
+	var catalogs []*xcstringspkg.XCStrings
+	var reports []statusReport
+	status := c.forEachCatalog(c.jsonOutput, func() subcommands.ExitStatus {
+		xcstrings, err := c.LoadXCStrings()
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		report := computeStatusReport(xcstrings, c.targetLanguages(xcstrings))
+		files = append(files, c.filePath)
+		catalogs = append(catalogs, xcstrings)
+		reports = append(reports, report)
+		if !c.jsonOutput {
+			printStatusReport(report)
+		}
+		return subcommands.ExitSuccess
+	})
+	if status != subcommands.ExitSuccess {
+		return status
 	}
 
-	totalKeys := len(xcstrings.Strings)
-	staleKeys := xcstrings.StaleKeys()
-	activeKeys := totalKeys - len(staleKeys)
-	languages := xcstrings.Languages()
-	sort.Strings(languages)
+	if len(reports) == 1 {
+		if c.jsonOutput {
+			return printStatusJSON(statusReportJSON(reports[0]))
+		}
+		return subcommands.ExitSuccess
+	}
+
+	total := totalStatusReport(catalogs, reports)
+	if c.jsonOutput {
+		out := statusMultiJSONOutput{Total: statusReportJSON(total)}
+		for i, report := range reports {
+			out.Catalogs = append(out.Catalogs, statusCatalogJSON{File: files[i], statusJSONOutput: statusReportJSON(report)})
+		}
+		return printStatusJSON(out)
+	}
+	fmt.Printf("\n== Total (%d catalogs) ==\n", len(reports))
+	printStatusReport(total)
+	return subcommands.ExitSuccess
+}
+
+// statusReport is the progress summary of one catalog, or the total of
+// several.
+type statusReport struct {
+	sourceLanguage string
+	totalKeys      int
+	staleKeys      int
+	activeKeys     int
+	languages      []string
+	langStats      []statusLanguageStats
+}
+
+// computeStatusReport computes the progress of xcs in each of languages.
+func computeStatusReport(xcs *xcstringspkg.XCStrings, languages []string) statusReport {
+	totalKeys := len(xcs.Strings)
+	staleKeys := len(xcs.StaleKeys())
+	activeKeys := totalKeys - staleKeys
 
 	langStats := make([]statusLanguageStats, 0, len(languages))
 	for _, lang := range languages {
-		langStats = append(langStats, computeStatusLanguageStats(xcstrings, lang, activeKeys))
+		langStats = append(langStats, computeStatusLanguageStats(xcs, lang, activeKeys))
+	}
+	return statusReport{
+		sourceLanguage: xcs.SourceLanguage,
+		totalKeys:      totalKeys,
+		staleKeys:      staleKeys,
+		activeKeys:     activeKeys,
+		languages:      languages,
+		langStats:      langStats,
 	}
+}
 
-	if c.jsonOutput {
-		return c.printJSON(xcstrings, totalKeys, len(staleKeys), activeKeys, langStats)
+// totalStatusReport adds up the progress of several catalogs, given their
+// individual reports. Every language of any report is counted against every
+// catalog, so a catalog lacking a language entirely counts as untranslated
+// for it. The source language is a comma-separated list when the catalogs
+// disagree.
+func totalStatusReport(catalogs []*xcstringspkg.XCStrings, reports []statusReport) statusReport {
+	var total statusReport
+	var sources []string
+	languageSet := make(map[string]bool)
+	for i, xcs := range catalogs {
+		if !slices.Contains(sources, xcs.SourceLanguage) {
+			sources = append(sources, xcs.SourceLanguage)
+		}
+		for _, lang := range reports[i].languages {
+			languageSet[lang] = true
+		}
+	}
+	total.sourceLanguage = strings.Join(sources, ",")
+	for lang := range languageSet {
+		total.languages = append(total.languages, lang)
+	}
+	sort.Strings(total.languages)
+
+	for _, lang := range total.languages {
+		sum := statusLanguageStats{Language: lang}
+		for _, xcs := range catalogs {
+			if lang == xcs.SourceLanguage {
+				continue
+			}
+			activeKeys := len(xcs.Strings) - len(xcs.StaleKeys())
+			s := computeStatusLanguageStats(xcs, lang, activeKeys)
+			sum.TranslatedKeys += s.TranslatedKeys
+			sum.TotalKeys += s.TotalKeys
+			sum.TranslatedUnits += s.TranslatedUnits
+			sum.TotalUnits += s.TotalUnits
+			sum.NeedsReviewCount += s.NeedsReviewCount
+		}
+		if sum.TotalKeys > 0 {
+			sum.KeysPercentage = roundTo1Decimal(float64(sum.TranslatedKeys) / float64(sum.TotalKeys) * 100)
+		}
+		if sum.TotalUnits > 0 {
+			sum.UnitsPercentage = roundTo1Decimal(float64(sum.TranslatedUnits) / float64(sum.TotalUnits) * 100)
+		}
+		total.langStats = append(total.langStats, sum)
 	}
 
+	for _, xcs := range catalogs {
+		stale := len(xcs.StaleKeys())
+		total.totalKeys += len(xcs.Strings)
+		total.staleKeys += stale
+		total.activeKeys += len(xcs.Strings) - stale
+	}
+	return total
+}
+
+func printStatusReport(r statusReport) {
 	fmt.Printf("Translation Status\n")
 	fmt.Printf("==================\n")
-	fmt.Printf("Source Language: %s\n", xcstrings.SourceLanguage)
-	fmt.Printf("Total Keys: %d\n", totalKeys)
-	if len(staleKeys) > 0 {
-		fmt.Printf("Stale Keys: %d\n", len(staleKeys))
-		fmt.Printf("Active Keys: %d\n", activeKeys)
+	fmt.Printf("Source Language: %s\n", r.sourceLanguage)
+	fmt.Printf("Total Keys: %d\n", r.totalKeys)
+	if r.staleKeys > 0 {
+		fmt.Printf("Stale Keys: %d\n", r.staleKeys)
+		fmt.Printf("Active Keys: %d\n", r.activeKeys)
 	}
-	fmt.Printf("Languages: %s\n\n", languages)
+	fmt.Printf("Languages: %s\n\n", r.languages)
 
 	fmt.Printf("Progress by Language:\n")
 	fmt.Printf("--------------------\n")
 
-	for _, s := range langStats {
+	for _, s := range r.langStats {
 		fmt.Printf("%-6s: Keys %3d/%d (%.1f%%), Strings %3d/%d (%.1f%%), %d needs_review\n",
 			s.Language, s.TranslatedKeys, s.TotalKeys, s.KeysPercentage,
 			s.TranslatedUnits, s.TotalUnits, s.UnitsPercentage, s.NeedsReviewCount)
 	}
-
-	return subcommands.ExitSuccess
 }
 
 // statusLanguageStats holds the translation progress figures for a single language.
@@ -172,16 +280,29 @@ type statusJSONProgress struct {
 	Percentage float64 `json:"percentage"`
 }
 
-// printJSON marshals the status summary to a single JSON document and writes it to stdout.
-func (c *StatusCommand) printJSON(xcs *xcstringspkg.XCStrings, totalKeys, staleKeys, activeKeys int, langStats []statusLanguageStats) subcommands.ExitStatus {
+// statusCatalogJSON is a single catalog's summary in multi-catalog
+// `status --json` output.
+type statusCatalogJSON struct {
+	File string `json:"file"`
+	statusJSONOutput
+}
+
+// statusMultiJSONOutput is the document printed by `status --json` for
+// several catalogs.
+type statusMultiJSONOutput struct {
+	Catalogs []statusCatalogJSON `json:"catalogs"`
+	Total    statusJSONOutput    `json:"total"`
+}
+
+func statusReportJSON(r statusReport) statusJSONOutput {
 	out := statusJSONOutput{
-		SourceLanguage: xcs.SourceLanguage,
-		TotalKeys:      totalKeys,
-		StaleKeys:      staleKeys,
-		ActiveKeys:     activeKeys,
-		Languages:      make([]statusJSONLangEntry, 0, len(langStats)),
+		SourceLanguage: r.sourceLanguage,
+		TotalKeys:      r.totalKeys,
+		StaleKeys:      r.staleKeys,
+		ActiveKeys:     r.activeKeys,
+		Languages:      make([]statusJSONLangEntry, 0, len(r.langStats)),
 	}
-	for _, s := range langStats {
+	for _, s := range r.langStats {
 		out.Languages = append(out.Languages, statusJSONLangEntry{
 			Language: s.Language,
 			Keys: statusJSONProgress{
@@ -197,7 +318,11 @@ func (c *StatusCommand) printJSON(xcs *xcstringspkg.XCStrings, totalKeys, staleK
 			NeedsReview: s.NeedsReviewCount,
 		})
 	}
+	return out
+}
 
+// printStatusJSON marshals a status document and writes it to stdout.
+func printStatusJSON(out any) subcommands.ExitStatus {
 	data, err := json.MarshalIndent(out, "", "  ")
 	if err != nil {
 		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"flag"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -266,3 +267,47 @@ func TestStatusCommand_Execute_FileNotFound(t *testing.T) {
 	status := cmd.Execute(context.Background(), flagSet)
 	test.AssertEqual(t, int(status), 1) // ExitFailure
 }
+
+func TestStatusCommand_Execute_MultipleCatalogs(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"Localizable.xcstrings": localizableFixture,
+		"InfoPlist.xcstrings":   infoPlistFixture,
+	})
+
+	cmd := &StatusCommand{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", dir, "--json"}))
+
+	output := captureOutput(func() {
+		test.AssertEqual(t, int(cmd.Execute(context.Background(), flagSet)), 0)
+	})
+
+	var parsed statusMultiJSONOutput
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("output should be valid JSON, got error %v, output: %q", err, output)
+	}
+	test.AssertEqual(t, len(parsed.Catalogs), 2)
+	test.AssertEqual(t, parsed.Catalogs[0].File, filepath.Join(dir, "InfoPlist.xcstrings"))
+	test.AssertEqual(t, parsed.Total.TotalKeys, 3)
+
+	// fr only exists in InfoPlist, so Localizable's two keys count as
+	// untranslated for it in the total.
+	test.AssertEqual(t, len(parsed.Total.Languages), 2)
+	fr, ja := parsed.Total.Languages[0], parsed.Total.Languages[1]
+	test.AssertEqual(t, fr.Keys, statusJSONProgress{Translated: 1, Total: 3, Percentage: 33.3})
+	test.AssertEqual(t, ja.Keys, statusJSONProgress{Translated: 2, Total: 3, Percentage: 66.7})
+
+	flagSet = flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd = &StatusCommand{}
+	cmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", dir}))
+	output = captureOutput(func() {
+		test.AssertEqual(t, int(cmd.Execute(context.Background(), flagSet)), 0)
+	})
+	for _, want := range []string{"== " + filepath.Join(dir, "Localizable.xcstrings") + " ==", "== Total (2 catalogs) ==", "ja    : Keys   2/3 (66.7%)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"xckit/translator"
+	"xckit/xcstrings"
+
+	"github.com/google/subcommands"
+)
+
+// Environment variables configuring the HTTP translation backend, so that
+// the API key never has to appear on a command line.
+const (
+	translateEndpointEnv = "XCKIT_TRANSLATE_ENDPOINT"
+	translateAPIKeyEnv   = "XCKIT_TRANSLATE_API_KEY"
+)
+
+type TranslateCommand struct {
+	XCStringsCommand
+	language   string
+	prefix     string
+	endpoint   string
+	batchSize  int
+	dryRun     bool
+	jsonOutput bool
+
+	// backend overrides the HTTP backend built from --endpoint.
+	backend translator.Translator
+}
+
+func (*TranslateCommand) Name() string {
+	return "translate"
+}
+
+func (*TranslateCommand) Synopsis() string {
+	return "Machine-translate untranslated strings"
+}
+
+func (*TranslateCommand) Usage() string {
+	return "translate [-f file.xcstrings] --lang <language> [--prefix <prefix>] [--endpoint <url>] [--batch-size <n>] [--dry-run] [--json]: Fill untranslated strings with machine translations, marked needs_review\n" +
+		"  The endpoint (default: $" + translateEndpointEnv + ") receives POST {\"sourceLanguage\", \"targetLanguage\", \"texts\": [...]} and must answer {\"translations\": [...]}; $" + translateAPIKeyEnv + ", when set, is sent as a Bearer token.\n"
+}
+
+func (c *TranslateCommand) SetFlags(f *flag.FlagSet) {
+	c.SetXCStringsFlags(f)
+	f.StringVar(&c.language, "lang", "", "Target language code (e.g., ja, fr, de)")
+	f.StringVar(&c.prefix, "prefix", "", "Only translate keys with this prefix")
+	f.StringVar(&c.endpoint, "endpoint", "", "URL of the HTTP/JSON translation endpoint (default: $"+translateEndpointEnv+")")
+	f.IntVar(&c.batchSize, "batch-size", 50, "Number of strings sent per request")
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show the translations without writing the file")
+	f.BoolVar(&c.jsonOutput, "json", false, "Output a structured JSON document describing the translations")
+}
+
+// translateUnit is a single leaf string unit to translate.
+type translateUnit struct {
+	key    string
+	path   string // variation path, "" for the plain/host string
+	source string
+	result string
+	err    error
+}
+
+// translateJSONResult is a single entry of `translate --json` output.
+type translateJSONResult struct {
+	Key         string `json:"key"`
+	Path        string `json:"path,omitempty"`
+	Source      string `json:"source"`
+	Translation string `json:"translation,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// translateJSONOutput is the top-level document printed by `translate --json`.
+type translateJSONOutput struct {
+	Language string                `json:"language"`
+	DryRun   bool                  `json:"dryRun"`
+	Results  []translateJSONResult `json:"results"`
+	Summary  struct {
+		Translated int `json:"translated"`
+		Skipped    int `json:"skipped"`
+	} `json:"summary"`
+}
+
+func (c *TranslateCommand) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if c.language == "" {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --lang is required\n")
+		return subcommands.ExitUsageError
+	}
+	if c.batchSize < 1 {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --batch-size must be at least 1\n")
+		return subcommands.ExitUsageError
+	}
+
+	backend := c.backend
+	if backend == nil {
+		endpoint := c.endpoint
+		if endpoint == "" {
+			endpoint = os.Getenv(translateEndpointEnv)
+		}
+		if endpoint == "" {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --endpoint or $%s is required\n", translateEndpointEnv)
+			return subcommands.ExitUsageError
+		}
+		backend = translator.NewHTTPTranslator(endpoint, os.Getenv(translateAPIKeyEnv))
+	}
+
+	return c.forEachCatalog(c.jsonOutput, func() subcommands.ExitStatus { return c.execute(ctx, backend) })
+}
+
+func (c *TranslateCommand) execute(ctx context.Context, backend translator.Translator) subcommands.ExitStatus {
+	xcs, err := c.LoadXCStrings()
+	if err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+	if c.language == xcs.SourceLanguage {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: --lang %s is the source language\n", c.language)
+		return subcommands.ExitUsageError
+	}
+
+	units := collectTranslateUnits(xcs, c.language, c.prefix)
+	if err := translateUnits(ctx, backend, xcs.SourceLanguage, c.language, units, c.batchSize); err != nil {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	translated := 0
+	for i := range units {
+		u := &units[i]
+		if u.err != nil {
+			continue
+		}
+		if err := setTranslation(xcs, u.key, c.language, u.result, u.path); err != nil {
+			u.err = err
+			continue
+		}
+		if unit := currentTranslationUnit(xcs, u.key, c.language, u.path); unit != nil {
+			unit.State = "needs_review"
+		}
+		translated++
+	}
+
+	if !c.dryRun && translated > 0 {
+		filePath := c.filePath
+		if filePath == "" {
+			filePath = c.findXCStringsFile()
+		}
+		if err := xcs.SaveToFile(filePath); err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error saving file: %v\n", err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	if c.jsonOutput {
+		out := translateJSONOutput{Language: c.language, DryRun: c.dryRun, Results: make([]translateJSONResult, 0, len(units))}
+		for _, u := range units {
+			r := translateJSONResult{Key: u.key, Path: u.path, Source: u.source}
+			if u.err != nil {
+				r.Error = u.err.Error()
+			} else {
+				r.Translation = u.result
+			}
+			out.Results = append(out.Results, r)
+		}
+		out.Summary.Translated = translated
+		out.Summary.Skipped = len(units) - translated
+		return c.printJSON(out)
+	}
+
+	prefix := ""
+	if c.dryRun {
+		prefix = "[dry-run] "
+	}
+	for _, u := range units {
+		label := unitLabel(u.key, u.path)
+		if u.err != nil {
+			fmt.Printf("%sSkipped %s: %v\n", prefix, label, u.err)
+			continue
+		}
+		fmt.Printf("%s%s: %q -> %q\n", prefix, label, u.source, u.result)
+	}
+	fmt.Printf("%sSummary: %d translated (needs_review), %d skipped\n", prefix, translated, len(units)-translated)
+	return subcommands.ExitSuccess
+}
+
+// collectTranslateUnits returns the leaf string units of lang to translate,
+// sorted by key and path: every unit UntranslatedDetailsForLanguage reports
+// except those already awaiting review, and, for keys lang has no
+// localization for, every leaf of the source localization. Units whose
+// source text is empty are left out.
+func collectTranslateUnits(xcs *xcstrings.XCStrings, lang, prefix string) []translateUnit {
+	var units []translateUnit
+	add := func(key, path string) {
+		def := xcs.Strings[key]
+		source := xliffSourceText(key, def.Localizations[xcs.SourceLanguage], path)
+		if source == "" {
+			return
+		}
+		units = append(units, translateUnit{key: key, path: path, source: source})
+	}
+
+	for _, d := range xcs.UntranslatedDetailsForLanguage(lang) {
+		if !strings.HasPrefix(d.Key, prefix) {
+			continue
+		}
+		if d.Path == "missing" {
+			def := xcs.Strings[d.Key]
+			for _, path := range translationPaths(def, xcs.SourceLanguage, xcs.SourceLanguage) {
+				add(d.Key, path)
+			}
+			continue
+		}
+		// A plain stringUnit is reported by its state rather than a path.
+		path := d.Path
+		if !strings.HasPrefix(path, "plural.") && !strings.HasPrefix(path, "device.") && !strings.HasPrefix(path, "substitutions.") {
+			path = ""
+		}
+		if unit := currentTranslationUnit(xcs, d.Key, lang, path); unit != nil && unit.State == "needs_review" {
+			continue
+		}
+		add(d.Key, path)
+	}
+
+	sort.Slice(units, func(i, j int) bool {
+		if units[i].key != units[j].key {
+			return units[i].key < units[j].key
+		}
+		return units[i].path < units[j].path
+	})
+	return units
+}
+
+// translateUnits sends the units' source texts to backend in batches, with
+// format tokens protected, and records each translation or per-unit error.
+// An error is returned only when a batch request itself fails.
+func translateUnits(ctx context.Context, backend translator.Translator, sourceLang, targetLang string, units []translateUnit, batchSize int) error {
+	for start := 0; start < len(units); start += batchSize {
+		batch := units[start:min(start+batchSize, len(units))]
+		texts := make([]string, len(batch))
+		tokens := make([][]string, len(batch))
+		for i, u := range batch {
+			texts[i], tokens[i] = translator.Protect(u.source)
+		}
+
+		results, err := backend.Translate(ctx, sourceLang, targetLang, texts)
+		if err != nil {
+			return err
+		}
+		if len(results) != len(batch) {
+			return fmt.Errorf("translation backend returned %d translations for %d texts", len(results), len(batch))
+		}
+		for i := range batch {
+			if strings.TrimSpace(results[i]) == "" {
+				batch[i].err = fmt.Errorf("translation is empty")
+				continue
+			}
+			batch[i].result, batch[i].err = translator.Restore(results[i], tokens[i])
+		}
+	}
+	return nil
+}
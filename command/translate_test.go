@@ -0,0 +1,141 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+// newTranslateStub starts a local HTTP/JSON translation endpoint that
+// returns translate(text) for every text it receives, and records the texts.
+func newTranslateStub(t *testing.T, translate func(string) string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			TargetLanguage string   `json:"targetLanguage"`
+			Texts          []string `json:"texts"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received = append(received, req.Texts...)
+		out := struct {
+			Translations []string `json:"translations"`
+		}{}
+		for _, text := range req.Texts {
+			out.Translations = append(out.Translations, translate(text))
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestTranslateCommand_FillsUntranslatedAsNeedsReview(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	server, received := newTranslateStub(t, func(s string) string { return "[ja] " + s })
+
+	output, status := runCommand(t, &TranslateCommand{}, "-f", filePath, "--lang", "ja", "--endpoint", server.URL)
+	test.AssertEqual(t, status, 0)
+	if !strings.Contains(output, "Summary: 3 translated (needs_review), 0 skipped") {
+		t.Errorf("unexpected summary: %q", output)
+	}
+
+	// Format tokens reach the backend as placeholders, and greeting (already
+	// needs_review) and debug (shouldTranslate: false) are not sent at all.
+	test.AssertSliceEqual(t, *received, []string{`<x id="0"/>`, `<x id="0"/> file`, `<x id="0"/> files`})
+
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	ja := xc.Strings["files"].Localizations["ja"]
+	test.AssertEqual(t, ja.StringUnit.Value, "[ja] %#@files@")
+	test.AssertEqual(t, ja.StringUnit.State, "needs_review")
+	one := ja.Substitutions["files"].Variations.Plural["one"].StringUnit
+	test.AssertEqual(t, one.Value, "[ja] %arg file")
+	test.AssertEqual(t, one.State, "needs_review")
+	test.AssertEqual(t, xc.Strings["greeting"].Localizations["ja"].StringUnit.Value, "こんにちは")
+}
+
+func TestTranslateCommand_DryRunDoesNotWrite(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	server, _ := newTranslateStub(t, func(s string) string { return "[ja] " + s })
+
+	output, status := runCommand(t, &TranslateCommand{}, "-f", filePath, "--lang", "ja", "--endpoint", server.URL, "--dry-run")
+	test.AssertEqual(t, status, 0)
+	if !strings.Contains(output, `[dry-run] files[substitutions.files.plural.one]: "%arg file" -> "[ja] %arg file"`) {
+		t.Errorf("expected a preview of the translation, got: %q", output)
+	}
+
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	if _, ok := xc.Strings["files"].Localizations["ja"]; ok {
+		t.Error("--dry-run should not write the file")
+	}
+}
+
+func TestTranslateCommand_SkipsTranslationsThatLoseTokens(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	server, _ := newTranslateStub(t, func(s string) string {
+		if strings.HasSuffix(s, " files") {
+			return "ファイル"
+		}
+		return s
+	})
+
+	output, status := runCommand(t, &TranslateCommand{}, "-f", filePath, "--lang", "ja", "--endpoint", server.URL, "--json")
+	test.AssertEqual(t, status, 0)
+	var out translateJSONOutput
+	test.AssertNoError(t, json.Unmarshal([]byte(output), &out))
+	test.AssertEqual(t, out.Summary.Translated, 2)
+	test.AssertEqual(t, out.Summary.Skipped, 1)
+	test.AssertEqual(t, out.Results[2].Path, "substitutions.files.plural.other")
+	if !strings.Contains(out.Results[2].Error, "dropped %arg") {
+		t.Errorf("expected a dropped-token error, got %q", out.Results[2].Error)
+	}
+}
+
+func TestTranslateCommand_BatchSize(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req struct {
+			Texts []string `json:"texts"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.NewEncoder(w).Encode(map[string][]string{"translations": req.Texts})
+	}))
+	defer server.Close()
+
+	_, status := runCommand(t, &TranslateCommand{}, "-f", filePath, "--lang", "fr", "--endpoint", server.URL, "--batch-size", "2")
+	test.AssertEqual(t, status, 0)
+	// fr is missing everywhere: greeting, item_count (one, other) and files
+	// (host, one, other) make 6 units, sent as 3 batches of 2.
+	test.AssertEqual(t, requests, 3)
+}
+
+func TestTranslateCommand_RequiresEndpointAndLang(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	t.Setenv(translateEndpointEnv, "")
+
+	for _, args := range [][]string{
+		{"-f", filePath, "--endpoint", "http://localhost"},
+		{"-f", filePath, "--lang", "ja"},
+		{"-f", filePath, "--lang", "en", "--endpoint", "http://localhost"},
+	} {
+		cmd := &TranslateCommand{}
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		cmd.SetFlags(flagSet)
+		test.AssertNoError(t, flagSet.Parse(args))
+		test.AssertEqual(t, int(cmd.Execute(context.Background(), flagSet)), 2)
+	}
+}
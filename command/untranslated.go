@@ -45,23 +45,69 @@ func (c *UntranslatedCommand) SetFlags(f *flag.FlagSet) {
 }
 
 func (c *UntranslatedCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
-	xcs, err := c.LoadXCStrings()
-	if err != nil {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
-		return subcommands.ExitFailure
+	total, count := 0, 0
+	var catalogs []untranslatedCatalogJSON
+	status := c.forEachCatalog(c.jsonOutput, func() subcommands.ExitStatus {
+		xcs, err := c.LoadXCStrings()
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		count++
+
+		switch {
+		case c.jsonOutput:
+			items := c.collectJSONItems(xcs)
+			catalogs = append(catalogs, untranslatedCatalogJSON{File: c.filePath, Untranslated: items})
+			total += len(items)
+		case c.detail:
+			total += c.executeDetail(xcs)
+		default:
+			total += c.executeKeys(xcs)
+		}
+		return subcommands.ExitSuccess
+	})
+	if status != subcommands.ExitSuccess {
+		return status
 	}
 
 	if c.jsonOutput {
-		return c.executeJSON(xcs)
-	}
-
-	if c.detail {
-		return c.executeDetail(xcs)
+		var out any = untranslatedMultiJSONOutput{Catalogs: catalogs, Total: total}
+		if len(catalogs) == 1 {
+			out = untranslatedJSONOutput{Untranslated: catalogs[0].Untranslated}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
+			return subcommands.ExitFailure
+		}
+		fmt.Println(string(data))
+	} else if count > 1 {
+		unit := "keys"
+		if c.detail {
+			unit = "strings"
+		}
+		fmt.Printf("\n== Total: %d untranslated %s in %d catalogs ==\n", total, unit, count)
 	}
+	return c.exitStatus(total > 0)
+}
 
+// executeKeys prints the untranslated keys of one catalog and returns how
+// many there are.
+func (c *UntranslatedCommand) executeKeys(xcs *xcstrings.XCStrings) int {
 	var untranslatedKeys []string
 	if c.language != "" {
 		untranslatedKeys = xcs.UntranslatedKeys(c.language)
+	} else if c.hasConfiguredLanguages() {
+		seen := make(map[string]bool)
+		for _, lang := range c.targetLanguages(xcs) {
+			for _, key := range xcs.UntranslatedKeys(lang) {
+				if !seen[key] {
+					seen[key] = true
+					untranslatedKeys = append(untranslatedKeys, key)
+				}
+			}
+		}
 	} else {
 		untranslatedKeys = xcs.KeysWithAnyUntranslated()
 	}
@@ -70,16 +116,8 @@ func (c *UntranslatedCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...i
 	sort.Strings(untranslatedKeys)
 
 	if len(untranslatedKeys) == 0 {
-		if c.prefix != "" && c.language != "" {
-			fmt.Printf("No untranslated keys found with prefix '%s' for language '%s'\n", c.prefix, c.language)
-		} else if c.prefix != "" {
-			fmt.Printf("No untranslated keys found with prefix '%s'\n", c.prefix)
-		} else if c.language != "" {
-			fmt.Printf("All keys are translated for language '%s'\n", c.language)
-		} else {
-			fmt.Println("All keys are fully translated in all languages")
-		}
-		return subcommands.ExitSuccess
+		c.printNoneFound()
+		return 0
 	}
 
 	if c.prefix != "" && c.language != "" {
@@ -93,7 +131,25 @@ func (c *UntranslatedCommand) Execute(_ context.Context, f *flag.FlagSet, _ ...i
 	}
 
 	formatter.DisplayKeyDetails(xcs, untranslatedKeys)
-	return c.exitStatus(len(untranslatedKeys) > 0)
+	return len(untranslatedKeys)
+}
+
+func (c *UntranslatedCommand) printNoneFound() {
+	if c.prefix != "" && c.language != "" {
+		fmt.Printf("No untranslated keys found with prefix '%s' for language '%s'\n", c.prefix, c.language)
+	} else if c.prefix != "" {
+		fmt.Printf("No untranslated keys found with prefix '%s'\n", c.prefix)
+	} else if c.language != "" {
+		fmt.Printf("All keys are translated for language '%s'\n", c.language)
+	} else {
+		fmt.Println("All keys are fully translated in all languages")
+	}
+}
+
+// hasConfiguredLanguages reports whether the project configuration lists
+// the languages to check, instead of those found in each catalog.
+func (c *UntranslatedCommand) hasConfiguredLanguages() bool {
+	return c.config != nil && len(c.config.Languages) > 0
 }
 
 // exitStatus returns ExitFailure when hasUntranslated is true and --fail-if-any
@@ -111,6 +167,10 @@ func (c *UntranslatedCommand) collectFilteredDetails(xcs *xcstrings.XCStrings) [
 	var details []xcstrings.UntranslatedDetail
 	if c.language != "" {
 		details = xcs.UntranslatedDetailsForLanguage(c.language)
+	} else if c.hasConfiguredLanguages() {
+		for _, lang := range c.targetLanguages(xcs) {
+			details = append(details, xcs.UntranslatedDetailsForLanguage(lang)...)
+		}
 	} else {
 		details = xcs.UntranslatedDetailsForAllLanguages()
 	}
@@ -150,44 +210,44 @@ type untranslatedJSONItem struct {
 	Path     string `json:"path"`
 }
 
-// executeJSON prints a single JSON document describing every untranslated
-// leaf string unit (always at --detail granularity, regardless of --detail)
-// and applies --fail-if-any to the exit status.
-func (c *UntranslatedCommand) executeJSON(xcs *xcstrings.XCStrings) subcommands.ExitStatus {
+// untranslatedCatalogJSON is a single catalog's entry in multi-catalog
+// `untranslated --json` output.
+type untranslatedCatalogJSON struct {
+	File         string                 `json:"file"`
+	Untranslated []untranslatedJSONItem `json:"untranslated"`
+}
+
+// untranslatedMultiJSONOutput is the document printed by `untranslated
+// --json` for several catalogs.
+type untranslatedMultiJSONOutput struct {
+	Catalogs []untranslatedCatalogJSON `json:"catalogs"`
+	Total    int                       `json:"total"`
+}
+
+// collectJSONItems returns every untranslated leaf string unit, always at
+// --detail granularity, regardless of --detail.
+func (c *UntranslatedCommand) collectJSONItems(xcs *xcstrings.XCStrings) []untranslatedJSONItem {
 	details := c.collectFilteredDetails(xcs)
 
 	items := make([]untranslatedJSONItem, 0, len(details))
 	for _, d := range details {
 		items = append(items, untranslatedJSONItem{Key: d.Key, Language: d.Language, Path: d.Path})
 	}
-
-	data, err := json.MarshalIndent(untranslatedJSONOutput{Untranslated: items}, "", "  ")
-	if err != nil {
-		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Error: %v\n", err)
-		return subcommands.ExitFailure
-	}
-	fmt.Println(string(data))
-	return c.exitStatus(len(items) > 0)
+	return items
 }
 
-func (c *UntranslatedCommand) executeDetail(xcs *xcstrings.XCStrings) subcommands.ExitStatus {
+// executeDetail prints the untranslated leaf string units of one catalog
+// and returns how many there are.
+func (c *UntranslatedCommand) executeDetail(xcs *xcstrings.XCStrings) int {
 	details := c.collectFilteredDetails(xcs)
 
 	if len(details) == 0 {
-		if c.prefix != "" && c.language != "" {
-			fmt.Printf("No untranslated keys found with prefix '%s' for language '%s'\n", c.prefix, c.language)
-		} else if c.prefix != "" {
-			fmt.Printf("No untranslated keys found with prefix '%s'\n", c.prefix)
-		} else if c.language != "" {
-			fmt.Printf("All keys are translated for language '%s'\n", c.language)
-		} else {
-			fmt.Println("All keys are fully translated in all languages")
-		}
-		return subcommands.ExitSuccess
+		c.printNoneFound()
+		return 0
 	}
 
 	for _, d := range details {
 		fmt.Printf("%s > %s > %s\n", d.Key, d.Language, d.Path)
 	}
-	return c.exitStatus(len(details) > 0)
+	return len(details)
 }
@@ -859,3 +859,40 @@ func TestUntranslatedCommand_Execute_WithFixtures(t *testing.T) {
 		})
 	}
 }
+
+func TestUntranslatedCommand_Execute_MultipleCatalogs(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"Localizable.xcstrings": localizableFixture,
+		"InfoPlist.xcstrings":   infoPlistFixture,
+	})
+
+	cmd := &UntranslatedCommand{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", dir, "--lang", "ja", "--json", "--fail-if-any"}))
+
+	output := captureOutput(func() {
+		test.AssertEqual(t, int(cmd.Execute(context.Background(), flagSet)), 1)
+	})
+
+	var parsed untranslatedMultiJSONOutput
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("output should be valid JSON, got error %v, output: %q", err, output)
+	}
+	test.AssertEqual(t, parsed.Total, 1)
+	test.AssertEqual(t, len(parsed.Catalogs), 2)
+	test.AssertEqual(t, len(parsed.Catalogs[0].Untranslated), 0)
+	test.AssertEqual(t, parsed.Catalogs[1].File, filepath.Join(dir, "Localizable.xcstrings"))
+	test.AssertEqual(t, parsed.Catalogs[1].Untranslated[0].Key, "bye")
+
+	cmd = &UntranslatedCommand{}
+	flagSet = flag.NewFlagSet("test", flag.ContinueOnError)
+	cmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", dir, "--lang", "fr", "--detail"}))
+	output = captureOutput(func() {
+		test.AssertEqual(t, int(cmd.Execute(context.Background(), flagSet)), 0)
+	})
+	if !strings.Contains(output, "== Total: 2 untranslated strings in 2 catalogs ==") {
+		t.Errorf("expected a total line, got:\n%s", output)
+	}
+}
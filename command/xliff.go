@@ -0,0 +1,259 @@
+package command
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"xckit/xcstrings"
+)
+
+// xliffIDSeparator joins a key and its variation path in a trans-unit id, the
+// way Xcode names variation units in .xcloc exports (e.g.
+// "item_count|==|plural.one", "files|==|substitutions.arg1.plural.other").
+const xliffIDSeparator = "|==|"
+
+// xliffDocument is the root <xliff> element of an XLIFF 1.2 document.
+type xliffDocument struct {
+	XMLName xml.Name    `xml:"xliff"`
+	Xmlns   string      `xml:"xmlns,attr,omitempty"`
+	Version string      `xml:"version,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+// xliffFile holds the trans-units for a single target language.
+type xliffFile struct {
+	Original       string      `xml:"original,attr"`
+	SourceLanguage string      `xml:"source-language,attr"`
+	TargetLanguage string      `xml:"target-language,attr"`
+	Datatype       string      `xml:"datatype,attr"`
+	Header         xliffHeader `xml:"header"`
+	Body           xliffBody   `xml:"body"`
+}
+
+type xliffHeader struct {
+	Tool xliffTool `xml:"tool"`
+}
+
+type xliffTool struct {
+	ToolID      string `xml:"tool-id,attr"`
+	ToolName    string `xml:"tool-name,attr"`
+	ToolVersion string `xml:"tool-version,attr,omitempty"`
+}
+
+type xliffBody struct {
+	TransUnits []xliffTransUnit `xml:"trans-unit"`
+}
+
+// xliffTransUnit is a single translatable leaf: a plain string, or one
+// plural/device/substitution variation of a key.
+type xliffTransUnit struct {
+	ID        string       `xml:"id,attr"`
+	Space     string       `xml:"xml:space,attr,omitempty"`
+	Translate string       `xml:"translate,attr,omitempty"`
+	Source    string       `xml:"source"`
+	Target    *xliffTarget `xml:"target"`
+	Note      string       `xml:"note,omitempty"`
+}
+
+type xliffTarget struct {
+	State string `xml:"state,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+// writeXLIFF writes the catalog as an XLIFF 1.2 document with one <file>
+// element per target language (only language, when non-empty). original is
+// recorded as each file's original attribute.
+func writeXLIFF(w io.Writer, xc *xcstrings.XCStrings, original, language string) error {
+	langs := buildLanguageOrder(xc)[1:]
+	if language != "" {
+		langs = []string{language}
+	}
+
+	keys := make([]string, 0, len(xc.Strings))
+	for k := range xc.Strings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	doc := xliffDocument{
+		Xmlns:   "urn:oasis:names:tc:xliff:document:1.2",
+		Version: "1.2",
+	}
+	for _, lang := range langs {
+		file := xliffFile{
+			Original:       original,
+			SourceLanguage: xc.SourceLanguage,
+			TargetLanguage: lang,
+			Datatype:       "plaintext",
+			Header: xliffHeader{Tool: xliffTool{
+				ToolID:      "xckit",
+				ToolName:    "xckit",
+				ToolVersion: Version,
+			}},
+		}
+		for _, key := range keys {
+			file.Body.TransUnits = append(file.Body.TransUnits, xliffTransUnits(key, xc.Strings[key], xc.SourceLanguage, lang)...)
+		}
+		doc.Files = append(doc.Files, file)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// xliffTransUnits produces the trans-units for a key in the given target
+// language. A key without variations becomes a single unit whose id is the
+// key; every variation leaf present in the source or target language becomes
+// its own unit with id "key|==|path". The key's host string is also emitted
+// when a key carries both a stringUnit and substitutions.
+func xliffTransUnits(key string, def xcstrings.StringDefinition, sourceLang, lang string) []xliffTransUnit {
+	srcLoc := def.Localizations[sourceLang]
+	tgtLoc := def.Localizations[lang]
+	paths := translationPaths(def, sourceLang, lang)
+
+	translate := ""
+	if def.ShouldTranslate != nil && !*def.ShouldTranslate {
+		translate = "no"
+	}
+
+	units := make([]xliffTransUnit, 0, len(paths))
+	for _, path := range paths {
+		id := key
+		if path != "" {
+			id = key + xliffIDSeparator + path
+		}
+		unit := xliffTransUnit{
+			ID:        id,
+			Space:     "preserve",
+			Translate: translate,
+			Source:    xliffSourceText(key, srcLoc, path),
+			Note:      def.Comment,
+		}
+		if su := localizationUnit(tgtLoc, path); su != nil {
+			unit.Target = &xliffTarget{State: xliffStateFromCatalog(su.State), Value: su.Value}
+		}
+		units = append(units, unit)
+	}
+	return units
+}
+
+// translationPaths returns the variation paths of a key to offer for
+// translation between the source and target language, sorted: every
+// variation leaf present in either language, preceded by "" for the host
+// string when the key has no variations or either language has a
+// stringUnit.
+func translationPaths(def xcstrings.StringDefinition, sourceLang, lang string) []string {
+	paths := collectVariationSuffixes(def, []string{sourceLang, lang})
+	sort.Strings(paths)
+	if len(paths) == 0 || def.Localizations[sourceLang].StringUnit != nil || def.Localizations[lang].StringUnit != nil {
+		paths = append([]string{""}, paths...)
+	}
+	return paths
+}
+
+// xliffSourceText returns the source-language text for a path. A plural
+// category the source language doesn't use (e.g. Russian "few" against an
+// English source) falls back to the source's "other" form at the same level,
+// and a key without a source localization falls back to the key itself, as
+// Xcode does for keys extracted from code.
+func xliffSourceText(key string, srcLoc xcstrings.Localization, path string) string {
+	if su := localizationUnit(srcLoc, path); su != nil {
+		return su.Value
+	}
+	if idx := strings.LastIndex(path, "plural."); idx >= 0 {
+		if su := localizationUnit(srcLoc, path[:idx]+"plural.other"); su != nil {
+			return su.Value
+		}
+	}
+	return key
+}
+
+// xliffStateFromCatalog maps a catalog string unit state to the XLIFF 1.2
+// target state Xcode uses for it.
+func xliffStateFromCatalog(state string) string {
+	switch state {
+	case "translated":
+		return "translated"
+	case "needs_review":
+		return "needs-review-l10n"
+	default:
+		return "new"
+	}
+}
+
+// catalogStateFromXLIFF maps an XLIFF 1.2 target state back to a catalog
+// string unit state. A target without a state attribute is treated as
+// translated, matching CSV import.
+func catalogStateFromXLIFF(state string) string {
+	switch state {
+	case "", "translated", "final", "signed-off":
+		return "translated"
+	case "new", "needs-translation":
+		return "new"
+	case "needs-review-l10n", "needs-review-translation":
+		return "needs_review"
+	default:
+		return "needs_review"
+	}
+}
+
+// parseXLIFFID splits a trans-unit id into (key, variationPath).
+func parseXLIFFID(id string) (string, string) {
+	key, path, found := strings.Cut(id, xliffIDSeparator)
+	if !found {
+		return id, ""
+	}
+	return key, path
+}
+
+// importXLIFF reads an XLIFF 1.2 document (as produced by writeXLIFF or by
+// Xcode's localization export) and applies every <target> to the catalog,
+// carrying the target's state attribute over as the string unit state.
+// Each <file> element is imported into its target-language.
+func importXLIFF(r io.Reader, xc *xcstrings.XCStrings, onMissingKey string, clearEmpty bool) (*importSummary, error) {
+	var doc xliffDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to read XLIFF: %w", err)
+	}
+
+	summary := &importSummary{}
+	for _, file := range doc.Files {
+		lang := file.TargetLanguage
+		if lang == "" {
+			return nil, fmt.Errorf("XLIFF <file original=%q> has no target-language", file.Original)
+		}
+		if lang == xc.SourceLanguage {
+			continue
+		}
+
+		for _, tu := range file.Body.TransUnits {
+			key, variationPath := parseXLIFFID(tu.ID)
+			if _, exists := xc.Strings[key]; !exists {
+				if onMissingKey == "error" {
+					return nil, fmt.Errorf("key not found: %s", key)
+				}
+				summary.skip(key, lang, variationPath, skipMissingKey, "key not found: "+key)
+				continue
+			}
+
+			if tu.Target == nil {
+				applyImportValue(xc, summary, key, lang, variationPath, "", "", clearEmpty)
+				continue
+			}
+			applyImportValue(xc, summary, key, lang, variationPath, tu.Target.Value, catalogStateFromXLIFF(tu.Target.State), clearEmpty)
+		}
+	}
+
+	return summary, nil
+}
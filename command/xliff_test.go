@@ -0,0 +1,295 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+const xliffFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"greeting": {
+			"comment": "Shown on the home screen",
+			"localizations": {
+				"en": {"stringUnit": {"state": "translated", "value": "Hello & welcome"}},
+				"ja": {"stringUnit": {"state": "needs_review", "value": "こんにちは"}}
+			}
+		},
+		"item_count": {
+			"localizations": {
+				"en": {
+					"variations": {
+						"plural": {
+							"one": {"stringUnit": {"state": "translated", "value": "%lld item"}},
+							"other": {"stringUnit": {"state": "translated", "value": "%lld items"}}
+						}
+					}
+				},
+				"ja": {
+					"variations": {
+						"plural": {
+							"other": {"stringUnit": {"state": "translated", "value": "%lld 個"}}
+						}
+					}
+				}
+			}
+		},
+		"files": {
+			"localizations": {
+				"en": {
+					"stringUnit": {"state": "translated", "value": "%#@files@"},
+					"substitutions": {
+						"files": {
+							"argNum": 1,
+							"formatSpecifier": "lld",
+							"variations": {
+								"plural": {
+									"one": {"stringUnit": {"state": "translated", "value": "%arg file"}},
+									"other": {"stringUnit": {"state": "translated", "value": "%arg files"}}
+								}
+							}
+						}
+					}
+				}
+			}
+		},
+		"debug": {
+			"shouldTranslate": false,
+			"localizations": {
+				"en": {"stringUnit": {"state": "translated", "value": "Debug"}}
+			}
+		}
+	},
+	"version": "1.0"
+}`
+
+func TestWriteXLIFF_TransUnits(t *testing.T) {
+	filePath := test.TempFile(t, "Localizable.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	var buf bytes.Buffer
+	test.AssertNoError(t, writeXLIFF(&buf, xc, "Localizable.xcstrings", ""))
+	out := buf.String()
+
+	expected := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">`,
+		`<file original="Localizable.xcstrings" source-language="en" target-language="ja" datatype="plaintext">`,
+		`<trans-unit id="debug" xml:space="preserve" translate="no">`,
+		`<trans-unit id="files" xml:space="preserve">`,
+		`<source>%#@files@</source>`,
+		`<trans-unit id="files|==|substitutions.files.plural.one" xml:space="preserve">`,
+		`<source>%arg file</source>`,
+		`<trans-unit id="greeting" xml:space="preserve">`,
+		`<source>Hello &amp; welcome</source>`,
+		`<target state="needs-review-l10n">こんにちは</target>`,
+		`<note>Shown on the home screen</note>`,
+		`<trans-unit id="item_count|==|plural.one" xml:space="preserve">`,
+		`<target state="translated">%lld 個</target>`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected XLIFF output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `target-language="en"`) {
+		t.Errorf("source language should not be exported as a target:\n%s", out)
+	}
+}
+
+func TestWriteXLIFF_SourceFallsBackToOtherForUnusedCategories(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+	_, _, err = xc.SetVariationTranslation("item_count", "ru", "%lld предмета", xcstrings.VariationOptions{Plural: "few"}, "")
+	test.AssertNoError(t, err)
+
+	var buf bytes.Buffer
+	test.AssertNoError(t, writeXLIFF(&buf, xc, "test.xcstrings", "ru"))
+	out := buf.String()
+
+	want := "<trans-unit id=\"item_count|==|plural.few\" xml:space=\"preserve\">\n        <source>%lld items</source>\n        <target state=\"translated\">%lld предмета</target>"
+	if !strings.Contains(out, want) {
+		t.Errorf("expected few to fall back to the source's other form, got:\n%s", out)
+	}
+	if strings.Contains(out, `target-language="ja"`) {
+		t.Errorf("--lang ru should only export ru:\n%s", out)
+	}
+}
+
+func TestImportXLIFF_XcodeExport(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	xliff := `<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" version="1.2" xsi:schemaLocation="urn:oasis:names:tc:xliff:document:1.2 http://docs.oasis-open.org/xliff/v1.2/os/xliff-core-1.2-strict.xsd">
+  <file original="App/Localizable.xcstrings" source-language="en" target-language="ja" datatype="plaintext">
+    <header>
+      <tool tool-id="com.apple.dt.xcode" tool-name="Xcode" tool-version="15.0" build-num="15A240d"/>
+    </header>
+    <body>
+      <trans-unit id="greeting" xml:space="preserve">
+        <source>Hello &amp; welcome</source>
+        <target state="translated">ようこそ &amp; こんにちは</target>
+        <note>Shown on the home screen</note>
+      </trans-unit>
+      <trans-unit id="item_count|==|plural.one" xml:space="preserve">
+        <source>%lld item</source>
+        <target state="needs-review-l10n">%lld 個</target>
+      </trans-unit>
+      <trans-unit id="item_count|==|plural.other" xml:space="preserve">
+        <source>%lld items</source>
+        <target state="translated">%lld 個</target>
+      </trans-unit>
+      <trans-unit id="files" xml:space="preserve">
+        <source>%#@files@</source>
+        <target state="translated">%#@files@</target>
+      </trans-unit>
+      <trans-unit id="files|==|substitutions.files.plural.other" xml:space="preserve">
+        <source>%arg files</source>
+        <target state="new">%arg 個のファイル</target>
+      </trans-unit>
+      <trans-unit id="missing.key" xml:space="preserve">
+        <source>Missing</source>
+        <target>欠落</target>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>`
+
+	summary, err := importXLIFF(strings.NewReader(xliff), xc, "skip", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.created, 3)
+	test.AssertEqual(t, summary.updated, 1)
+	test.AssertEqual(t, summary.unchanged, 1)
+	test.AssertEqual(t, summary.skipped, 1)
+
+	ja := xc.Strings["greeting"].Localizations["ja"]
+	test.AssertEqual(t, ja.StringUnit.Value, "ようこそ & こんにちは")
+	test.AssertEqual(t, ja.StringUnit.State, "translated")
+
+	plural := xc.Strings["item_count"].Localizations["ja"].Variations.Plural
+	test.AssertEqual(t, plural["one"].StringUnit.Value, "%lld 個")
+	test.AssertEqual(t, plural["one"].StringUnit.State, "needs_review")
+
+	sub := xc.Strings["files"].Localizations["ja"].Substitutions["files"]
+	test.AssertEqual(t, sub.ArgNum, 1)
+	test.AssertEqual(t, sub.Variations.Plural["other"].StringUnit.Value, "%arg 個のファイル")
+	test.AssertEqual(t, sub.Variations.Plural["other"].StringUnit.State, "new")
+}
+
+func TestImportXLIFF_StateChangeAloneIsAnUpdate(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	xliff := `<xliff version="1.2"><file original="x" source-language="en" target-language="ja" datatype="plaintext"><body>
+<trans-unit id="greeting"><source>Hello &amp; welcome</source><target state="final">こんにちは</target></trans-unit>
+</body></file></xliff>`
+
+	summary, err := importXLIFF(strings.NewReader(xliff), xc, "skip", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.updated, 1)
+	test.AssertEqual(t, xc.Strings["greeting"].Localizations["ja"].StringUnit.State, "translated")
+}
+
+func TestImportXLIFF_MissingTargetLanguage(t *testing.T) {
+	xc := &xcstrings.XCStrings{SourceLanguage: "en", Strings: map[string]xcstrings.StringDefinition{}}
+	_, err := importXLIFF(strings.NewReader(`<xliff version="1.2"><file original="x" source-language="en"><body/></file></xliff>`), xc, "skip", false)
+	test.AssertError(t, err)
+}
+
+func TestXLIFF_RoundTrip_NoEditsAreAllUnchanged(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	var buf bytes.Buffer
+	test.AssertNoError(t, writeXLIFF(&buf, xc, "test.xcstrings", ""))
+
+	summary, err := importXLIFF(&buf, xc, "error", false)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, summary.created, 0)
+	test.AssertEqual(t, summary.updated, 0)
+	test.AssertEqual(t, summary.skipped, 0)
+}
+
+func TestExportImportCommand_XLIFF(t *testing.T) {
+	xcPath := test.TempFile(t, "test.xcstrings", xliffFixture)
+
+	exportCmd := &ExportCommand{}
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	exportCmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", "xliff", "--lang", "ja"}))
+
+	output := captureOutput(func() {
+		status := exportCmd.Execute(context.Background(), flagSet)
+		test.AssertEqual(t, int(status), 0)
+	})
+	if !strings.Contains(output, `original="test.xcstrings"`) {
+		t.Fatalf("expected the catalog file name as original, got:\n%s", output)
+	}
+
+	edited := strings.Replace(output, `<target state="needs-review-l10n">こんにちは</target>`, `<target state="translated">やあ</target>`, 1)
+	xliffPath := test.TempFile(t, "ja.xliff", edited)
+
+	importCmd := &ImportCommand{}
+	flagSet = flag.NewFlagSet("test", flag.ContinueOnError)
+	importCmd.SetFlags(flagSet)
+	test.AssertNoError(t, flagSet.Parse([]string{"-f", xcPath, "--format", "xliff", xliffPath}))
+
+	output = captureOutput(func() {
+		status := importCmd.Execute(context.Background(), flagSet)
+		test.AssertEqual(t, int(status), 0)
+	})
+	if !strings.Contains(output, "1 updated") {
+		t.Errorf("expected 1 updated, got: %q", output)
+	}
+
+	xc, err := xcstrings.Load(xcPath)
+	test.AssertNoError(t, err)
+	unit := xc.Strings["greeting"].Localizations["ja"].StringUnit
+	test.AssertEqual(t, unit.Value, "やあ")
+	test.AssertEqual(t, unit.State, "translated")
+}
+
+func TestImportXLIFF_HostStringKeepsSubstitutions(t *testing.T) {
+	filePath := test.TempFile(t, "test.xcstrings", xliffFixture)
+	xc, err := xcstrings.Load(filePath)
+	test.AssertNoError(t, err)
+
+	xliff := `<xliff version="1.2"><file original="x" source-language="en" target-language="en-GB" datatype="plaintext"><body>
+<trans-unit id="files|==|substitutions.files.plural.one"><source>%arg file</source><target>%arg file</target></trans-unit>
+<trans-unit id="files"><source>%#@files@</source><target>%#@files@ here</target></trans-unit>
+</body></file></xliff>`
+
+	_, err = importXLIFF(strings.NewReader(xliff), xc, "skip", false)
+	test.AssertNoError(t, err)
+
+	loc := xc.Strings["files"].Localizations["en-GB"]
+	test.AssertEqual(t, loc.StringUnit.Value, "%#@files@ here")
+	if _, ok := loc.Substitutions["files"]; !ok {
+		t.Error("updating the host string dropped its substitutions")
+	}
+}
+
+func TestCatalogStateFromXLIFF(t *testing.T) {
+	for state, want := range map[string]string{
+		"":                         "translated",
+		"final":                    "translated",
+		"needs-translation":        "new",
+		"needs-review-l10n":        "needs_review",
+		"needs-review-translation": "needs_review",
+	} {
+		test.AssertEqual(t, catalogStateFromXLIFF(state), want)
+	}
+	test.AssertEqual(t, xliffStateFromCatalog("needs_review"), "needs-review-l10n")
+}
@@ -0,0 +1,227 @@
+// Package config loads the project configuration file (.xckit.yaml,
+// .xckit.yml or .xckit.json) that supplies defaults to every xckit command.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the configuration file names looked for in each directory,
+// in order of preference.
+var FileNames = []string{".xckit.yaml", ".xckit.yml", ".xckit.json"}
+
+// Config is a project configuration.
+type Config struct {
+	// Catalogs are the .xcstrings files or directories commands operate on
+	// when no -f is given, relative to the configuration file.
+	Catalogs []string `json:"catalogs"`
+	// Recursive searches the Catalogs directories recursively, like
+	// --recursive.
+	Recursive bool `json:"recursive"`
+	// Languages are the languages every catalog must be translated into. They
+	// replace the languages found in a catalog for progress and untranslated
+	// reports, so a language nobody has started on still shows up.
+	Languages []string `json:"languages"`
+	Lint      Lint     `json:"lint"`
+	// Commands holds flag defaults per command name, e.g.
+	// {"import": {"on-missing-key": "error"}}. Flags given on the command
+	// line take precedence.
+	Commands map[string]map[string]any `json:"commands"`
+
+	// Path is the file the configuration was loaded from.
+	Path string `json:"-"`
+}
+
+// Lint configures the lint rules.
+type Lint struct {
+	// Rules maps a rule name to "off", "error" or "warning".
+	Rules map[string]string `json:"rules"`
+	// Glossary is the glossary file (CSV or TBX) of the glossary rule,
+	// relative to the configuration file.
+	Glossary string `json:"glossary"`
+}
+
+// Find looks for a configuration file in dir and each of its parents, and
+// loads the first one found. It returns nil without an error when there is
+// none.
+func Find(dir string) (*Config, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var found []string
+		for _, name := range FileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				found = append(found, path)
+			}
+		}
+		if len(found) > 1 {
+			return nil, fmt.Errorf("both %s and %s exist; keep only one", found[0], found[1])
+		}
+		if len(found) == 1 {
+			return Load(found[0])
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads a configuration file, as YAML unless its extension is .json.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is converted to JSON so that both formats are decoded, and
+	// unknown fields rejected, the same way.
+	if filepath.Ext(path) != ".json" {
+		var value any
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if value == nil {
+			value = map[string]any{}
+		}
+		if data, err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Path = path
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	for rule, setting := range c.Lint.Rules {
+		if setting != "off" && setting != "error" && setting != "warning" {
+			return fmt.Errorf("lint.rules.%s must be off, error or warning, not %q", rule, setting)
+		}
+	}
+	for command, flags := range c.Commands {
+		for name, value := range flags {
+			if _, err := flagValues(value); err != nil {
+				return fmt.Errorf("commands.%s.%s: %w", command, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CatalogPaths returns Catalogs resolved against the configuration file's
+// directory, relative to the working directory when possible.
+func (c *Config) CatalogPaths() []string {
+	paths := make([]string, 0, len(c.Catalogs))
+	for _, p := range c.Catalogs {
+		paths = append(paths, c.resolvePath(p))
+	}
+	return paths
+}
+
+// GlossaryPath returns Lint.Glossary resolved like CatalogPaths, or "" when
+// no glossary is configured.
+func (c *Config) GlossaryPath() string {
+	if c.Lint.Glossary == "" {
+		return ""
+	}
+	return c.resolvePath(c.Lint.Glossary)
+}
+
+func (c *Config) resolvePath(p string) string {
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(filepath.Dir(c.Path), p)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if abs, err := filepath.Abs(p); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil {
+				p = rel
+			}
+		}
+	}
+	return p
+}
+
+// ApplyDefaults sets each of the command's configured flags that was not
+// given on the command line. A list sets a repeatable flag once per element.
+func (c *Config) ApplyDefaults(command string, f *flag.FlagSet) error {
+	defaults := c.Commands[command]
+	if len(defaults) == 0 {
+		return nil
+	}
+
+	given := make(map[string]bool)
+	f.Visit(func(fl *flag.Flag) { given[fl.Name] = true })
+
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if f.Lookup(name) == nil {
+			return fmt.Errorf("%s: commands.%s: %s has no flag --%s", c.Path, command, command, name)
+		}
+		if given[name] {
+			continue
+		}
+		values, _ := flagValues(defaults[name])
+		for _, v := range values {
+			if err := f.Set(name, v); err != nil {
+				return fmt.Errorf("%s: commands.%s.%s: %w", c.Path, command, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// flagValues renders a configured flag value as the strings to pass to
+// flag.Set.
+func flagValues(value any) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case bool:
+		return []string{strconv.FormatBool(v)}, nil
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case []any:
+		var values []string
+		for _, item := range v {
+			if _, ok := item.([]any); ok {
+				return nil, errors.New("nested lists are not supported")
+			}
+			itemValues, err := flagValues(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, itemValues...)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported value %v", value)
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	test.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	test.AssertNoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestFind_WalksUpward(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".xckit.yaml"), "languages: [ja, fr]\n")
+	sub := filepath.Join(root, "App", "Sources")
+	test.AssertNoError(t, os.MkdirAll(sub, 0755))
+
+	cfg, err := Find(sub)
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, cfg.Languages, []string{"ja", "fr"})
+	test.AssertEqual(t, cfg.Path, filepath.Join(root, ".xckit.yaml"))
+
+	// The nearest file wins.
+	writeFile(t, filepath.Join(root, "App", ".xckit.json"), `{"languages": ["de"]}`)
+	cfg, err = Find(sub)
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, cfg.Languages, []string{"de"})
+
+	// Two files in one directory are ambiguous.
+	writeFile(t, filepath.Join(root, "App", ".xckit.yml"), "languages: [es]\n")
+	_, err = Find(sub)
+	test.AssertError(t, err)
+}
+
+func TestFind_NoConfig(t *testing.T) {
+	cfg, err := Find(t.TempDir())
+	test.AssertNoError(t, err)
+	if cfg != nil {
+		// A configuration above the temporary directory would make this
+		// test meaningless rather than wrong.
+		t.Skipf("found %s above the temporary directory", cfg.Path)
+	}
+}
+
+func TestLoad_YAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".xckit.yaml")
+	writeFile(t, path, `# Project settings
+catalogs:
+  - App/Localizable.xcstrings   # the main table
+  - "Packages/Core # not a comment"
+languages: &languages [ja, "fr", 'zh-Hans']
+lint:
+  rules: {literal-newline: off}
+commands:
+  translate:
+    batch-size: 20
+    prefix: 'it''s'
+  prefill:
+    tm:
+    - a.xcstrings
+    - b.xcstrings
+`)
+	cfg, err := Load(path)
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, cfg.Catalogs, []string{"App/Localizable.xcstrings", "Packages/Core # not a comment"})
+	test.AssertSliceEqual(t, cfg.Languages, []string{"ja", "fr", "zh-Hans"})
+	test.AssertEqual(t, cfg.Lint.Rules["literal-newline"], "off")
+	test.AssertEqual(t, cfg.Commands["translate"]["batch-size"], float64(20))
+	test.AssertEqual(t, cfg.Commands["translate"]["prefix"], "it's")
+
+	writeFile(t, path, "# only a comment\n")
+	cfg, err = Load(path)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, len(cfg.Languages), 0)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"unknown.json":  `{"langauges": ["ja"]}`,
+		"severity.yaml": "lint:\n  rules:\n    empty-key: fatal\n",
+		"type.yaml":     "languages: ja\n",
+		"value.yaml":    "commands:\n  lint:\n    json: {}\n",
+		"syntax.yaml":   "a: 1\n  b: 2\n",
+	} {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, content)
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("%s: expected an error naming the file, got %v", name, err)
+		}
+	}
+}
+
+func TestCatalogPaths(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	test.AssertNoError(t, err)
+	writeFile(t, filepath.Join(root, ".xckit.yaml"), "catalogs: [App/Localizable.xcstrings, Shared]\n")
+	cfg, err := Load(filepath.Join(root, ".xckit.yaml"))
+	test.AssertNoError(t, err)
+
+	// Paths are relative to the configuration file, not the working directory.
+	sub := filepath.Join(root, "App")
+	test.AssertNoError(t, os.MkdirAll(sub, 0755))
+	t.Chdir(sub)
+	test.AssertSliceEqual(t, cfg.CatalogPaths(), []string{"Localizable.xcstrings", filepath.Join("..", "Shared")})
+}
+
+func TestGlossaryPath(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	test.AssertNoError(t, err)
+	writeFile(t, filepath.Join(root, ".xckit.yaml"), "lint:\n  glossary: Docs/glossary.csv\n")
+	cfg, err := Load(filepath.Join(root, ".xckit.yaml"))
+	test.AssertNoError(t, err)
+
+	sub := filepath.Join(root, "App")
+	test.AssertNoError(t, os.MkdirAll(sub, 0755))
+	t.Chdir(sub)
+	test.AssertEqual(t, cfg.GlossaryPath(), filepath.Join("..", "Docs", "glossary.csv"))
+	test.AssertEqual(t, (&Config{}).GlossaryPath(), "")
+}
+
+func TestApplyDefaults(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".xckit.yaml"), `commands:
+  prefill:
+    min-score: 0.9
+    dry-run: true
+    tm: [a.xcstrings, b.xcstrings]
+    lang: ja
+`)
+	cfg, err := Load(filepath.Join(root, ".xckit.yaml"))
+	test.AssertNoError(t, err)
+
+	f := flag.NewFlagSet("prefill", flag.ContinueOnError)
+	minScore := f.Float64("min-score", 0.8, "")
+	dryRun := f.Bool("dry-run", false, "")
+	lang := f.String("lang", "", "")
+	var tm []string
+	f.Func("tm", "", func(s string) error { tm = append(tm, s); return nil })
+	test.AssertNoError(t, f.Parse([]string{"--lang", "fr"}))
+
+	test.AssertNoError(t, cfg.ApplyDefaults("prefill", f))
+	test.AssertEqual(t, *minScore, 0.9)
+	test.AssertEqual(t, *dryRun, true)
+	test.AssertEqual(t, *lang, "fr") // given on the command line
+	test.AssertSliceEqual(t, tm, []string{"a.xcstrings", "b.xcstrings"})
+
+	// Other commands are unaffected, and unknown flags are reported.
+	test.AssertNoError(t, cfg.ApplyDefaults("lint", flag.NewFlagSet("lint", flag.ContinueOnError)))
+	err = cfg.ApplyDefaults("prefill", flag.NewFlagSet("prefill", flag.ContinueOnError))
+	if err == nil || !strings.Contains(err.Error(), "prefill has no flag --dry-run") {
+		t.Errorf("expected an unknown flag error, got %v", err)
+	}
+}
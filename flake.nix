@@ -26,7 +26,7 @@
             pname = "xckit";
             inherit version;
             src = pkgs.lib.cleanSource self;
-            vendorHash = "sha256-vU5ZB9c4xqvWEk7NZkchaUlGS0mzDi5YHbe/Apq+FaU=";
+            vendorHash = "sha256-DAfjko3qNj4poLXWALBBgsVoR9XNS4f4j7NYXrPXbc4=";
             # Keep in sync with .goreleaser.yml / Makefile LDFLAGS.
             ldflags = [ "-s" "-w" "-X" "xckit/command.Version=${version}" ];
             meta.mainProgram = "xckit";
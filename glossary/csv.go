@@ -0,0 +1,61 @@
+package glossary
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// forbiddenSuffix marks a CSV column of forbidden renderings.
+const forbiddenSuffix = ":forbidden"
+
+// ParseCSV reads a CSV glossary. The header names a language per column,
+// the source language included, or "<language>:forbidden" for a column of
+// forbidden renderings; each row is a concept. A cell may hold several
+// renderings separated by ";".
+//
+//	en,ja,ja:forbidden,de
+//	Workspace;Workspaces,ワークスペース,作業スペース,Arbeitsbereich
+func ParseCSV(r io.Reader) (*Glossary, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty glossary")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(header) > 0 {
+		// Spreadsheet applications save UTF-8 with a byte order mark.
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	g := &Glossary{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return g, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry := Entry{Terms: make(map[string][]string), Forbidden: make(map[string][]string)}
+		for i, cell := range record {
+			if i >= len(header) {
+				break
+			}
+			column, target := header[i], entry.Terms
+			if strings.HasSuffix(column, forbiddenSuffix) {
+				column, target = strings.TrimSuffix(column, forbiddenSuffix), entry.Forbidden
+			}
+			for _, rendering := range strings.Split(cell, ";") {
+				add(target, column, rendering)
+			}
+		}
+		if len(entry.Terms)+len(entry.Forbidden) > 0 {
+			g.Entries = append(g.Entries, entry)
+		}
+	}
+}
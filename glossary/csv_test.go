@@ -0,0 +1,29 @@
+package glossary
+
+import (
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func TestParseCSV(t *testing.T) {
+	g, err := ParseCSV(strings.NewReader("\ufeffen,ja,ja:forbidden,pt_BR\n" +
+		"Workspace; Workspaces,ワークスペース,作業スペース;ワークエリア,Espaço de trabalho\n" +
+		",,,\n" +
+		"Reminder,リマインダー\n"))
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, len(g.Entries), 2)
+
+	workspace := g.Entries[0]
+	test.AssertSliceEqual(t, workspace.Terms["en"], []string{"Workspace", "Workspaces"})
+	test.AssertSliceEqual(t, workspace.Terms["ja"], []string{"ワークスペース"})
+	test.AssertSliceEqual(t, workspace.Forbidden["ja"], []string{"作業スペース", "ワークエリア"})
+	test.AssertSliceEqual(t, workspace.Terms["pt-br"], []string{"Espaço de trabalho"})
+	test.AssertSliceEqual(t, g.Entries[1].Terms["ja"], []string{"リマインダー"})
+
+	_, err = ParseCSV(strings.NewReader(""))
+	test.AssertError(t, err)
+	_, err = ParseCSV(strings.NewReader("en,ja\n\"Workspace,ワークスペース\n"))
+	test.AssertError(t, err)
+}
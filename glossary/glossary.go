@@ -0,0 +1,172 @@
+// Package glossary reads terminology glossaries (CSV or TBX) and checks
+// translations against them: a translation whose source uses a glossary
+// term must use an approved rendering of it, and none of the forbidden ones.
+package glossary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Entry is one concept of a glossary, with its renderings per language.
+// Languages are catalog language codes such as "ja" or "pt-BR".
+type Entry struct {
+	// Terms are the approved renderings per language, the source term
+	// included.
+	Terms map[string][]string
+	// Forbidden are renderings per language that must not be used.
+	Forbidden map[string][]string
+}
+
+// Glossary is a list of concepts.
+type Glossary struct {
+	Entries []Entry
+}
+
+// Violation is a translation's departure from a glossary entry.
+type Violation struct {
+	// Term is the source term the source text uses.
+	Term string
+	// Approved are the renderings the translation should use.
+	Approved []string
+	// Forbidden is the forbidden rendering the translation uses, or empty
+	// when it uses none of the approved ones.
+	Forbidden string
+}
+
+// Load reads a glossary file: TBX for .tbx and .xml files, CSV otherwise.
+func Load(path string) (*Glossary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var g *Glossary
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tbx", ".xml":
+		g, err = ParseTBX(f)
+	default:
+		g, err = ParseCSV(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return g, nil
+}
+
+// Check returns the violations of a translation into language of source,
+// a string in sourceLanguage. A source term matches whole words, ignoring
+// case; a rendering matches anywhere in the translation, ignoring case, so
+// that inflected forms ("Arbeitsbereiche") count.
+func (g *Glossary) Check(sourceLanguage, source, language, target string) []Violation {
+	var violations []Violation
+	lowerSource := strings.ToLower(source)
+	lowerTarget := strings.ToLower(target)
+	for _, entry := range g.Entries {
+		term := ""
+		for _, t := range lookup(entry.Terms, sourceLanguage) {
+			if containsWord(lowerSource, strings.ToLower(t)) {
+				term = t
+				break
+			}
+		}
+		if term == "" {
+			continue
+		}
+
+		approved := lookup(entry.Terms, language)
+		// A forbidden rendering inside an approved one ("Erinnerung" in
+		// "Terminerinnerung") is no violation, so approved ones are masked.
+		masked := lowerTarget
+		used := false
+		for _, a := range approved {
+			if a = strings.ToLower(a); strings.Contains(masked, a) {
+				used = true
+				masked = strings.ReplaceAll(masked, a, "\x00")
+			}
+		}
+		if !used && len(approved) > 0 {
+			violations = append(violations, Violation{Term: term, Approved: approved})
+		}
+		for _, f := range lookup(entry.Forbidden, language) {
+			if strings.Contains(masked, strings.ToLower(f)) {
+				violations = append(violations, Violation{Term: term, Approved: approved, Forbidden: f})
+			}
+		}
+	}
+	return violations
+}
+
+// lookup returns the renderings for language, or for its base language
+// ("pt" for "pt-BR") when it has none of its own.
+func lookup(renderings map[string][]string, language string) []string {
+	language = normalizeLanguage(language)
+	if r, ok := renderings[language]; ok {
+		return r
+	}
+	if i := strings.IndexByte(language, '-'); i >= 0 {
+		return renderings[language[:i]]
+	}
+	return nil
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+}
+
+// add appends a rendering for language to m.
+func add(m map[string][]string, language, rendering string) {
+	language = normalizeLanguage(language)
+	if rendering = strings.TrimSpace(rendering); rendering != "" && language != "" {
+		m[language] = append(m[language], rendering)
+	}
+}
+
+// containsWord reports whether term occurs in s as a whole word. Scripts
+// written without spaces between words (Chinese, Japanese, Thai, ...) have
+// no word boundaries to check.
+func containsWord(s, term string) bool {
+	if term == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(s[offset:], term)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(term)
+		first, last := []rune(term)[0], []rune(term)[len([]rune(term))-1]
+		before, after := lastRune(s[:start]), firstRune(s[end:])
+		if (!isWordRune(before) || !spaced(first)) && (!isWordRune(after) || !spaced(last)) {
+			return true
+		}
+		offset = start + len(string(first))
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func spaced(r rune) bool {
+	return !unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
+
+func firstRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+	return -1
+}
+
+func lastRune(s string) rune {
+	r := []rune(s)
+	if len(r) == 0 {
+		return -1
+	}
+	return r[len(r)-1]
+}
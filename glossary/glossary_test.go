@@ -0,0 +1,85 @@
+package glossary
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func testGlossary() *Glossary {
+	return &Glossary{Entries: []Entry{
+		{
+			Terms:     map[string][]string{"en": {"Workspace", "Workspaces"}, "ja": {"ワークスペース"}, "de": {"Arbeitsbereich"}},
+			Forbidden: map[string][]string{"ja": {"作業スペース"}, "de": {"Workspace"}},
+		},
+		{
+			Terms:     map[string][]string{"en": {"Reminder"}, "de": {"Erinnerung"}},
+			Forbidden: map[string][]string{"de": {"Mahnung"}},
+		},
+		{
+			Terms: map[string][]string{"ja": {"設定"}, "en": {"Settings"}},
+		},
+	}}
+}
+
+func TestCheck(t *testing.T) {
+	g := testGlossary()
+	tests := []struct {
+		name     string
+		source   string
+		language string
+		target   string
+		want     []Violation
+	}{
+		{"approved", "Open workspace", "ja", "ワークスペースを開く", nil},
+		{"missing", "Open Workspace", "ja", "ワークエリアを開く", []Violation{{Term: "Workspace", Approved: []string{"ワークスペース"}}}},
+		{"forbidden", "Open Workspace", "ja", "作業スペースを開く", []Violation{
+			{Term: "Workspace", Approved: []string{"ワークスペース"}},
+			{Term: "Workspace", Approved: []string{"ワークスペース"}, Forbidden: "作業スペース"},
+		}},
+		{"inflected rendering", "%lld Workspaces", "de", "%lld Arbeitsbereiche", nil},
+		{"regional variant", "Workspace", "de-AT", "Arbeitsbereich", nil},
+		{"forbidden within approved", "New Reminder", "de", "Neue Terminerinnerung", nil},
+		{"forbidden only", "Reminder", "de", "Mahnung", []Violation{
+			{Term: "Reminder", Approved: []string{"Erinnerung"}},
+			{Term: "Reminder", Approved: []string{"Erinnerung"}, Forbidden: "Mahnung"},
+		}},
+		{"whole words only", "Reminders", "de", "Hinweise", nil},
+		{"no rendering for the language", "Workspace", "fr", "Espace de travail", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.Check("en", tt.source, tt.language, tt.target)
+			test.AssertEqual(t, len(got), len(tt.want))
+			for i := range tt.want {
+				test.AssertEqual(t, got[i].Term, tt.want[i].Term)
+				test.AssertSliceEqual(t, got[i].Approved, tt.want[i].Approved)
+				test.AssertEqual(t, got[i].Forbidden, tt.want[i].Forbidden)
+			}
+		})
+	}
+
+	// Source terms in scripts without spaces match inside words.
+	got := g.Check("ja", "表示設定を開く", "en", "Open display preferences")
+	test.AssertEqual(t, len(got), 1)
+	test.AssertEqual(t, got[0].Term, "設定")
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "glossary.csv")
+	test.AssertNoError(t, os.WriteFile(csvPath, []byte("en,ja\nWorkspace,ワークスペース\n"), 0644))
+	g, err := Load(csvPath)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, len(g.Entries), 1)
+
+	tbxPath := filepath.Join(dir, "glossary.tbx")
+	test.AssertNoError(t, os.WriteFile(tbxPath, []byte("en,ja\nWorkspace,ワークスペース\n"), 0644))
+	_, err = Load(tbxPath)
+	test.AssertError(t, err)
+
+	_, err = Load(filepath.Join(dir, "missing.csv"))
+	test.AssertError(t, err)
+}
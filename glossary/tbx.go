@@ -0,0 +1,105 @@
+package glossary
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ParseTBX reads a TermBase eXchange glossary, TBX 2 (<martif>, <termEntry>,
+// <langSet>, <tig> or <ntig>) or TBX 3 (<tbx>, <conceptEntry>, <langSec>,
+// <termSec>). Each entry is a concept. A term whose administrative status is
+// deprecated or superseded is forbidden; any other term is approved.
+func ParseTBX(r io.Reader) (*Glossary, error) {
+	d := xml.NewDecoder(r)
+	g := &Glossary{}
+	var (
+		entry    *Entry
+		language string
+		// term and status belong to the term being read; inGroup is set
+		// inside a <tig>, <ntig> or <termSec>, which closes the term.
+		term, status string
+		inGroup      bool
+		text         *strings.Builder
+		sawRoot      bool
+	)
+	commit := func() {
+		if entry != nil && term != "" {
+			if strings.Contains(status, "deprecated") || strings.Contains(status, "superseded") {
+				add(entry.Forbidden, language, term)
+			} else {
+				add(entry.Terms, language, term)
+			}
+		}
+		term, status = "", ""
+	}
+
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "martif", "tbx":
+				sawRoot = true
+			case "termEntry", "conceptEntry":
+				entry = &Entry{Terms: make(map[string][]string), Forbidden: make(map[string][]string)}
+			case "langSet", "langSec":
+				language = attrValue(t, "lang")
+			case "tig", "ntig", "termSec":
+				inGroup = true
+			case "term":
+				text = &strings.Builder{}
+			case "termNote", "administrativeStatus":
+				if t.Name.Local == "administrativeStatus" || attrValue(t, "type") == "administrativeStatus" {
+					text = &strings.Builder{}
+				}
+			}
+		case xml.CharData:
+			if text != nil {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "term":
+				term = strings.TrimSpace(text.String())
+				text = nil
+				if !inGroup {
+					commit()
+				}
+			case "termNote", "administrativeStatus":
+				if text != nil {
+					status = strings.TrimSpace(text.String())
+					text = nil
+				}
+			case "tig", "ntig", "termSec":
+				commit()
+				inGroup = false
+			case "termEntry", "conceptEntry":
+				if entry != nil && len(entry.Terms)+len(entry.Forbidden) > 0 {
+					g.Entries = append(g.Entries, *entry)
+				}
+				entry = nil
+			}
+		}
+	}
+	if !sawRoot {
+		return nil, errors.New("not a TBX document")
+	}
+	return g, nil
+}
+
+func attrValue(e xml.StartElement, name string) string {
+	for _, attr := range e.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package glossary
+
+import (
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func TestParseTBX_Version2(t *testing.T) {
+	g, err := ParseTBX(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<martif type="TBX-Basic" xml:lang="en">
+  <text><body>
+    <termEntry id="workspace">
+      <descrip type="definition">A shared area for a team's documents</descrip>
+      <langSet xml:lang="en"><tig><term>Workspace</term></tig></langSet>
+      <langSet xml:lang="ja">
+        <tig>
+          <term>ワークスペース</term>
+          <termNote type="administrativeStatus">preferredTerm-admn-sts</termNote>
+        </tig>
+        <ntig>
+          <termGrp><term>作業スペース</term></termGrp>
+          <termNote type="administrativeStatus">deprecatedTerm-admn-sts</termNote>
+        </ntig>
+      </langSet>
+    </termEntry>
+  </body></text>
+</martif>`))
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, len(g.Entries), 1)
+	test.AssertSliceEqual(t, g.Entries[0].Terms["en"], []string{"Workspace"})
+	test.AssertSliceEqual(t, g.Entries[0].Terms["ja"], []string{"ワークスペース"})
+	test.AssertSliceEqual(t, g.Entries[0].Forbidden["ja"], []string{"作業スペース"})
+}
+
+func TestParseTBX_Version3(t *testing.T) {
+	g, err := ParseTBX(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<tbx type="TBX-Basic" style="dca" xml:lang="en" xmlns="urn:iso:std:iso:30042:ed-2">
+  <tbxHeader><fileDesc><sourceDesc><p>Product terms</p></sourceDesc></fileDesc></tbxHeader>
+  <text><body>
+    <conceptEntry id="reminder">
+      <langSec xml:lang="en"><termSec><term>Reminder</term></termSec></langSec>
+      <langSec xml:lang="de">
+        <termSec><term>Erinnerung</term><termNote type="partOfSpeech">noun</termNote></termSec>
+        <termSec><term>Mahnung</term><termNote type="administrativeStatus">supersededTerm-admn-sts</termNote></termSec>
+      </langSec>
+    </conceptEntry>
+  </body></text>
+</tbx>`))
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, len(g.Entries), 1)
+	test.AssertSliceEqual(t, g.Entries[0].Terms["de"], []string{"Erinnerung"})
+	test.AssertSliceEqual(t, g.Entries[0].Forbidden["de"], []string{"Mahnung"})
+
+	_, err = ParseTBX(strings.NewReader(`<glossary><term>Workspace</term></glossary>`))
+	test.AssertError(t, err)
+	_, err = ParseTBX(strings.NewReader(`<martif><text>`))
+	test.AssertError(t, err)
+}
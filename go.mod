@@ -2,4 +2,7 @@ module xckit
 
 go 1.24.2
 
-require github.com/google/subcommands v1.2.0
+require (
+	github.com/google/subcommands v1.2.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -11,15 +11,29 @@ import (
 )
 
 func main() {
-	subcommands.Register(&command.ExportCommand{}, "")
-	subcommands.Register(&command.ImportCommand{}, "")
-	subcommands.Register(&command.UntranslatedCommand{}, "")
-	subcommands.Register(&command.ListCommand{}, "")
-	subcommands.Register(&command.SetCommand{}, "")
-	subcommands.Register(&command.RemoveCommand{}, "")
-	subcommands.Register(&command.StaleCommand{}, "")
-	subcommands.Register(&command.StatusCommand{}, "")
-	subcommands.Register(&command.LintCommand{}, "")
+	subcommands.Register(command.Configured(&command.ExportCommand{}), "")
+	subcommands.Register(command.Configured(&command.ImportCommand{}), "")
+	subcommands.Register(command.Configured(&command.UntranslatedCommand{}), "")
+	subcommands.Register(command.Configured(&command.ListCommand{}), "")
+	subcommands.Register(command.Configured(&command.SetCommand{}), "")
+	subcommands.Register(command.Configured(&command.RemoveCommand{}), "")
+	subcommands.Register(command.Configured(&command.RenameCommand{}), "")
+	subcommands.Register(subcommands.Alias("mv", command.Configured(&command.RenameCommand{})), "")
+	subcommands.Register(command.Configured(&command.CopyCommand{}), "")
+	subcommands.Register(command.Configured(&command.SplitCommand{}), "")
+	subcommands.Register(command.Configured(&command.MergeCommand{}), "")
+	subcommands.Register(command.Configured(&command.ScanCommand{}), "")
+	subcommands.Register(command.Configured(&command.ResolveCommand{}), "")
+	subcommands.Register(command.Configured(&command.StaleCommand{}), "")
+	subcommands.Register(command.Configured(&command.StatusCommand{}), "")
+	subcommands.Register(command.Configured(&command.LintCommand{}), "")
+	subcommands.Register(command.Configured(&command.TranslateCommand{}), "")
+	subcommands.Register(command.Configured(&command.PrefillCommand{}), "")
+	subcommands.Register(command.Configured(&command.ScaffoldCommand{}), "")
+	subcommands.Register(command.Configured(&command.PseudoCommand{}), "")
+	subcommands.Register(command.Configured(&command.CodegenCommand{}), "")
+	subcommands.Register(command.Configured(&command.DiffCommand{}), "")
+	subcommands.Register(&command.MergeDriverCommand{}, "")
 	subcommands.Register(&command.VersionCommand{}, "")
 	subcommands.Register(subcommands.HelpCommand(), "")
 	subcommands.Register(subcommands.CommandsCommand(), "")
@@ -0,0 +1,105 @@
+package resolve
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// specRe matches a printf-style conversion as Foundation's String(format:)
+// parses it, capturing its position, flags, width, precision and
+// conversion character, or a %% escape.
+var specRe = regexp.MustCompile(`%%|%(?:(\d+)\$)?([-+ 0#']*)(\d*)(\.\d*)?(?:hh|h|ll|l|q|L|z|j|t)?([@dioxXucsfFeEgGaAp])`)
+
+// Format formats format with args, given as text, the way String(format:)
+// does with the corresponding values: an integer conversion takes an
+// integer, a floating-point conversion a number, and %@ any text.
+// Conversions without an explicit position (%1$@) take the arguments in
+// order. Numbers are formatted without locale-specific separators.
+func Format(format string, args []string) (string, error) {
+	var b strings.Builder
+	next := 1
+	last := 0
+	for _, m := range specRe.FindAllStringSubmatchIndex(format, -1) {
+		b.WriteString(format[last:m[0]])
+		last = m[1]
+		if format[m[0]:m[1]] == "%%" {
+			b.WriteByte('%')
+			continue
+		}
+
+		position := next
+		if m[2] >= 0 {
+			position, _ = strconv.Atoi(format[m[2]:m[3]])
+		} else {
+			next++
+		}
+		if position < 1 || position > len(args) {
+			return "", fmt.Errorf("%s needs a value for argument %d", format[m[0]:m[1]], position)
+		}
+		flags := strings.ReplaceAll(format[m[4]:m[5]], "'", "")
+		precision := ""
+		if m[8] >= 0 {
+			precision = format[m[8]:m[9]]
+		}
+		text, err := formatValue(flags+format[m[6]:m[7]], precision, format[m[10]:m[11]], args[position-1])
+		if err != nil {
+			return "", fmt.Errorf("argument %d: %w", position, err)
+		}
+		b.WriteString(text)
+	}
+	b.WriteString(format[last:])
+	return b.String(), nil
+}
+
+// formatValue formats value for one conversion, given its flags and width,
+// its precision (".2") and its conversion character.
+func formatValue(flagsWidth, precision, conversion, value string) (string, error) {
+	switch conversion {
+	case "@", "s":
+		return fmt.Sprintf("%"+flagsWidth+precision+"s", value), nil
+	case "c":
+		r := []rune(value)
+		if len(r) != 1 {
+			return "", fmt.Errorf("%q is not a single character", value)
+		}
+		return fmt.Sprintf("%"+flagsWidth+"c", r[0]), nil
+	case "d", "i":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an integer", value)
+		}
+		return fmt.Sprintf("%"+flagsWidth+precision+"d", n), nil
+	case "u", "o", "x", "X", "p":
+		n, err := strconv.ParseInt(value, 0, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an integer", value)
+		}
+		switch conversion {
+		case "u":
+			return fmt.Sprintf("%"+flagsWidth+precision+"d", uint64(n)), nil
+		case "p":
+			return fmt.Sprintf("0x%x", uint64(n)), nil
+		}
+		return fmt.Sprintf("%"+flagsWidth+precision+conversion, uint64(n)), nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a number", value)
+	}
+	verb := conversion
+	switch conversion {
+	case "g", "G":
+		// C's %g defaults to 6 significant digits; Go's to as many as needed.
+		if precision == "" {
+			precision = ".6"
+		}
+	case "a":
+		verb = "x"
+	case "A":
+		verb = "X"
+	}
+	return fmt.Sprintf("%"+flagsWidth+precision+verb, f), nil
+}
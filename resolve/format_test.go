@@ -0,0 +1,35 @@
+package resolve
+
+import (
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		args   []string
+		want   string
+	}{
+		{"%@ has %lld items", []string{"Alice", "3"}, "Alice has 3 items"},
+		{"%2$@ before %1$@", []string{"one", "two"}, "two before one"},
+		{"%.1f%% done", []string{"42.25"}, "42.2% done"},
+		{"%05d|%-4d|%+d", []string{"42", "7", "3"}, "00042|7   |+3"},
+		{"%x %X %o %u", []string{"255", "255", "8", "-1"}, "ff FF 10 18446744073709551615"},
+		{"%g %g %e", []string{"0.1", "1234567", "1.5"}, "0.1 1.23457e+06 1.500000e+00"},
+		{"%c%s", []string{"A", "bc"}, "Abc"},
+		{"%'d", []string{"1000"}, "1000"},
+		{"no arguments", nil, "no arguments"},
+	}
+	for _, tt := range tests {
+		got, err := Format(tt.format, tt.args)
+		test.AssertNoError(t, err)
+		test.AssertEqual(t, got, tt.want)
+	}
+
+	_, err := Format("%d items", []string{"three"})
+	test.AssertError(t, err)
+	_, err = Format("%@ and %@", []string{"one"})
+	test.AssertError(t, err)
+}
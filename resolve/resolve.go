@@ -0,0 +1,281 @@
+// Package resolve renders a catalog string the way Foundation does at
+// runtime: it picks the device and CLDR plural variations for the given
+// argument values, expands %#@name@ substitutions, and formats the result.
+package resolve
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"xckit/xcstrings"
+)
+
+// Options describes the device and argument values to resolve a key for.
+type Options struct {
+	// Language is the language to resolve in. A key without a localization
+	// in it falls back to the source language, as the app would.
+	Language string
+	// Device is the device variation to use (iphone, ipad, mac, ...). Other
+	// devices, and an empty Device, use the "other" variation.
+	Device string
+	// Args are the argument values, as text, by position.
+	Args []string
+}
+
+// Result is a resolved string.
+type Result struct {
+	Text string
+	// Language is the localization the text comes from.
+	Language string
+}
+
+var (
+	subRefRe = regexp.MustCompile(`%(?:\d+\$)?#@(\w+)@`)
+	argRe    = regexp.MustCompile(`%(?:\d+\$)?arg\b`)
+)
+
+// Resolve renders key for opts.
+func Resolve(x *xcstrings.XCStrings, key string, opts Options) (Result, error) {
+	loc, language, err := localization(x, key, opts.Language)
+	if err != nil {
+		return Result{}, err
+	}
+	r := resolver{language: language, device: opts.Device, args: opts.Args}
+
+	host, err := r.pick(loc.StringUnit, loc.Variations, "", 0)
+	if err != nil {
+		return Result{}, err
+	}
+	// Foundation replaces a substitution reference with the selected form,
+	// in which %arg stands for the substitution's own argument.
+	var subErr error
+	host = subRefRe.ReplaceAllStringFunc(host, func(ref string) string {
+		name := subRefRe.FindStringSubmatch(ref)[1]
+		sub, ok := loc.Substitutions[name]
+		if !ok {
+			subErr = fmt.Errorf("substitution %s is not defined", name)
+			return ref
+		}
+		form, err := r.pick(nil, &sub.Variations, name, sub.ArgNum)
+		if err != nil {
+			subErr = err
+			return ref
+		}
+		return argRe.ReplaceAllLiteralString(form, "%"+strconv.Itoa(sub.ArgNum)+"$"+sub.FormatSpecifier)
+	})
+	if subErr != nil {
+		return Result{}, subErr
+	}
+
+	text, err := Format(host, opts.Args)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Text: text, Language: language}, nil
+}
+
+// PluralArguments returns the positions of the arguments that select a
+// plural variation of key in language, sorted, along with the language
+// whose localization they come from: language, or the source language it
+// falls back to.
+func PluralArguments(x *xcstrings.XCStrings, key, language string) ([]int, string, error) {
+	loc, language, err := localization(x, key, language)
+	if err != nil {
+		return nil, "", err
+	}
+	seen := make(map[int]bool)
+	if hasPlural(loc.Variations) {
+		seen[pluralArgument(loc.Variations)] = true
+	}
+	for _, sub := range loc.Substitutions {
+		if hasPlural(&sub.Variations) {
+			seen[sub.ArgNum] = true
+		}
+	}
+	positions := make([]int, 0, len(seen))
+	for position := range seen {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+	return positions, language, nil
+}
+
+// SampleCounts returns counts that show every plural category of language:
+// the first two integers selecting each one, and a million.
+func SampleCounts(language string) []int64 {
+	examples := make(map[xcstrings.PluralCategory]int)
+	var counts []int64
+	for n := int64(0); n <= 1000; n++ {
+		category := xcstrings.PluralCategoryOf(language, n)
+		if examples[category] < 2 {
+			examples[category]++
+			counts = append(counts, n)
+		}
+	}
+	return append(counts, 1000000)
+}
+
+// localization returns key's localization for language, or the source
+// language's when it has none.
+func localization(x *xcstrings.XCStrings, key, language string) (xcstrings.Localization, string, error) {
+	definition, ok := x.Strings[key]
+	if !ok {
+		return xcstrings.Localization{}, "", fmt.Errorf("key not found: %s", key)
+	}
+	if loc, ok := definition.Localizations[language]; ok && len(loc.AllStringUnits()) > 0 {
+		return loc, language, nil
+	}
+	if loc, ok := definition.Localizations[x.SourceLanguage]; ok && len(loc.AllStringUnits()) > 0 {
+		return loc, x.SourceLanguage, nil
+	}
+	// A key extracted from code without a value is its own source text.
+	return xcstrings.Localization{StringUnit: &xcstrings.StringUnit{Value: key}}, x.SourceLanguage, nil
+}
+
+type resolver struct {
+	language string
+	device   string
+	args     []string
+}
+
+// pick returns the string a unit or variations resolve to. where names the
+// substitution being resolved, if any, for errors, and position is the
+// argument its plural variations vary by (0 outside a substitution).
+func (r *resolver) pick(unit *xcstrings.StringUnit, variations *xcstrings.Variations, where string, position int) (string, error) {
+	for unit == nil {
+		switch {
+		case variations == nil:
+			return "", fmt.Errorf("%s has no string", r.label(where))
+		case variations.Device != nil:
+			value := variations.Device[r.device]
+			if value == nil {
+				value = variations.Device["other"]
+			}
+			if value == nil {
+				return "", fmt.Errorf("%s has no %s or other device variation", r.label(where), r.deviceName())
+			}
+			unit, variations = value.StringUnit, value.Variations
+		case variations.Plural != nil:
+			value, err := r.plural(variations, where, position)
+			if err != nil {
+				return "", err
+			}
+			unit, variations = value.StringUnit, value.Variations
+		default:
+			return "", fmt.Errorf("%s has no string", r.label(where))
+		}
+	}
+	return unit.Value, nil
+}
+
+// plural returns the plural form selected by the argument at position, or
+// when position is 0, by the first number the forms format.
+func (r *resolver) plural(variations *xcstrings.Variations, where string, position int) (*xcstrings.VariationValue, error) {
+	if position == 0 {
+		position = pluralArgument(variations)
+	}
+	category, n, err := r.category(position, where)
+	if err != nil {
+		return nil, err
+	}
+	// Apple platforms honor an explicit zero case in every language.
+	if value := variations.Plural["zero"]; n == 0 && value != nil {
+		return value, nil
+	}
+	if value := variations.Plural[category]; value != nil {
+		return value, nil
+	}
+	if value := variations.Plural["other"]; value != nil {
+		return value, nil
+	}
+	return nil, fmt.Errorf("%s has no %s or other plural variation", r.label(where), category)
+}
+
+// category returns the plural category of the argument at position, along
+// with its value. A fractional value selects "other".
+func (r *resolver) category(position int, where string) (xcstrings.PluralCategory, float64, error) {
+	if position < 1 || position > len(r.args) {
+		return "", 0, fmt.Errorf("%s needs a value for argument %d", r.label(where), position)
+	}
+	n, err := strconv.ParseFloat(r.args[position-1], 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("argument %d: %q is not a number", position, r.args[position-1])
+	}
+	if n != math.Trunc(n) || math.IsInf(n, 0) {
+		return "other", n, nil
+	}
+	return xcstrings.PluralCategoryOf(r.language, int64(n)), n, nil
+}
+
+func (r *resolver) label(where string) string {
+	if where == "" {
+		return r.language + " localization"
+	}
+	return "substitution " + where
+}
+
+func (r *resolver) deviceName() string {
+	if r.device == "" {
+		return "default"
+	}
+	return r.device
+}
+
+func hasPlural(v *xcstrings.Variations) bool {
+	if v == nil {
+		return false
+	}
+	if v.Plural != nil {
+		return true
+	}
+	for _, value := range v.Device {
+		if value != nil && hasPlural(value.Variations) {
+			return true
+		}
+	}
+	return false
+}
+
+// pluralArgument returns the position of the argument a plural variation
+// outside a substitution varies by: the first number its forms format.
+func pluralArgument(v *xcstrings.Variations) int {
+	for v != nil && v.Plural == nil {
+		var next *xcstrings.Variations
+		for _, value := range v.Device {
+			if value != nil && hasPlural(value.Variations) {
+				next = value.Variations
+				break
+			}
+		}
+		v = next
+	}
+	if v == nil {
+		return 1
+	}
+	for _, category := range xcstrings.ValidPluralCategories {
+		value := v.Plural[category]
+		if value == nil || value.StringUnit == nil {
+			continue
+		}
+		next := 1
+		for _, m := range specRe.FindAllStringSubmatch(value.StringUnit.Value, -1) {
+			if m[0] == "%%" {
+				continue
+			}
+			position := next
+			if m[1] != "" {
+				position, _ = strconv.Atoi(m[1])
+			} else {
+				next++
+			}
+			if strings.Contains("diouxXcfFeEgGaA", m[5]) {
+				return position
+			}
+		}
+	}
+	return 1
+}
@@ -0,0 +1,116 @@
+package resolve
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+const resolveFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"%lld files": {"localizations": {
+			"en": {"variations": {"plural": {
+				"one": {"stringUnit": {"state": "translated", "value": "%lld file"}},
+				"other": {"stringUnit": {"state": "translated", "value": "%lld files"}}
+			}}},
+			"ru": {"variations": {"plural": {
+				"one": {"stringUnit": {"state": "translated", "value": "%lld файл"}},
+				"few": {"stringUnit": {"state": "translated", "value": "%lld файла"}},
+				"many": {"stringUnit": {"state": "translated", "value": "%lld файлов"}},
+				"other": {"stringUnit": {"state": "translated", "value": "%lld файла"}}
+			}}}
+		}},
+		"%@ sent %lld photos": {"localizations": {
+			"en": {
+				"stringUnit": {"state": "translated", "value": "%1$@ sent %2$#@photos@"},
+				"substitutions": {"photos": {"argNum": 2, "formatSpecifier": "lld", "variations": {"plural": {
+					"zero": {"stringUnit": {"state": "translated", "value": "no photos"}},
+					"one": {"stringUnit": {"state": "translated", "value": "a photo"}},
+					"other": {"stringUnit": {"state": "translated", "value": "%arg photos"}}
+				}}}}
+			}
+		}},
+		"Tap to continue": {"localizations": {
+			"en": {"variations": {"device": {
+				"mac": {"stringUnit": {"state": "translated", "value": "Click to continue"}},
+				"other": {"stringUnit": {"state": "translated", "value": "Tap to continue"}}
+			}}}
+		}},
+		"Extracted": {}
+	},
+	"version": "1.0"
+}`
+
+func loadResolveFixture(t *testing.T) *xcstrings.XCStrings {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Localizable.xcstrings")
+	test.AssertNoError(t, os.WriteFile(path, []byte(resolveFixture), 0644))
+	x, err := xcstrings.Load(path)
+	test.AssertNoError(t, err)
+	return x
+}
+
+func TestResolve(t *testing.T) {
+	x := loadResolveFixture(t)
+	tests := []struct {
+		key      string
+		opts     Options
+		want     string
+		language string
+	}{
+		{"%lld files", Options{Language: "ru", Args: []string{"1"}}, "1 файл", "ru"},
+		{"%lld files", Options{Language: "ru", Args: []string{"22"}}, "22 файла", "ru"},
+		{"%lld files", Options{Language: "ru", Args: []string{"5"}}, "5 файлов", "ru"},
+		{"%lld files", Options{Language: "en", Args: []string{"1"}}, "1 file", "en"},
+		// A missing localization falls back to the source language.
+		{"%lld files", Options{Language: "ja", Args: []string{"2"}}, "2 files", "en"},
+		{"%@ sent %lld photos", Options{Language: "en", Args: []string{"Ana", "0"}}, "Ana sent no photos", "en"},
+		{"%@ sent %lld photos", Options{Language: "en", Args: []string{"Ana", "1"}}, "Ana sent a photo", "en"},
+		{"%@ sent %lld photos", Options{Language: "en", Args: []string{"Ana", "12"}}, "Ana sent 12 photos", "en"},
+		{"Tap to continue", Options{Language: "en", Device: "mac"}, "Click to continue", "en"},
+		{"Tap to continue", Options{Language: "en", Device: "iphone"}, "Tap to continue", "en"},
+		{"Extracted", Options{Language: "en"}, "Extracted", "en"},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(x, tt.key, tt.opts)
+		test.AssertNoError(t, err)
+		test.AssertEqual(t, got.Text, tt.want)
+		test.AssertEqual(t, got.Language, tt.language)
+	}
+
+	_, err := Resolve(x, "%lld files", Options{Language: "en"})
+	test.AssertError(t, err)
+	_, err = Resolve(x, "missing", Options{Language: "en"})
+	test.AssertError(t, err)
+}
+
+func TestPluralArguments(t *testing.T) {
+	x := loadResolveFixture(t)
+	for key, want := range map[string][]int{
+		"%lld files":          {1},
+		"%@ sent %lld photos": {2},
+		"Tap to continue":     {},
+	} {
+		got, language, err := PluralArguments(x, key, "ja")
+		test.AssertNoError(t, err)
+		test.AssertEqual(t, language, "en")
+		test.AssertEqual(t, len(got), len(want))
+		for i := range want {
+			test.AssertEqual(t, got[i], want[i])
+		}
+	}
+}
+
+func TestSampleCounts(t *testing.T) {
+	test.AssertEqual(t, len(SampleCounts("ja")), 3)
+	got := SampleCounts("ru")
+	want := []int64{0, 1, 2, 3, 5, 21, 1000000}
+	test.AssertEqual(t, len(got), len(want))
+	for i := range want {
+		test.AssertEqual(t, got[i], want[i])
+	}
+}
@@ -0,0 +1,443 @@
+package scan
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// swiftCall describes a Swift initializer or function that localizes its
+// key argument, by the labels of its arguments. keyLabel is "" when the key
+// is the first, unlabeled argument.
+type swiftCall struct {
+	keyLabel, table, comment, defaultValue string
+}
+
+// swiftCalls are the calls Xcode extracts strings from. The SwiftUI views
+// and modifiers take a LocalizedStringKey for a literal first argument.
+var swiftCalls = map[string]swiftCall{
+	"String":                  {keyLabel: "localized", table: "table", comment: "comment", defaultValue: "defaultValue"},
+	"LocalizedStringResource": {table: "table", comment: "comment", defaultValue: "defaultValue"},
+	"LocalizedStringKey":      {},
+	"NSLocalizedString":       {table: "tableName", comment: "comment", defaultValue: "value"},
+	"Text":                    {table: "tableName", comment: "comment"},
+	"Button":                  {},
+	"Label":                   {},
+	"Toggle":                  {},
+	"navigationTitle":         {},
+}
+
+// objcCall describes an Objective-C localization macro by the positions of
+// its arguments after the key; 0 when it has no such argument.
+type objcCall struct {
+	table, comment, defaultValue int
+}
+
+var objcCalls = map[string]objcCall{
+	"NSLocalizedString":                  {comment: 1},
+	"NSLocalizedStringFromTable":         {table: 1, comment: 2},
+	"NSLocalizedStringFromTableInBundle": {table: 1, comment: 3},
+	"NSLocalizedStringWithDefaultValue":  {table: 1, defaultValue: 3, comment: 4},
+}
+
+// Swift returns the usages in Swift source src, read from file.
+func Swift(file string, src []byte) []Usage {
+	p := &parser{src: string(src), file: file}
+	return p.usages()
+}
+
+// ObjC returns the usages in Objective-C source src, read from file.
+func ObjC(file string, src []byte) []Usage {
+	p := &parser{src: string(src), file: file, objc: true}
+	return p.usages()
+}
+
+// argument is one argument of a call. segments is nil when the argument is
+// not a single string literal.
+type argument struct {
+	label    string
+	segments []string
+}
+
+func (a argument) text() (string, bool) {
+	if a.segments == nil || len(a.segments) > 1 {
+		return "", false
+	}
+	return a.segments[0], true
+}
+
+type parser struct {
+	src  string
+	pos  int
+	file string
+	objc bool
+}
+
+func (p *parser) usages() []Usage {
+	var usages []Usage
+	for p.pos < len(p.src) {
+		if p.skipTrivia() {
+			continue
+		}
+		c := p.src[p.pos]
+		switch {
+		case c == '"' || c == '#' || (c == '@' && p.peek(1) == '"') || (p.objc && c == '\''):
+			p.skipLiteral()
+		case isIdentStart(c):
+			start := p.pos
+			name := p.identifier()
+			if u, ok := p.call(name); ok {
+				u.File = p.file
+				u.Line = 1 + strings.Count(p.src[:start], "\n")
+				usages = append(usages, u)
+			}
+		default:
+			p.pos++
+		}
+	}
+	return usages
+}
+
+// call reports the usage made by a call of name whose arguments start at
+// the current position. The position is left just past the opening
+// parenthesis, so calls nested in the arguments are found as well.
+func (p *parser) call(name string) (Usage, bool) {
+	var args []argument
+	save := p.pos
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != '(' {
+		p.pos = save
+		return Usage{}, false
+	}
+	p.pos++
+	open := p.pos
+	if p.objc {
+		spec, ok := objcCalls[name]
+		if !ok {
+			return Usage{}, false
+		}
+		args = p.arguments()
+		p.pos = open
+		return objcUsage(spec, args)
+	}
+	spec, ok := swiftCalls[name]
+	if !ok {
+		return Usage{}, false
+	}
+	args = p.arguments()
+	p.pos = open
+	return swiftUsage(spec, args)
+}
+
+func swiftUsage(spec swiftCall, args []argument) (Usage, bool) {
+	if len(args) == 0 || args[0].label != spec.keyLabel || len(args[0].segments) == 0 {
+		return Usage{}, false
+	}
+	u := newUsage(args[0].segments)
+	if u.Key == "" {
+		return Usage{}, false
+	}
+	for _, arg := range args[1:] {
+		value, ok := arg.text()
+		if !ok || arg.label == "" {
+			continue
+		}
+		switch arg.label {
+		case spec.table:
+			u.Table = value
+		case spec.comment:
+			u.Comment = value
+		case spec.defaultValue:
+			u.DefaultValue = value
+		}
+	}
+	return u, true
+}
+
+func objcUsage(spec objcCall, args []argument) (Usage, bool) {
+	if len(args) == 0 || len(args[0].segments) != 1 || args[0].segments[0] == "" {
+		return Usage{}, false
+	}
+	u := newUsage(args[0].segments)
+	text := func(i int) string {
+		if i == 0 || i >= len(args) {
+			return ""
+		}
+		value, _ := args[i].text()
+		return value
+	}
+	u.Table = text(spec.table)
+	u.Comment = text(spec.comment)
+	u.DefaultValue = text(spec.defaultValue)
+	return u, true
+}
+
+// arguments parses a call's arguments up to and including its closing
+// parenthesis.
+func (p *parser) arguments() []argument {
+	var args []argument
+	for p.pos < len(p.src) {
+		var arg argument
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == ')' {
+			p.pos++
+			return args
+		}
+		if !p.objc {
+			save := p.pos
+			if isIdentStart(p.src[p.pos]) {
+				label := p.identifier()
+				p.skipSpace()
+				if p.peek(0) == ':' && p.peek(1) != ':' {
+					p.pos++
+					arg.label = label
+				} else {
+					p.pos = save
+				}
+			}
+			p.skipSpace()
+		}
+
+		start := p.pos
+		if segments, ok := p.literal(); ok {
+			p.skipSpace()
+			if c := p.peek(0); c == ',' || c == ')' {
+				arg.segments = segments
+			}
+		}
+		if arg.segments == nil {
+			p.pos = start
+		}
+		end := p.skipExpression()
+		args = append(args, arg)
+		if end != ',' {
+			return args
+		}
+	}
+	return args
+}
+
+// skipExpression advances to the next top-level ',' or ')' of the argument
+// list and past it, returning it (0 at the end of the source).
+func (p *parser) skipExpression() byte {
+	depth := 0
+	for p.pos < len(p.src) {
+		if p.skipTrivia() {
+			continue
+		}
+		c := p.src[p.pos]
+		switch {
+		case c == '"' || c == '#' || (c == '@' && p.peek(1) == '"') || (p.objc && c == '\''):
+			p.skipLiteral()
+			continue
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			if depth == 0 {
+				p.pos++
+				return c
+			}
+			depth--
+		case c == ',' && depth == 0:
+			p.pos++
+			return c
+		}
+		p.pos++
+	}
+	return 0
+}
+
+// skipTrivia skips a comment at the current position, reporting whether
+// there was one.
+func (p *parser) skipTrivia() bool {
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "//"):
+		if i := strings.IndexByte(p.src[p.pos:], '\n'); i >= 0 {
+			p.pos += i + 1
+		} else {
+			p.pos = len(p.src)
+		}
+		return true
+	case strings.HasPrefix(p.src[p.pos:], "/*"):
+		if i := strings.Index(p.src[p.pos+2:], "*/"); i >= 0 {
+			p.pos += i + 4
+		} else {
+			p.pos = len(p.src)
+		}
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) {
+		if p.skipTrivia() {
+			continue
+		}
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// skipLiteral skips the string or character literal at the current
+// position, or a single character when there is none.
+func (p *parser) skipLiteral() {
+	start := p.pos
+	if _, ok := p.literal(); !ok && p.pos == start {
+		p.pos++
+	}
+}
+
+// literal parses the string literal at the current position and returns the
+// text between its interpolations. ok is false, with the literal skipped
+// when there is one, for anything but a plain single-line literal:
+// multi-line and raw Swift strings are not extracted.
+func (p *parser) literal() (segments []string, ok bool) {
+	rest := p.src[p.pos:]
+	switch {
+	case p.objc && strings.HasPrefix(rest, `@"`):
+		p.pos++
+		return p.quoted('"', false)
+	case p.objc && strings.HasPrefix(rest, "'"):
+		_, _ = p.quoted('\'', false)
+		return nil, false
+	case p.objc && strings.HasPrefix(rest, `"`):
+		// A C string is not an NSString key.
+		_, _ = p.quoted('"', false)
+		return nil, false
+	case strings.HasPrefix(rest, `"""`):
+		if i := strings.Index(rest[3:], `"""`); i >= 0 {
+			p.pos += i + 6
+		} else {
+			p.pos = len(p.src)
+		}
+		return nil, false
+	case strings.HasPrefix(rest, `#`):
+		hashes := len(rest) - len(strings.TrimLeft(rest, "#"))
+		if !strings.HasPrefix(rest[hashes:], `"`) {
+			return nil, false
+		}
+		closing := `"` + rest[:hashes]
+		if strings.HasPrefix(rest[hashes:], `"""`) {
+			closing = `"""` + rest[:hashes]
+		}
+		if i := strings.Index(rest[hashes+1:], closing); i >= 0 {
+			p.pos += hashes + 1 + i + len(closing)
+		} else {
+			p.pos = len(p.src)
+		}
+		return nil, false
+	case strings.HasPrefix(rest, `"`):
+		return p.quoted('"', true)
+	}
+	return nil, false
+}
+
+// quoted parses a literal delimited by quote, starting at the opening quote,
+// with Swift interpolations when swift is set.
+func (p *parser) quoted(quote byte, swift bool) ([]string, bool) {
+	p.pos++
+	var segments []string
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case quote:
+			p.pos++
+			return append(segments, b.String()), true
+		case '\n':
+			return nil, false
+		case '\\':
+			p.pos++
+			if p.pos >= len(p.src) {
+				return nil, false
+			}
+			e := p.src[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			case 'u':
+				b.WriteString(p.unicodeEscape(swift))
+			case '(':
+				if !swift {
+					b.WriteByte(e)
+					continue
+				}
+				segments = append(segments, b.String())
+				b.Reset()
+				// Skip the interpolated expression, which may have
+				// arguments, as in \(price, format: .currency(code: "EUR")).
+				for end := p.skipExpression(); end != ')'; end = p.skipExpression() {
+					if end != ',' {
+						return nil, false
+					}
+				}
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return nil, false
+}
+
+// unicodeEscape decodes the rest of a \u escape: \u{1F600} in Swift,
+// \u00e9 in C.
+func (p *parser) unicodeEscape(swift bool) string {
+	var hex string
+	if swift {
+		end := strings.IndexByte(p.src[p.pos:], '}')
+		if p.peek(0) != '{' || end < 0 {
+			return "u"
+		}
+		hex = p.src[p.pos+1 : p.pos+end]
+		p.pos += end + 1
+	} else {
+		if p.pos+4 > len(p.src) {
+			return "u"
+		}
+		hex = p.src[p.pos : p.pos+4]
+		p.pos += 4
+	}
+	r, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || !utf8.ValidRune(rune(r)) {
+		return "u" + hex
+	}
+	return string(rune(r))
+}
+
+func (p *parser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.src) && isIdentPart(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) peek(offset int) byte {
+	if p.pos+offset < len(p.src) {
+		return p.src[p.pos+offset]
+	}
+	return 0
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
@@ -0,0 +1,125 @@
+package scan
+
+import (
+	"testing"
+
+	"xckit/helper/test"
+)
+
+const swiftFixture = `import SwiftUI
+
+struct SettingsView: View {
+    let count: Int
+
+    var body: some View {
+        VStack {
+            Text("settings.title")
+            Text("Hello, \(name)!", comment: "Greeting with the user's name")
+            Text(verbatim: "Not localized")
+            Text(title)
+            Button("Save") { save() }
+            Button(action: save) { Text("Save changes") }
+            Label("Trash", systemImage: "trash")
+            Toggle("Notifications", isOn: $enabled)
+            // Text("commented.out")
+            /* Text("also.commented") */
+            Text("\(count, specifier: "%lld") items")
+        }
+        .navigationTitle("Settings")
+    }
+
+    func message() -> String {
+        String(localized: "settings.saved", defaultValue: "Saved \"\u{2713}\"", table: "Settings", comment: "Shown after saving")
+    }
+
+    let resource = LocalizedStringResource("widget.title", comment: "Widget")
+    let key: LocalizedStringKey = "not.a.call"
+    let legacy = NSLocalizedString("legacy.key", tableName: "Legacy", value: "Legacy", comment: "")
+    let raw = #"Text("raw.string")"#
+    let multiline = """
+        Text("in.multiline")
+        """
+}
+`
+
+func TestSwift(t *testing.T) {
+	usages := Swift("SettingsView.swift", []byte(swiftFixture))
+
+	var keys []string
+	for _, u := range usages {
+		keys = append(keys, u.Key)
+	}
+	test.AssertSliceEqual(t, keys, []string{
+		"settings.title",
+		"Hello, %@!",
+		"Save",
+		"Save changes",
+		"Trash",
+		"Notifications",
+		"%@ items",
+		"Settings",
+		"settings.saved",
+		"widget.title",
+		"legacy.key",
+	})
+
+	byKey := make(map[string]Usage)
+	for _, u := range usages {
+		byKey[u.Key] = u
+	}
+	greeting := byKey["Hello, %@!"]
+	test.AssertEqual(t, greeting.Interpolated, true)
+	test.AssertEqual(t, greeting.Comment, "Greeting with the user's name")
+	test.AssertEqual(t, greeting.Line, 9)
+	test.AssertEqual(t, greeting.File, "SettingsView.swift")
+
+	saved := byKey["settings.saved"]
+	test.AssertEqual(t, saved.DefaultValue, `Saved "✓"`)
+	test.AssertEqual(t, saved.Table, "Settings")
+	test.AssertEqual(t, saved.TableName(), "Settings")
+	test.AssertEqual(t, saved.Comment, "Shown after saving")
+
+	legacy := byKey["legacy.key"]
+	test.AssertEqual(t, legacy.Table, "Legacy")
+	test.AssertEqual(t, legacy.DefaultValue, "Legacy")
+	test.AssertEqual(t, byKey["settings.title"].TableName(), "Localizable")
+}
+
+func TestObjC(t *testing.T) {
+	src := `#import "ViewController.h"
+
+@implementation ViewController
+- (void)viewDidLoad {
+    self.title = NSLocalizedString(@"home.title", @"Home screen title");
+    label.text = NSLocalizedStringFromTable(@"settings.done", @"Settings", nil);
+    other.text = NSLocalizedStringWithDefaultValue(@"welcome", nil, [NSBundle mainBundle], @"Welcome!", @"Shown once");
+    NSLog(@"NSLocalizedString(@\"not.a.call\", nil)");
+    char *c = "NSLocalizedString(@\"c.string\", nil)";
+}
+@end
+`
+	usages := ObjC("ViewController.m", []byte(src))
+	test.AssertEqual(t, len(usages), 3)
+	test.AssertEqual(t, usages[0], Usage{Key: "home.title", Comment: "Home screen title", File: "ViewController.m", Line: 5})
+	test.AssertEqual(t, usages[1], Usage{Key: "settings.done", Table: "Settings", File: "ViewController.m", Line: 6})
+	test.AssertEqual(t, usages[2], Usage{Key: "welcome", DefaultValue: "Welcome!", Comment: "Shown once", File: "ViewController.m", Line: 7})
+}
+
+func TestUsage_Matches(t *testing.T) {
+	u := newUsage([]string{"", " of ", " files"})
+	test.AssertEqual(t, u.Key, "%@ of %@ files")
+	for key, want := range map[string]bool{
+		"%lld of %lld files":   true,
+		"%1$@ of %2$lld files": true,
+		"%@ of %@ files":       true,
+		"%.1f of %d files":     true,
+		"3 of 4 files":         false,
+		"%@ of %@ folders":     false,
+	} {
+		test.AssertEqual(t, u.Matches(key), want)
+	}
+
+	plain := newUsage([]string{"100% done"})
+	test.AssertEqual(t, plain.Matches("100% done"), true)
+	test.AssertEqual(t, plain.Matches("100%@ done"), false)
+}
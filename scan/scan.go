@@ -0,0 +1,117 @@
+// Package scan finds the localized strings Swift and Objective-C sources use,
+// the way Xcode's string extraction does, so that a catalog can be checked
+// against the code without Xcode.
+package scan
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Usage is a localizable string literal found in source code.
+type Usage struct {
+	// Key is the literal's value. Each interpolation (\(...)) in a Swift
+	// literal is written as %@; see Interpolated.
+	Key string
+	// Table is the strings table the call names, "" for the default
+	// (Localizable).
+	Table        string
+	Comment      string
+	DefaultValue string
+	// Interpolated reports whether the key contains interpolations. Xcode
+	// turns each into a format specifier that depends on the interpolated
+	// value's type, so the key matches a catalog key with any specifier in
+	// their place.
+	Interpolated bool
+	File         string
+	Line         int
+
+	pattern *regexp.Regexp
+}
+
+// Matches reports whether key is the catalog key for u.
+func (u Usage) Matches(key string) bool {
+	if u.pattern == nil {
+		return key == u.Key
+	}
+	return u.pattern.MatchString(key)
+}
+
+// TableName returns the strings table u belongs to.
+func (u Usage) TableName() string {
+	if u.Table == "" {
+		return "Localizable"
+	}
+	return u.Table
+}
+
+// formatSpecifier matches a printf-style format specifier, as Xcode writes
+// for an interpolation.
+const formatSpecifier = `%(?:\d+\$)?[-+ #0]*\d*(?:\.\d+)?(?:hh|h|ll|l|q|L|z|t|j)?[@dDuUxXoOfFeEgGcCsSaAp]`
+
+// newUsage makes a usage from a literal's segments: the text between its
+// interpolations.
+func newUsage(segments []string) Usage {
+	u := Usage{Key: strings.Join(segments, "%@")}
+	if len(segments) > 1 {
+		quoted := make([]string, len(segments))
+		for i, segment := range segments {
+			quoted[i] = regexp.QuoteMeta(segment)
+		}
+		u.Interpolated = true
+		u.pattern = regexp.MustCompile("^" + strings.Join(quoted, formatSpecifier) + "$")
+	}
+	return u
+}
+
+// Sources returns the usages in the Swift (.swift) and Objective-C (.m, .mm)
+// files at or below each root, skipping hidden directories, sorted by file
+// and line.
+func Sources(roots []string) ([]Usage, error) {
+	var usages []Usage
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			objc := false
+			switch filepath.Ext(path) {
+			case ".swift":
+			case ".m", ".mm":
+				objc = true
+			default:
+				return nil
+			}
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if objc {
+				usages = append(usages, ObjC(path, src)...)
+			} else {
+				usages = append(usages, Swift(path, src)...)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].File != usages[j].File {
+			return usages[i].File < usages[j].File
+		}
+		return usages[i].Line < usages[j].Line
+	})
+	return usages, nil
+}
@@ -0,0 +1,42 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func TestSources(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"App/ContentView.swift":       `Text("b.swift")` + "\n" + `Text("a.swift")`,
+		"App/Legacy/Controller.m":     `NSLocalizedString(@"objc", nil);`,
+		"App/README.md":               `Text("not.source")`,
+		"App/.build/Dep.swift":        `Text("hidden")`,
+		"Packages/Feature/View.swift": `String(localized: "package")`,
+	} {
+		path := filepath.Join(dir, name)
+		test.AssertNoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		test.AssertNoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	usages, err := Sources([]string{filepath.Join(dir, "App"), filepath.Join(dir, "Packages")})
+	test.AssertNoError(t, err)
+	var got []string
+	for _, u := range usages {
+		rel, err := filepath.Rel(dir, u.File)
+		test.AssertNoError(t, err)
+		got = append(got, u.Key+"@"+rel)
+	}
+	test.AssertSliceEqual(t, got, []string{
+		"b.swift@App/ContentView.swift",
+		"a.swift@App/ContentView.swift",
+		"objc@App/Legacy/Controller.m",
+		"package@Packages/Feature/View.swift",
+	})
+
+	_, err = Sources([]string{filepath.Join(dir, "missing")})
+	test.AssertError(t, err)
+}
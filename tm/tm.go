@@ -0,0 +1,270 @@
+// Package tm implements a translation memory: an index of the translated
+// string units of one or more catalogs, queried by source text for exact and
+// fuzzy matches.
+package tm
+
+import (
+	"sort"
+	"strings"
+
+	"xckit/xcstrings"
+)
+
+// Entry is a single translated unit in the memory.
+type Entry struct {
+	SourceLanguage string
+	Language       string
+	Source         string
+	Target         string
+	// Category is the plural category of the unit ("one", "other", ...), or
+	// "" when it is not a plural form. Matches never cross categories, since
+	// a language's "one" form is not a translation of another's "other".
+	Category string
+	Key      string
+	Path     string // variation path within the key, "" for the plain string
+	Origin   string // the catalog the entry was indexed from
+}
+
+// Match is the result of a lookup.
+type Match struct {
+	Entry
+	// Score is the similarity between the queried source text and the
+	// entry's, from 0 to 1; 1 is an exact match.
+	Score float64
+}
+
+// Exact reports whether the match's source text is identical to the query.
+func (m Match) Exact() bool {
+	return m.Score == 1
+}
+
+type bucket struct {
+	sourceLanguage, language, category string
+}
+
+// Memory is a translation memory. The zero value is not usable; create one
+// with New.
+type Memory struct {
+	entries map[bucket][]Entry
+	exact   map[bucket]map[string]int // source text -> index into entries
+	seen    map[bucket]map[[2]string]bool
+}
+
+// New returns an empty memory.
+func New() *Memory {
+	return &Memory{
+		entries: make(map[bucket][]Entry),
+		exact:   make(map[bucket]map[string]int),
+		seen:    make(map[bucket]map[[2]string]bool),
+	}
+}
+
+// Len returns the number of entries in the memory.
+func (m *Memory) Len() int {
+	n := 0
+	for _, entries := range m.entries {
+		n += len(entries)
+	}
+	return n
+}
+
+// AddCatalog indexes every translated, non-empty string unit of every
+// non-source language of xc, and returns the number of entries added. A
+// unit's source text is the source language's unit at the same path; plural
+// categories the source language doesn't use fall back to its "other" form,
+// and keys without a source localization use the key itself. Duplicate
+// source/target pairs are indexed once, and when one source text has
+// several translations, the first one added is preferred for exact matches.
+func (m *Memory) AddCatalog(xc *xcstrings.XCStrings, origin string) int {
+	keys := make([]string, 0, len(xc.Strings))
+	for k := range xc.Strings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	added := 0
+	for _, key := range keys {
+		def := xc.Strings[key]
+		srcLoc, hasSource := def.Localizations[xc.SourceLanguage]
+
+		langs := make([]string, 0, len(def.Localizations))
+		for lang := range def.Localizations {
+			if lang != xc.SourceLanguage {
+				langs = append(langs, lang)
+			}
+		}
+		sort.Strings(langs)
+
+		for _, lang := range langs {
+			for path, unit := range Leaves(def.Localizations[lang]) {
+				if unit.State != "translated" || unit.Value == "" {
+					continue
+				}
+				source := key
+				if hasSource {
+					source = sourceText(srcLoc, path)
+				}
+				if source == "" {
+					continue
+				}
+				if m.add(Entry{
+					SourceLanguage: xc.SourceLanguage,
+					Language:       lang,
+					Source:         source,
+					Target:         unit.Value,
+					Category:       Category(path),
+					Key:            key,
+					Path:           path,
+					Origin:         origin,
+				}) {
+					added++
+				}
+			}
+		}
+	}
+	return added
+}
+
+func (m *Memory) add(e Entry) bool {
+	b := bucket{e.SourceLanguage, e.Language, e.Category}
+	pair := [2]string{e.Source, e.Target}
+	if m.seen[b] == nil {
+		m.seen[b] = make(map[[2]string]bool)
+		m.exact[b] = make(map[string]int)
+	}
+	if m.seen[b][pair] {
+		return false
+	}
+	m.seen[b][pair] = true
+	if _, ok := m.exact[b][e.Source]; !ok {
+		m.exact[b][e.Source] = len(m.entries[b])
+	}
+	m.entries[b] = append(m.entries[b], e)
+	return true
+}
+
+// Lookup returns the best entry translating source from sourceLanguage into
+// language for a unit of the given plural category ("" for none): an exact
+// match when there is one, otherwise the most similar source text scoring
+// at least minScore. Ties go to the entry added first.
+func (m *Memory) Lookup(sourceLanguage, language, category, source string, minScore float64) (Match, bool) {
+	b := bucket{sourceLanguage, language, category}
+	entries := m.entries[b]
+	if i, ok := m.exact[b][source]; ok {
+		return Match{Entry: entries[i], Score: 1}, true
+	}
+
+	best := Match{}
+	found := false
+	n := len([]rune(source))
+	for _, e := range entries {
+		// The length difference alone bounds the best possible score.
+		en := len([]rune(e.Source))
+		if bound := 1 - float64(abs(n-en))/float64(max(n, en)); bound < minScore || (found && bound <= best.Score) {
+			continue
+		}
+		score := Similarity(source, e.Source)
+		if score >= minScore && (!found || score > best.Score) {
+			best = Match{Entry: e, Score: score}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// Similarity returns 1 minus the edit distance between a and b divided by
+// the length of the longer one, counted in runes: 1 for identical strings,
+// 0 for entirely different ones.
+func Similarity(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	longest := max(len(ar), len(br))
+	if longest == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(br)])/float64(longest)
+}
+
+// Category returns the plural category a variation path ends in, or "" when
+// its last variation is not a plural one.
+func Category(path string) string {
+	idx := strings.LastIndex(path, "plural.")
+	if idx < 0 || strings.Contains(path[idx+len("plural."):], ".") {
+		return ""
+	}
+	return path[idx+len("plural."):]
+}
+
+// Leaves returns every string unit of loc keyed by its variation path ("" for
+// the plain string, "plural.one", "device.iphone.plural.other",
+// "substitutions.files.plural.one", ...).
+func Leaves(loc xcstrings.Localization) map[string]*xcstrings.StringUnit {
+	leaves := make(map[string]*xcstrings.StringUnit)
+	if loc.StringUnit != nil {
+		leaves[""] = loc.StringUnit
+	}
+	if loc.Variations != nil {
+		addVariationLeaves(leaves, loc.Variations, "")
+	}
+	for name, sub := range loc.Substitutions {
+		addVariationLeaves(leaves, &sub.Variations, "substitutions."+name+".")
+	}
+	return leaves
+}
+
+func addVariationLeaves(leaves map[string]*xcstrings.StringUnit, v *xcstrings.Variations, prefix string) {
+	add := func(kind string, values map[string]*xcstrings.VariationValue) {
+		for name, vv := range values {
+			if vv == nil {
+				continue
+			}
+			path := prefix + kind + "." + name
+			if vv.StringUnit != nil {
+				leaves[path] = vv.StringUnit
+			}
+			if vv.Variations != nil {
+				addVariationLeaves(leaves, vv.Variations, path+".")
+			}
+		}
+	}
+	add("plural", v.Plural)
+	add("device", v.Device)
+}
+
+// sourceText returns the source unit's text at path, falling back to the
+// "other" form for a plural category the source language doesn't use.
+func sourceText(srcLoc xcstrings.Localization, path string) string {
+	leaves := Leaves(srcLoc)
+	if unit, ok := leaves[path]; ok {
+		return unit.Value
+	}
+	if cat := Category(path); cat != "" {
+		if unit, ok := leaves[strings.TrimSuffix(path, cat)+"other"]; ok {
+			return unit.Value
+		}
+	}
+	return ""
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
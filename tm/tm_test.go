@@ -0,0 +1,146 @@
+package tm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"xckit/helper/test"
+	"xckit/xcstrings"
+)
+
+const memoryFixture = `{
+  "sourceLanguage" : "en",
+  "strings" : {
+    "cancel" : {
+      "localizations" : {
+        "en" : { "stringUnit" : { "state" : "translated", "value" : "Cancel" } },
+        "ja" : { "stringUnit" : { "state" : "translated", "value" : "キャンセル" } },
+        "fr" : { "stringUnit" : { "state" : "needs_review", "value" : "Annuler" } }
+      }
+    },
+    "items" : {
+      "localizations" : {
+        "en" : { "variations" : { "plural" : {
+          "one" : { "stringUnit" : { "state" : "translated", "value" : "%lld item" } },
+          "other" : { "stringUnit" : { "state" : "translated", "value" : "%lld items" } }
+        } } },
+        "ru" : { "variations" : { "plural" : {
+          "one" : { "stringUnit" : { "state" : "translated", "value" : "%lld элемент" } },
+          "few" : { "stringUnit" : { "state" : "translated", "value" : "%lld элемента" } },
+          "other" : { "stringUnit" : { "state" : "translated", "value" : "%lld элементов" } }
+        } } }
+      }
+    },
+    "Save changes" : {
+      "localizations" : {
+        "ja" : { "stringUnit" : { "state" : "translated", "value" : "変更を保存" } }
+      }
+    }
+  },
+  "version" : "1.0"
+}`
+
+func loadFixture(t *testing.T) *xcstrings.XCStrings {
+	t.Helper()
+	var xc xcstrings.XCStrings
+	test.AssertNoError(t, json.Unmarshal([]byte(memoryFixture), &xc))
+	return &xc
+}
+
+func TestAddCatalog_IndexesTranslatedUnitsOnly(t *testing.T) {
+	m := New()
+	// cancel/ja, items/ru one, few, other, and "Save changes"/ja; the
+	// needs_review French unit is left out.
+	test.AssertEqual(t, m.AddCatalog(loadFixture(t), "a.xcstrings"), 5)
+	// Indexing the same catalog again adds nothing.
+	test.AssertEqual(t, m.AddCatalog(loadFixture(t), "b.xcstrings"), 0)
+	test.AssertEqual(t, m.Len(), 5)
+
+	if _, ok := m.Lookup("en", "fr", "", "Cancel", 0.5); ok {
+		t.Error("needs_review units must not be indexed")
+	}
+}
+
+func TestLookup_Exact(t *testing.T) {
+	m := New()
+	m.AddCatalog(loadFixture(t), "a.xcstrings")
+
+	match, ok := m.Lookup("en", "ja", "", "Cancel", 0.8)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, match.Target, "キャンセル")
+	test.AssertEqual(t, match.Exact(), true)
+	test.AssertEqual(t, match.Origin, "a.xcstrings")
+
+	// A key without a source localization is its own source text.
+	match, ok = m.Lookup("en", "ja", "", "Save changes", 0.8)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, match.Target, "変更を保存")
+}
+
+func TestLookup_PluralCategories(t *testing.T) {
+	m := New()
+	m.AddCatalog(loadFixture(t), "a.xcstrings")
+
+	// ru "few" has no English counterpart and is indexed under "other".
+	match, ok := m.Lookup("en", "ru", "few", "%lld items", 0.8)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, match.Target, "%lld элемента")
+
+	match, ok = m.Lookup("en", "ru", "other", "%lld items", 0.8)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, match.Target, "%lld элементов")
+
+	// Categories never cross.
+	if _, ok := m.Lookup("en", "ru", "", "%lld items", 0.5); ok {
+		t.Error("a plain unit should not match a plural form")
+	}
+}
+
+func TestLookup_Fuzzy(t *testing.T) {
+	m := New()
+	m.AddCatalog(loadFixture(t), "a.xcstrings")
+
+	match, ok := m.Lookup("en", "ja", "", "Save change", 0.8)
+	test.AssertEqual(t, ok, true)
+	test.AssertEqual(t, match.Exact(), false)
+	test.AssertEqual(t, match.Source, "Save changes")
+	test.AssertEqual(t, match.Target, "変更を保存")
+
+	if _, ok := m.Lookup("en", "ja", "", "Save", 0.8); ok {
+		t.Error("a match below the minimum score should not be returned")
+	}
+	if _, ok := m.Lookup("de", "ja", "", "Cancel", 0.8); ok {
+		t.Error("a different source language should not match")
+	}
+}
+
+func TestLookup_FirstCatalogWinsExactTies(t *testing.T) {
+	other := loadFixture(t)
+	unit := other.Strings["cancel"].Localizations["ja"].StringUnit
+	unit.Value = "取り消し"
+
+	m := New()
+	m.AddCatalog(loadFixture(t), "a.xcstrings")
+	test.AssertEqual(t, m.AddCatalog(other, "b.xcstrings"), 1)
+
+	match, _ := m.Lookup("en", "ja", "", "Cancel", 0.8)
+	test.AssertEqual(t, match.Target, "キャンセル")
+}
+
+func TestSimilarity(t *testing.T) {
+	test.AssertEqual(t, Similarity("", ""), 1.0)
+	test.AssertEqual(t, Similarity("abc", "abc"), 1.0)
+	test.AssertEqual(t, Similarity("abc", "xyz"), 0.0)
+	test.AssertEqual(t, Similarity("kitten", "sitting"), 1-3.0/7)
+	// Counted in runes, not bytes.
+	test.AssertEqual(t, Similarity("日本語", "日本"), 1-float64(1)/3)
+}
+
+func TestCategory(t *testing.T) {
+	test.AssertEqual(t, Category(""), "")
+	test.AssertEqual(t, Category("plural.one"), "one")
+	test.AssertEqual(t, Category("device.iphone"), "")
+	test.AssertEqual(t, Category("plural.one.device.iphone"), "")
+	test.AssertEqual(t, Category("device.iphone.plural.few"), "few")
+	test.AssertEqual(t, Category("substitutions.files.plural.other"), "other")
+}
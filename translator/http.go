@@ -0,0 +1,102 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPTranslator is a generic backend that POSTs each batch as JSON to an
+// endpoint:
+//
+//	{"sourceLanguage": "en", "targetLanguage": "ja", "texts": ["...", ...]}
+//
+// and expects a JSON response with one translation per text:
+//
+//	{"translations": ["...", ...]}
+//
+// Any service can be adapted to this protocol with a small proxy.
+type HTTPTranslator struct {
+	Endpoint string
+	// APIKey, when set, is sent as "Authorization: Bearer <APIKey>".
+	APIKey string
+	Client *http.Client
+}
+
+// NewHTTPTranslator returns an HTTPTranslator for endpoint with a client
+// that gives up on a batch after a minute.
+func NewHTTPTranslator(endpoint, apiKey string) *HTTPTranslator {
+	return &HTTPTranslator{
+		Endpoint: endpoint,
+		APIKey:   apiKey,
+		Client:   &http.Client{Timeout: time.Minute},
+	}
+}
+
+type httpRequest struct {
+	SourceLanguage string   `json:"sourceLanguage"`
+	TargetLanguage string   `json:"targetLanguage"`
+	Texts          []string `json:"texts"`
+}
+
+type httpResponse struct {
+	Translations []string `json:"translations"`
+}
+
+// Translate sends texts to the endpoint in a single request.
+func (t *HTTPTranslator) Translate(ctx context.Context, sourceLanguage, targetLanguage string, texts []string) ([]string, error) {
+	body, err := json.Marshal(httpRequest{
+		SourceLanguage: sourceLanguage,
+		TargetLanguage: targetLanguage,
+		Texts:          texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if t.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.APIKey)
+	}
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200] + "..."
+		}
+		return nil, fmt.Errorf("translation endpoint returned %s: %s", resp.Status, msg)
+	}
+
+	var out httpResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("invalid response from translation endpoint: %w", err)
+	}
+	if len(out.Translations) != len(texts) {
+		return nil, fmt.Errorf("translation endpoint returned %d translations for %d texts", len(out.Translations), len(texts))
+	}
+	return out.Translations, nil
+}
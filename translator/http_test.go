@@ -0,0 +1,79 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func TestHTTPTranslator_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		test.AssertEqual(t, r.Method, http.MethodPost)
+		test.AssertEqual(t, r.Header.Get("Authorization"), "Bearer secret")
+		test.AssertEqual(t, r.Header.Get("Content-Type"), "application/json")
+
+		var req httpRequest
+		test.AssertNoError(t, json.NewDecoder(r.Body).Decode(&req))
+		test.AssertEqual(t, req.SourceLanguage, "en")
+		test.AssertEqual(t, req.TargetLanguage, "ja")
+
+		out := httpResponse{}
+		for _, text := range req.Texts {
+			out.Translations = append(out.Translations, "ja:"+text)
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+
+	got, err := NewHTTPTranslator(server.URL, "secret").Translate(context.Background(), "en", "ja", []string{"a", "b"})
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, got, []string{"ja:a", "ja:b"})
+}
+
+func TestHTTPTranslator_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantMsg string
+	}{
+		{
+			name: "non-2xx status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+			},
+			wantMsg: "quota exceeded",
+		},
+		{
+			name: "wrong number of translations",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"translations": ["only one"]}`))
+			},
+			wantMsg: "1 translations for 2 texts",
+		},
+		{
+			name: "invalid JSON",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`<html>`))
+			},
+			wantMsg: "invalid response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			_, err := NewHTTPTranslator(server.URL, "").Translate(context.Background(), "en", "ja", []string{"a", "b"})
+			test.AssertError(t, err)
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.wantMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package translator
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Pseudo pseudo-localizes text: it keeps it readable but makes untranslated
+// (hard-coded) strings, truncation and layout assumptions visible on screen.
+type Pseudo struct {
+	// Expansion lengthens each text by this fraction of its letters (0.3
+	// adds 30%), as translations into many languages run longer.
+	Expansion float64
+	// Brackets wraps each text in [ and ], so a clipped end is noticeable.
+	Brackets bool
+	// RTL wraps each run of text in a right-to-left override, so the layout
+	// can be checked as for Arabic or Hebrew without knowing either.
+	RTL bool
+}
+
+// pseudoAccents maps ASCII letters to accented look-alikes.
+var pseudoAccents = map[rune]rune{
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Đ', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î',
+	'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ',
+	'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+	'a': 'å', 'b': 'ƀ', 'c': 'ç', 'd': 'đ', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î',
+	'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ṁ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ',
+	's': 'š', 't': 'ţ', 'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+}
+
+// pseudoAccented is the set of accented look-alikes of pseudoAccents.
+var pseudoAccented = func() map[rune]bool {
+	m := make(map[rune]bool, len(pseudoAccents))
+	for _, r := range pseudoAccents {
+		m[r] = true
+	}
+	return m
+}()
+
+const (
+	rightToLeftOverride  = "\u202E"
+	popDirectionalFormat = "\u202C"
+)
+
+// Localize returns the pseudo-localized form of text. Format tokens
+// (printf conversions, %#@name@ references, %arg and %%) are kept exactly,
+// so the result takes the same arguments as text.
+func (p Pseudo) Localize(text string) string {
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	if p.Brackets {
+		b.WriteString("[")
+	}
+	letters := 0
+	last := 0
+	for _, loc := range append(tokenRe.FindAllStringIndex(text, -1), []int{len(text), len(text)}) {
+		letters += p.run(&b, text[last:loc[0]])
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	if padding := int(math.Ceil(float64(letters) * p.Expansion)); padding > 0 {
+		b.WriteString(" ")
+		p.run(&b, strings.Repeat("~", padding))
+	}
+	if p.Brackets {
+		b.WriteString("]")
+	}
+	return b.String()
+}
+
+// run writes the accented form of a run of text without tokens, and returns
+// the number of its letters.
+func (p Pseudo) run(b *strings.Builder, s string) int {
+	if s == "" {
+		return 0
+	}
+	if p.RTL {
+		b.WriteString(rightToLeftOverride)
+	}
+	letters := 0
+	for _, r := range s {
+		if accented, ok := pseudoAccents[r]; ok {
+			r = accented
+		}
+		if !unicode.IsSpace(r) {
+			letters++
+		}
+		b.WriteRune(r)
+	}
+	if p.RTL {
+		b.WriteString(popDirectionalFormat)
+	}
+	return letters
+}
+
+// IsPseudoLocalization reports whether text may be the output of Localize
+// for source, with any options. Without its brackets, padding and
+// right-to-left marks, text must be the accented source. If the source has
+// changed since, text must still look pseudo-localized: no ASCII letter is
+// left outside its format tokens, and it is bracketed, padded or accented.
+// A translation, such as a Japanese one, is not.
+func IsPseudoLocalization(source, text string) bool {
+	text = strings.NewReplacer(rightToLeftOverride, "", popDirectionalFormat, "").Replace(text)
+	bracketed := len(text) >= 2 && strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]")
+	if bracketed {
+		text = text[1 : len(text)-1]
+	}
+	trimmed := strings.TrimRight(text, "~")
+	padded := len(trimmed) < len(text) && strings.HasSuffix(trimmed, " ")
+	if padded {
+		text = strings.TrimSuffix(trimmed, " ")
+	}
+	if text == (Pseudo{}).Localize(source) {
+		return true
+	}
+
+	letters, accented := false, false
+	for _, r := range tokenRe.ReplaceAllString(text, "") {
+		switch {
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			return false
+		case pseudoAccented[r]:
+			accented = true
+		case unicode.IsLetter(r):
+			letters = true
+		}
+	}
+	return bracketed || padded || accented || !letters
+}
@@ -0,0 +1,69 @@
+package translator
+
+import (
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func TestPseudo_Localize(t *testing.T) {
+	tests := []struct {
+		name   string
+		pseudo Pseudo
+		text   string
+		want   string
+	}{
+		{
+			name:   "accents and brackets",
+			pseudo: Pseudo{Brackets: true},
+			text:   "Save changes",
+			want:   "[Šåṽé çĥåñĝéš]",
+		},
+		{
+			name:   "expansion counts letters only",
+			pseudo: Pseudo{Expansion: 0.5},
+			text:   "Open %@",
+			want:   "Öþéñ %@ ~~",
+		},
+		{
+			name:   "tokens are kept",
+			pseudo: Pseudo{Brackets: true},
+			text:   "%1$@ sent %2$#@photos@ (%.1f%%) to %arg",
+			want:   "[%1$@ šéñţ %2$#@photos@ (%.1f%%) ţö %arg]",
+		},
+		{
+			name:   "right to left",
+			pseudo: Pseudo{RTL: true},
+			text:   "Hi %@!",
+			want:   "\u202EĤî \u202C%@\u202E!\u202C",
+		},
+		{
+			name:   "empty",
+			pseudo: Pseudo{Expansion: 0.3, Brackets: true},
+			text:   "",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test.AssertEqual(t, tt.pseudo.Localize(tt.text), tt.want)
+		})
+	}
+}
+
+func TestIsPseudoLocalization(t *testing.T) {
+	pseudo := Pseudo{Expansion: 0.3, Brackets: true, RTL: true}
+	test.AssertEqual(t, IsPseudoLocalization("%lld files in %@", pseudo.Localize("%lld files in %@")), true)
+	test.AssertEqual(t, IsPseudoLocalization("こんにちは", pseudo.Localize("こんにちは")), true)
+	test.AssertEqual(t, IsPseudoLocalization("こんにちは", Pseudo{}.Localize("こんにちは")), true)
+	// The source changed after the pseudo-locale was generated.
+	test.AssertEqual(t, IsPseudoLocalization("Save all", pseudo.Localize("Save")), true)
+	test.AssertEqual(t, IsPseudoLocalization("こんばんは", pseudo.Localize("こんにちは")), true)
+	test.AssertEqual(t, IsPseudoLocalization("%#@files@", "[%#@files@]"), true)
+	test.AssertEqual(t, IsPseudoLocalization("%lld", "%lld"), true)
+	test.AssertEqual(t, IsPseudoLocalization("%lld files", "%lld Dateien"), false)
+	test.AssertEqual(t, IsPseudoLocalization("Generate", "Générer"), false)
+	test.AssertEqual(t, IsPseudoLocalization("Hello", "こんにちは"), false)
+	test.AssertEqual(t, IsPseudoLocalization("こんにちは", "Hello"), false)
+}
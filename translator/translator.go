@@ -0,0 +1,76 @@
+// Package translator defines the machine translation backends used by the
+// translate command, the protection of format specifiers while text is in a
+// backend's hands, and the pseudo-localization used by the pseudo command.
+package translator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Translator translates a batch of texts from one language to another.
+// Implementations must return exactly one translation per input text, in
+// input order.
+type Translator interface {
+	Translate(ctx context.Context, sourceLanguage, targetLanguage string, texts []string) ([]string, error)
+}
+
+// tokenRe matches everything in a catalog string that must reach the
+// translation unchanged: %#@name@ substitution references, %% escapes,
+// Apple's %arg placeholder, and printf-style conversions (optionally
+// positional, e.g. %1$@).
+var tokenRe = regexp.MustCompile(`%(?:\d+\$)?#@\w+@|%%|%(?:\d+\$)?arg\b|%(?:\d+\$)?[-+ 0#']*\d*(?:\.\d+)?(?:hh|h|ll|l|q|L|z|j|t)?[@dioxXucsfeEgGaAp]`)
+
+// placeholderRe matches the placeholders Protect substitutes for tokens,
+// tolerating the whitespace changes some backends make inside tags.
+var placeholderRe = regexp.MustCompile(`<x\s+id\s*=\s*"(\d+)"\s*/>`)
+
+// Protect replaces every format token in text with an XLIFF-style inline
+// placeholder (<x id="0"/>, <x id="1"/>, ...), which translation services
+// leave untouched, and returns the protected text along with the tokens in
+// placeholder order.
+func Protect(text string) (string, []string) {
+	var tokens []string
+	protected := tokenRe.ReplaceAllStringFunc(text, func(tok string) string {
+		tokens = append(tokens, tok)
+		return `<x id="` + strconv.Itoa(len(tokens)-1) + `"/>`
+	})
+	return protected, tokens
+}
+
+// Restore puts the tokens returned by Protect back into a translated text.
+// It fails when the translation lost, duplicated or invented a placeholder,
+// since the result would no longer match the source's format arguments.
+func Restore(text string, tokens []string) (string, error) {
+	seen := make([]bool, len(tokens))
+	var restoreErr error
+	restored := placeholderRe.ReplaceAllStringFunc(text, func(ph string) string {
+		id, _ := strconv.Atoi(placeholderRe.FindStringSubmatch(ph)[1])
+		if id >= len(tokens) {
+			restoreErr = fmt.Errorf("translation contains unknown placeholder %s", ph)
+			return ph
+		}
+		if seen[id] {
+			restoreErr = fmt.Errorf("translation repeats placeholder for %s", tokens[id])
+			return ph
+		}
+		seen[id] = true
+		return tokens[id]
+	})
+	if restoreErr != nil {
+		return "", restoreErr
+	}
+	var missing []string
+	for i, ok := range seen {
+		if !ok {
+			missing = append(missing, tokens[i])
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("translation dropped %s", strings.Join(missing, ", "))
+	}
+	return restored, nil
+}
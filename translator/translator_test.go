@@ -0,0 +1,72 @@
+package translator
+
+import (
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func TestProtect(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		tokens []string
+	}{
+		{
+			name:   "printf specifiers",
+			text:   "%@ has %lld items (%.1f%%)",
+			want:   `<x id="0"/> has <x id="1"/> items (<x id="2"/><x id="3"/>)`,
+			tokens: []string{"%@", "%lld", "%.1f", "%%"},
+		},
+		{
+			name:   "positional and substitution references",
+			text:   "%2$@ sent %1$#@files@",
+			want:   `<x id="0"/> sent <x id="1"/>`,
+			tokens: []string{"%2$@", "%1$#@files@"},
+		},
+		{
+			name:   "arg placeholder",
+			text:   "%arg files",
+			want:   `<x id="0"/> files`,
+			tokens: []string{"%arg"},
+		},
+		{
+			name: "plain text",
+			text: "Hello",
+			want: "Hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, tokens := Protect(tt.text)
+			test.AssertEqual(t, got, tt.want)
+			test.AssertSliceEqual(t, tokens, tt.tokens)
+
+			restored, err := Restore(got, tokens)
+			test.AssertNoError(t, err)
+			test.AssertEqual(t, restored, tt.text)
+		})
+	}
+}
+
+func TestRestore_ReorderedAndRespaced(t *testing.T) {
+	restored, err := Restore(`<x id = "1" /> の <x id="0"/>`, []string{"%1$@", "%2$@"})
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, restored, "%2$@ の %1$@")
+}
+
+func TestRestore_Errors(t *testing.T) {
+	tests := map[string]string{
+		"dropped":  `<x id="0"/> only`,
+		"repeated": `<x id="0"/> <x id="0"/> <x id="1"/>`,
+		"unknown":  `<x id="0"/> <x id="1"/> <x id="2"/>`,
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Restore(text, []string{"%@", "%d"})
+			test.AssertError(t, err)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package xcstrings
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// CopyLocalization merges source, a localization taken from any key of this
+// or another catalog, into key's localization for language. String units
+// are merged one by one, down through plural and device variations and
+// substitutions: a unit the target lacks is added, and one it has is
+// overwritten. A substitution the target lacks is added with the source's
+// argNum and formatSpecifier. Untranslated source units (empty, or "new")
+// never replace an existing unit.
+//
+// Overwriting a translated unit (one with a value that isn't "new") with a
+// different value is a conflict, as is replacing translated units of a
+// different shape (a plain string where the source varies, or the reverse).
+// Without force, conflicts leave the localization untouched; with force,
+// the source wins.
+//
+// It returns the paths of the copied string units and of the conflicts, in
+// the notation of ScaffoldLocalization, sorted.
+func (x *XCStrings) CopyLocalization(key, language string, source Localization, force bool) ([]string, []string, error) {
+	definition, exists := x.Strings[key]
+	if !exists {
+		return nil, nil, fmt.Errorf("key not found: %s", key)
+	}
+
+	// Work on copies, so that a refused copy changes nothing and the result
+	// shares no string units with source.
+	var target Localization
+	if existing, ok := definition.Localizations[language]; ok {
+		if err := cloneLocalization(existing, &target); err != nil {
+			return nil, nil, err
+		}
+	}
+	var from Localization
+	if err := cloneLocalization(source, &from); err != nil {
+		return nil, nil, err
+	}
+
+	c := copier{force: force}
+	c.localization(from, &target)
+	sort.Strings(c.copied)
+	sort.Strings(c.conflicts)
+	if len(c.copied) == 0 || (len(c.conflicts) > 0 && !force) {
+		return c.copied, c.conflicts, nil
+	}
+
+	if definition.Localizations == nil {
+		definition.Localizations = make(map[string]Localization)
+	}
+	definition.Localizations[language] = target
+	x.Strings[key] = definition
+	return c.copied, c.conflicts, nil
+}
+
+// DeriveLocalization replaces key's localization for language with a copy of
+// the source language's, plural, device and substitution structure
+// included, with every string unit's value passed through transform and
+// marked "translated". A key without a source localization is derived from a
+// plain string holding the key, as Xcode treats keys extracted from code.
+//
+// It returns the number of string units written.
+func (x *XCStrings) DeriveLocalization(key, language string, transform func(string) string) (int, error) {
+	if language == x.SourceLanguage {
+		return 0, fmt.Errorf("%s is the source language", language)
+	}
+	definition, exists := x.Strings[key]
+	if !exists {
+		return 0, fmt.Errorf("key not found: %s", key)
+	}
+
+	source, ok := definition.Localizations[x.SourceLanguage]
+	if !ok {
+		source = Localization{StringUnit: &StringUnit{State: "translated", Value: key}}
+	}
+	var target Localization
+	if err := cloneLocalization(source, &target); err != nil {
+		return 0, err
+	}
+	units := target.AllStringUnits()
+	for _, unit := range units {
+		unit.State = "translated"
+		unit.Value = transform(unit.Value)
+	}
+
+	if definition.Localizations == nil {
+		definition.Localizations = make(map[string]Localization)
+	}
+	definition.Localizations[language] = target
+	x.Strings[key] = definition
+	return len(units), nil
+}
+
+// cloneLocalization deep-copies l into out, unknown properties included.
+func cloneLocalization(l Localization, out *Localization) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+type copier struct {
+	force     bool
+	copied    []string
+	conflicts []string
+}
+
+func (c *copier) localization(source Localization, target *Localization) {
+	c.merge(source.StringUnit, source.Variations, &target.StringUnit, &target.Variations, "")
+
+	names := make([]string, 0, len(source.Substitutions))
+	for name := range source.Substitutions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sourceSub := source.Substitutions[name]
+		sub, ok := target.Substitutions[name]
+		if !ok {
+			sub = Substitution{ArgNum: sourceSub.ArgNum, FormatSpecifier: sourceSub.FormatSpecifier}
+		}
+		c.variations(&sourceSub.Variations, &sub.Variations, "substitutions."+name)
+		if target.Substitutions == nil {
+			target.Substitutions = make(map[string]Substitution)
+		}
+		target.Substitutions[name] = sub
+	}
+}
+
+// merge copies a string unit or variations (whichever the source has) into
+// the target's, which may have either.
+func (c *copier) merge(sourceUnit *StringUnit, sourceVariations *Variations, unit **StringUnit, variations **Variations, path string) {
+	switch {
+	case sourceUnit != nil:
+		if *variations != nil {
+			if !c.replaceable(translatedUnits((*variations).allStringUnits()), path) {
+				return
+			}
+			*variations = nil
+		}
+		*unit = c.unit(sourceUnit, *unit, path)
+	case sourceVariations != nil:
+		if *unit != nil {
+			if !c.replaceable(translatedUnits([]*StringUnit{*unit}), path) {
+				return
+			}
+			*unit = nil
+		}
+		if *variations == nil {
+			*variations = &Variations{}
+		}
+		c.variations(sourceVariations, *variations, path)
+	}
+}
+
+// replaceable reports whether target units of another shape than the
+// source's may be dropped, recording a conflict when they can't.
+func (c *copier) replaceable(translated int, path string) bool {
+	if translated > 0 {
+		c.conflicts = append(c.conflicts, path)
+		return c.force
+	}
+	return true
+}
+
+func (c *copier) variations(source, target *Variations, prefix string) {
+	if len(source.Plural) > 0 && target.Plural == nil {
+		target.Plural = make(map[PluralCategory]*VariationValue)
+	}
+	for category, value := range source.Plural {
+		target.Plural[category] = c.value(value, target.Plural[category], joinPath(prefix, "plural."+category))
+	}
+
+	if len(source.Device) > 0 && target.Device == nil {
+		target.Device = make(map[string]*VariationValue)
+	}
+	for device, value := range source.Device {
+		target.Device[device] = c.value(value, target.Device[device], joinPath(prefix, "device."+device))
+	}
+}
+
+func (c *copier) value(source, target *VariationValue, path string) *VariationValue {
+	if source == nil {
+		return target
+	}
+	if target == nil {
+		target = &VariationValue{}
+	}
+	c.merge(source.StringUnit, source.Variations, &target.StringUnit, &target.Variations, path)
+	return target
+}
+
+func (c *copier) unit(source, target *StringUnit, path string) *StringUnit {
+	if target != nil {
+		if !isTranslated(source) || (source.Value == target.Value && source.State == target.State) {
+			return target
+		}
+		if isTranslated(target) && source.Value != target.Value {
+			c.conflicts = append(c.conflicts, path)
+			if !c.force {
+				return target
+			}
+		}
+	}
+	c.copied = append(c.copied, path)
+	return source
+}
+
+// isTranslated reports whether u holds a translation: a value that isn't
+// still "new".
+func isTranslated(u *StringUnit) bool {
+	return u != nil && u.Value != "" && u.State != "new"
+}
+
+func translatedUnits(units []*StringUnit) int {
+	n := 0
+	for _, u := range units {
+		if isTranslated(u) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,154 @@
+package xcstrings
+
+import (
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+const copyFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"%lld files": {"localizations": {
+			"ja": {
+				"stringUnit": {"state": "translated", "value": "%#@files@"},
+				"substitutions": {
+					"files": {"argNum": 1, "formatSpecifier": "lld", "variations": {"plural": {
+						"other": {"stringUnit": {"state": "translated", "value": "%arg 個のファイル"}}
+					}}}
+				}
+			},
+			"ru": {"variations": {"plural": {
+				"one": {"stringUnit": {"state": "translated", "value": "%lld файл"}},
+				"few": {"stringUnit": {"state": "translated", "value": "%lld файла"}},
+				"other": {"stringUnit": {"state": "new", "value": ""}}
+			}}}
+		}},
+		"count": {"localizations": {
+			"ru": {"variations": {"plural": {
+				"one": {"stringUnit": {"state": "translated", "value": "%lld файл"}},
+				"many": {"stringUnit": {"state": "translated", "value": "%lld файлов"}},
+				"other": {"stringUnit": {"state": "translated", "value": "%lld файла"}}
+			}}},
+			"ja": {"stringUnit": {"state": "translated", "value": "件数"}}
+		}},
+		"empty": {}
+	},
+	"version": "1.0"
+}`
+
+func TestCopyLocalization_NewLocalization(t *testing.T) {
+	xcs := loadScaffoldFixture(t, copyFixture)
+	source := xcs.Strings["%lld files"].Localizations["ja"]
+
+	copied, conflicts, err := xcs.CopyLocalization("empty", "ja", source, false)
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, copied, []string{"", "substitutions.files.plural.other"})
+	test.AssertEqual(t, len(conflicts), 0)
+
+	sub := xcs.Strings["empty"].Localizations["ja"].Substitutions["files"]
+	test.AssertEqual(t, sub.ArgNum, 1)
+	test.AssertEqual(t, sub.FormatSpecifier, "lld")
+	test.AssertEqual(t, sub.Variations.Plural["other"].StringUnit.Value, "%arg 個のファイル")
+
+	// The copy shares nothing with its source.
+	sub.Variations.Plural["other"].StringUnit.Value = "changed"
+	test.AssertEqual(t, xcs.Strings["%lld files"].Localizations["ja"].Substitutions["files"].Variations.Plural["other"].StringUnit.Value, "%arg 個のファイル")
+}
+
+func TestCopyLocalization_MergesVariations(t *testing.T) {
+	xcs := loadScaffoldFixture(t, copyFixture)
+	source := xcs.Strings["count"].Localizations["ru"]
+
+	// one is identical, many is missing and the target's other is untranslated;
+	// none of them conflict. few only exists in the target and is kept.
+	copied, conflicts, err := xcs.CopyLocalization("%lld files", "ru", source, false)
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, copied, []string{"plural.many", "plural.other"})
+	test.AssertEqual(t, len(conflicts), 0)
+
+	plural := xcs.Strings["%lld files"].Localizations["ru"].Variations.Plural
+	test.AssertEqual(t, plural["few"].StringUnit.Value, "%lld файла")
+	test.AssertEqual(t, plural["many"].StringUnit.Value, "%lld файлов")
+	test.AssertEqual(t, plural["other"].StringUnit.Value, "%lld файла")
+	test.AssertEqual(t, plural["other"].StringUnit.State, "translated")
+}
+
+func TestCopyLocalization_Conflicts(t *testing.T) {
+	xcs := loadScaffoldFixture(t, copyFixture)
+
+	// A different translation, and a plain string over substitutions.
+	source := Localization{StringUnit: &StringUnit{State: "translated", Value: "ファイル数"}}
+	copied, conflicts, err := xcs.CopyLocalization("%lld files", "ja", source, false)
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, copied, nil)
+	test.AssertSliceEqual(t, conflicts, []string{""})
+	test.AssertEqual(t, xcs.Strings["%lld files"].Localizations["ja"].StringUnit.Value, "%#@files@")
+
+	// Plural variations over a translated plain string.
+	source = xcs.Strings["count"].Localizations["ru"]
+	_, conflicts, err = xcs.CopyLocalization("count", "ja", source, false)
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, conflicts, []string{""})
+	test.AssertEqual(t, xcs.Strings["count"].Localizations["ja"].StringUnit.Value, "件数")
+
+	// force lets the source win.
+	copied, conflicts, err = xcs.CopyLocalization("count", "ja", source, true)
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, copied, []string{"plural.many", "plural.one", "plural.other"})
+	test.AssertSliceEqual(t, conflicts, []string{""})
+	loc := xcs.Strings["count"].Localizations["ja"]
+	if loc.StringUnit != nil {
+		t.Error("the plain string should have been replaced by the variations")
+	}
+	test.AssertEqual(t, loc.Variations.Plural["many"].StringUnit.Value, "%lld файлов")
+
+	_, _, err = xcs.CopyLocalization("missing", "ja", source, false)
+	test.AssertError(t, err)
+}
+
+func TestDeriveLocalization(t *testing.T) {
+	xcs := loadScaffoldFixture(t, `{
+	"sourceLanguage": "en",
+	"strings": {
+		"%lld files": {"localizations": {
+			"en": {
+				"stringUnit": {"state": "translated", "value": "%#@files@"},
+				"substitutions": {
+					"files": {"argNum": 1, "formatSpecifier": "lld", "variations": {"plural": {
+						"one": {"stringUnit": {"state": "translated", "value": "%arg file"}},
+						"other": {"stringUnit": {"state": "new", "value": "%arg files"}}
+					}}}
+				}
+			},
+			"en-XA": {"stringUnit": {"state": "translated", "value": "outdated"}}
+		}},
+		"Cancel": {}
+	},
+	"version": "1.0"
+}`)
+	upper := func(s string) string { return strings.ToUpper(s) }
+
+	n, err := xcs.DeriveLocalization("%lld files", "en-XA", upper)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, n, 3)
+	derived := xcs.Strings["%lld files"].Localizations["en-XA"]
+	test.AssertEqual(t, derived.StringUnit.Value, "%#@FILES@")
+	files := derived.Substitutions["files"]
+	test.AssertEqual(t, files.ArgNum, 1)
+	test.AssertEqual(t, files.Variations.Plural["other"].StringUnit.Value, "%ARG FILES")
+	test.AssertEqual(t, files.Variations.Plural["other"].StringUnit.State, "translated")
+	// The source is left as it was.
+	test.AssertEqual(t, xcs.Strings["%lld files"].Localizations["en"].Substitutions["files"].Variations.Plural["other"].StringUnit.Value, "%arg files")
+
+	n, err = xcs.DeriveLocalization("Cancel", "en-XA", upper)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, n, 1)
+	test.AssertEqual(t, xcs.Strings["Cancel"].Localizations["en-XA"].StringUnit.Value, "CANCEL")
+
+	_, err = xcs.DeriveLocalization("Cancel", "en", upper)
+	test.AssertError(t, err)
+	_, err = xcs.DeriveLocalization("missing", "en-XA", upper)
+	test.AssertError(t, err)
+}
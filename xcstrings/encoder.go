@@ -0,0 +1,228 @@
+package xcstrings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Encoder writes JSON values using the exact formatting Xcode produces for
+// String Catalogs (Foundation's JSONSerialization with the prettyPrinted and
+// sortedKeys options), so that a catalog loaded and saved by xckit without
+// modification is byte-identical to the file Xcode wrote:
+//   - two-space indentation and `"key" : value` member separators;
+//   - empty objects and arrays written with a blank line between the brackets;
+//   - object members sorted case-insensitively, with runs of digits compared
+//     numerically (exact comparison breaks ties);
+//   - "/" escaped as "\/" and control characters as \n, \t, ... or \uXXXX,
+//     while <, >, & and non-ASCII text are written literally;
+//   - a trailing newline after the closing brace.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns an Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the Xcode-formatted JSON encoding of v, followed by a newline.
+// v is first marshaled with encoding/json, so custom MarshalJSON methods
+// (including the retention of unknown catalog members) are honored.
+func (e *Encoder) Encode(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := writeXcodeValue(&buf, tree, 0); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+
+	_, err = e.w.Write(buf.Bytes())
+	return err
+}
+
+// MarshalXcode returns the catalog encoded with the Xcode formatting
+// described on Encoder.
+func (x *XCStrings) MarshalXcode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(x); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeXcodeIndent(buf *bytes.Buffer, depth int) {
+	for i := 0; i < depth; i++ {
+		buf.WriteString("  ")
+	}
+}
+
+func writeXcodeValue(buf *bytes.Buffer, v any, depth int) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		if val {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case json.Number:
+		buf.WriteString(val.String())
+	case string:
+		writeXcodeString(buf, val)
+	case []any:
+		buf.WriteString("[\n")
+		if len(val) == 0 {
+			buf.WriteByte('\n')
+		}
+		for i, elem := range val {
+			writeXcodeIndent(buf, depth+1)
+			if err := writeXcodeValue(buf, elem, depth+1); err != nil {
+				return err
+			}
+			if i < len(val)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		writeXcodeIndent(buf, depth)
+		buf.WriteByte(']')
+	case map[string]any:
+		buf.WriteString("{\n")
+		if len(val) == 0 {
+			buf.WriteByte('\n')
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, compareXcodeKeys)
+		for i, k := range keys {
+			writeXcodeIndent(buf, depth+1)
+			writeXcodeString(buf, k)
+			buf.WriteString(" : ")
+			if err := writeXcodeValue(buf, val[k], depth+1); err != nil {
+				return err
+			}
+			if i < len(keys)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		writeXcodeIndent(buf, depth)
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported JSON value of type %T", v)
+	}
+	return nil
+}
+
+// writeXcodeString writes s as a quoted JSON string using Foundation's
+// escaping rules.
+func writeXcodeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '/':
+			buf.WriteString(`\/`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// compareXcodeKeys compares a and b case-insensitively, treating runs of ASCII
+// digits as numbers ("item 2" < "item 10"). Keys that compare equal under
+// those rules fall back to an exact comparison so the order is total.
+func compareXcodeKeys(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isASCIIDigit(a[i]) && isASCIIDigit(b[j]) {
+			ai := i
+			for i < len(a) && isASCIIDigit(a[i]) {
+				i++
+			}
+			bj := j
+			for j < len(b) && isASCIIDigit(b[j]) {
+				j++
+			}
+			if c := compareDigitRuns(a[ai:i], b[bj:j]); c != 0 {
+				return c
+			}
+			continue
+		}
+
+		ra, sa := utf8.DecodeRuneInString(a[i:])
+		rb, sb := utf8.DecodeRuneInString(b[j:])
+		la, lb := unicode.ToLower(ra), unicode.ToLower(rb)
+		if la != lb {
+			if la < lb {
+				return -1
+			}
+			return 1
+		}
+		i += sa
+		j += sb
+	}
+
+	switch {
+	case len(a)-i < len(b)-j:
+		return -1
+	case len(a)-i > len(b)-j:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareDigitRuns compares two runs of ASCII digits by numeric value.
+func compareDigitRuns(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,110 @@
+package xcstrings
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func TestSaveToFile_ByteIdenticalToXcode(t *testing.T) {
+	fixtures := []string{
+		"xcode_format.xcstrings",
+		"unknown_fields.xcstrings",
+	}
+
+	for _, name := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			path := test.FixturePath(name)
+			original, err := os.ReadFile(path)
+			test.AssertNoError(t, err)
+
+			xc, err := Load(path)
+			test.AssertNoError(t, err)
+
+			out := filepath.Join(t.TempDir(), name)
+			test.AssertNoError(t, xc.SaveToFile(out))
+
+			saved, err := os.ReadFile(out)
+			test.AssertNoError(t, err)
+
+			if !bytes.Equal(original, saved) {
+				t.Errorf("saved catalog differs from input:\nwant:\n%s\ngot:\n%s", original, saved)
+			}
+		})
+	}
+}
+
+func TestSaveToFile_EditOnlyTouchesChangedLines(t *testing.T) {
+	path := test.FixturePath("xcode_format.xcstrings")
+	original, err := os.ReadFile(path)
+	test.AssertNoError(t, err)
+
+	xc, err := Load(path)
+	test.AssertNoError(t, err)
+	_, err = xc.SetTranslation("account.title", "en", "Your Account", "")
+	test.AssertNoError(t, err)
+
+	out := filepath.Join(t.TempDir(), "edited.xcstrings")
+	test.AssertNoError(t, xc.SaveToFile(out))
+	saved, err := os.ReadFile(out)
+	test.AssertNoError(t, err)
+
+	want := bytes.Replace(original, []byte(`"value" : "Account"`), []byte(`"value" : "Your Account"`), 1)
+	if !bytes.Equal(want, saved) {
+		t.Errorf("edit rewrote more than the changed value:\nwant:\n%s\ngot:\n%s", want, saved)
+	}
+}
+
+func TestEncoder_Formatting(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			name:  "object members use spaced colon and two-space indent",
+			value: map[string]any{"b": 1, "a": map[string]any{"c": true}},
+			want:  "{\n  \"a\" : {\n    \"c\" : true\n  },\n  \"b\" : 1\n}\n",
+		},
+		{
+			name:  "empty object",
+			value: map[string]any{"a": map[string]any{}},
+			want:  "{\n  \"a\" : {\n\n  }\n}\n",
+		},
+		{
+			name:  "arrays",
+			value: map[string]any{"a": []any{1, "x"}, "b": []any{}},
+			want:  "{\n  \"a\" : [\n    1,\n    \"x\"\n  ],\n  \"b\" : [\n\n  ]\n}\n",
+		},
+		{
+			name:  "escaping",
+			value: "a/b \"q\" \\ \n\t\r\b\f \x01 <&> é 日本 😀",
+			want:  "\"a\\/b \\\"q\\\" \\\\ \\n\\t\\r\\b\\f \\u0001 <&> é 日本 😀\"\n",
+		},
+		{
+			name:  "null and false",
+			value: map[string]any{"a": nil, "b": false},
+			want:  "{\n  \"a\" : null,\n  \"b\" : false\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			test.AssertNoError(t, NewEncoder(&buf).Encode(tt.value))
+			test.AssertEqual(t, buf.String(), tt.want)
+		})
+	}
+}
+
+func TestCompareXcodeKeys(t *testing.T) {
+	keys := []string{"Zoom", "item 10", "b", "", "item 2", "About", "B", "account", "item 02", "a1", "A"}
+	slices.SortFunc(keys, compareXcodeKeys)
+
+	want := []string{"", "A", "a1", "About", "account", "B", "b", "item 02", "item 2", "item 10", "Zoom"}
+	test.AssertSliceEqual(t, keys, want)
+}
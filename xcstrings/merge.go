@@ -0,0 +1,211 @@
+package xcstrings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MergeConflict is a leaf of the catalog changed differently on both sides
+// of a three-way merge. Key and Language are empty for top-level and
+// key-level properties; Path is the variation path within the localization
+// (e.g. "plural.one" or "substitutions.files.plural.other", "" for the plain
+// string unit) or the name of the conflicting property (e.g. "comment").
+// Ours and Theirs render the two sides, "(deleted)" for a removed leaf.
+type MergeConflict struct {
+	Key      string
+	Language string
+	Path     string
+	Ours     string
+	Theirs   string
+}
+
+// Merge three-way merges catalog contents the way a line-based merge would,
+// but per key, per language and per variation leaf: a change made on one
+// side only is taken, and changes on both sides are merged member by member
+// down to the string units. Keys, languages and variations added on either
+// side are kept, and a removal is honored when the other side left the
+// removed part unchanged. Members xckit doesn't model are merged the same
+// way.
+//
+// Only a leaf changed differently on both sides is a conflict. A string unit
+// or comment changed on both sides gets a value holding both versions between
+// git-style conflict markers, and the unit is marked needs_review, so the
+// merged file is still a valid catalog Xcode can open; any other conflicting
+// leaf keeps our side. Every conflict is returned so the caller can report
+// it.
+//
+// An empty base is read as an empty catalog, as git passes for a file added
+// on both sides. The merged catalog is returned with Xcode's formatting.
+func Merge(base, ours, theirs []byte) ([]byte, []MergeConflict, error) {
+	var trees [3]any
+	for i, data := range [][]byte{base, ours, theirs} {
+		if i == 0 && len(bytes.TrimSpace(data)) == 0 {
+			trees[i] = map[string]any{}
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		name := [3]string{"base", "ours", "theirs"}[i]
+		if err := dec.Decode(&trees[i]); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		if _, ok := trees[i].(map[string]any); !ok {
+			return nil, nil, fmt.Errorf("failed to parse %s: not a string catalog", name)
+		}
+	}
+
+	m := &merger{}
+	merged, _ := m.merge(nil, trees[0], trees[1], trees[2], true, true, true)
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(merged); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), m.conflicts, nil
+}
+
+type merger struct {
+	conflicts []MergeConflict
+}
+
+// merge returns the merged value at path and whether it is present.
+func (m *merger) merge(path []string, base, ours, theirs any, hasBase, hasOurs, hasTheirs bool) (any, bool) {
+	if sameValue(ours, theirs, hasOurs, hasTheirs) {
+		return ours, hasOurs
+	}
+	if sameValue(base, ours, hasBase, hasOurs) {
+		return theirs, hasTheirs
+	}
+	if sameValue(base, theirs, hasBase, hasTheirs) {
+		return ours, hasOurs
+	}
+
+	ourObject, ok1 := ours.(map[string]any)
+	theirObject, ok2 := theirs.(map[string]any)
+	if ok1 && ok2 && !isMergeLeaf(path) {
+		baseObject, _ := base.(map[string]any)
+		return m.mergeObjects(path, baseObject, ourObject, theirObject), true
+	}
+	return m.conflict(path, ours, theirs, hasOurs, hasTheirs)
+}
+
+func (m *merger) mergeObjects(path []string, base, ours, theirs map[string]any) map[string]any {
+	names := make(map[string]bool)
+	for _, object := range []map[string]any{base, ours, theirs} {
+		for name := range object {
+			names[name] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	merged := make(map[string]any)
+	for _, name := range sorted {
+		b, hasBase := base[name]
+		o, hasOurs := ours[name]
+		t, hasTheirs := theirs[name]
+		if value, ok := m.merge(append(path[:len(path):len(path)], name), b, o, t, hasBase, hasOurs, hasTheirs); ok {
+			merged[name] = value
+		}
+	}
+	return merged
+}
+
+// conflict records a conflict at path and returns its resolution: the
+// changed side of a removal, conflict markers for a string unit or comment,
+// otherwise our side.
+func (m *merger) conflict(path []string, ours, theirs any, hasOurs, hasTheirs bool) (any, bool) {
+	c := mergeConflictAt(path)
+	c.Ours = renderMergeValue(ours, hasOurs)
+	c.Theirs = renderMergeValue(theirs, hasTheirs)
+	m.conflicts = append(m.conflicts, c)
+
+	if !hasOurs {
+		// Removed on our side, changed on theirs: keep their change.
+		return theirs, true
+	}
+	s1, ok1 := ours.(string)
+	s2, ok2 := theirs.(string)
+	if ok1 && ok2 && len(path) > 0 && path[len(path)-1] == "comment" {
+		return conflictMarkers(s1, s2), true
+	}
+	u1, ok1 := ours.(map[string]any)
+	u2, ok2 := theirs.(map[string]any)
+	if ok1 && ok2 && isMergeLeaf(path) {
+		unit := make(map[string]any, len(u1))
+		for name, value := range u1 {
+			unit[name] = value
+		}
+		v1, _ := u1["value"].(string)
+		v2, _ := u2["value"].(string)
+		unit["value"] = conflictMarkers(v1, v2)
+		unit["state"] = "needs_review"
+		return unit, true
+	}
+	return ours, true
+}
+
+// isMergeLeaf reports whether the object at path is merged as a whole: a
+// string unit's state and value belong together.
+func isMergeLeaf(path []string) bool {
+	return len(path) > 0 && path[len(path)-1] == "stringUnit"
+}
+
+func sameValue(a, b any, hasA, hasB bool) bool {
+	return hasA == hasB && (!hasA || reflect.DeepEqual(a, b))
+}
+
+func conflictMarkers(ours, theirs string) string {
+	return "<<<<<<< ours\n" + ours + "\n=======\n" + theirs + "\n>>>>>>> theirs"
+}
+
+// mergeConflictAt locates a conflict from its JSON path, e.g. strings >
+// greeting > localizations > ja > variations > plural > one > stringUnit.
+func mergeConflictAt(path []string) MergeConflict {
+	var c MergeConflict
+	rest := path
+	if len(rest) >= 2 && rest[0] == "strings" {
+		c.Key, rest = rest[1], rest[2:]
+		if len(rest) >= 2 && rest[0] == "localizations" {
+			c.Language, rest = rest[1], rest[2:]
+		}
+	}
+	var segments []string
+	for _, segment := range rest {
+		if segment != "variations" && segment != "stringUnit" {
+			segments = append(segments, segment)
+		}
+	}
+	c.Path = strings.Join(segments, ".")
+	return c
+}
+
+// renderMergeValue renders one side of a conflict: a string unit or string
+// as its text, a larger part of the catalog as "(changed)", anything else as
+// JSON.
+func renderMergeValue(v any, present bool) string {
+	if !present {
+		return "(deleted)"
+	}
+	if object, ok := v.(map[string]any); ok {
+		if value, ok := object["value"].(string); ok {
+			return value
+		}
+		return "(changed)"
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,131 @@
+package xcstrings
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+const mergeBase = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"greeting": {"comment": "Shown on launch", "localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Hello"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}}
+		}},
+		"item_count": {"localizations": {
+			"en": {"variations": {"plural": {
+				"one": {"stringUnit": {"state": "translated", "value": "%lld item"}},
+				"other": {"stringUnit": {"state": "translated", "value": "%lld items"}}
+			}}}
+		}},
+		"old": {"localizations": {}}
+	},
+	"version": "1.0"
+}`
+
+func mergeCatalogs(t *testing.T, base, ours, theirs string) (*XCStrings, []MergeConflict) {
+	t.Helper()
+	merged, conflicts, err := Merge([]byte(base), []byte(ours), []byte(theirs))
+	test.AssertNoError(t, err)
+	var xcs XCStrings
+	test.AssertNoError(t, json.Unmarshal(merged, &xcs))
+	return &xcs, conflicts
+}
+
+// edit returns mergeBase with each old string replaced by its new one.
+func edit(t *testing.T, pairs ...string) string {
+	t.Helper()
+	content := mergeBase
+	for i := 0; i < len(pairs); i += 2 {
+		if !strings.Contains(content, pairs[i]) {
+			t.Fatalf("fixture has no %q", pairs[i])
+		}
+		content = strings.Replace(content, pairs[i], pairs[i+1], 1)
+	}
+	return content
+}
+
+func TestMerge_NonOverlappingChanges(t *testing.T) {
+	ours := edit(t,
+		// A new key and a new plural form.
+		`"old": {"localizations": {}}`, `"old": {"localizations": {}}, "added_ours": {"localizations": {}}`,
+		`"one": {"stringUnit": {"state": "translated", "value": "%lld item"}},`,
+		`"zero": {"stringUnit": {"state": "translated", "value": "No items"}}, "one": {"stringUnit": {"state": "translated", "value": "%lld item"}},`,
+	)
+	theirs := edit(t,
+		// Another new key, a French translation, a new comment and a removed key.
+		`"old": {"localizations": {}}`, `"added_theirs": {"localizations": {}}`,
+		`"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}}`,
+		`"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}}, "fr": {"stringUnit": {"state": "translated", "value": "Bonjour"}}`,
+		`"Shown on launch"`, `"Shown on the home screen"`,
+	)
+
+	xcs, conflicts := mergeCatalogs(t, mergeBase, ours, theirs)
+	test.AssertEqual(t, len(conflicts), 0)
+	keys := xcs.Keys()
+	sort.Strings(keys)
+	test.AssertSliceEqual(t, keys, []string{"added_ours", "added_theirs", "greeting", "item_count"})
+	test.AssertEqual(t, xcs.Strings["greeting"].Comment, "Shown on the home screen")
+	test.AssertEqual(t, xcs.Strings["greeting"].Localizations["fr"].StringUnit.Value, "Bonjour")
+	test.AssertEqual(t, xcs.Strings["item_count"].Localizations["en"].Variations.Plural["zero"].StringUnit.Value, "No items")
+}
+
+func TestMerge_SameLeafConflicts(t *testing.T) {
+	ours := edit(t,
+		`"value": "こんにちは"`, `"value": "やあ"`,
+		`"Shown on launch"`, `"Greeting"`,
+		`"value": "%lld items"`, `"value": "%lld things"`,
+	)
+	theirs := edit(t,
+		`"value": "こんにちは"`, `"value": "どうも"`,
+		`"Shown on launch"`, `"Welcome"`,
+		// The same change on both sides isn't a conflict.
+		`"value": "%lld items"`, `"value": "%lld things"`,
+		`"old": {"localizations": {}}`, `"old": {"extractionState": "stale", "localizations": {}}`,
+	)
+
+	xcs, conflicts := mergeCatalogs(t, mergeBase, ours, theirs)
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %+v", conflicts)
+	}
+	test.AssertEqual(t, conflicts[0], MergeConflict{Key: "greeting", Path: "comment", Ours: "Greeting", Theirs: "Welcome"})
+	test.AssertEqual(t, conflicts[1], MergeConflict{Key: "greeting", Language: "ja", Ours: "やあ", Theirs: "どうも"})
+
+	greeting := xcs.Strings["greeting"]
+	test.AssertEqual(t, greeting.Comment, "<<<<<<< ours\nGreeting\n=======\nWelcome\n>>>>>>> theirs")
+	ja := greeting.Localizations["ja"].StringUnit
+	test.AssertEqual(t, ja.Value, "<<<<<<< ours\nやあ\n=======\nどうも\n>>>>>>> theirs")
+	test.AssertEqual(t, ja.State, "needs_review")
+	test.AssertEqual(t, xcs.Strings["item_count"].Localizations["en"].Variations.Plural["other"].StringUnit.Value, "%lld things")
+	test.AssertEqual(t, xcs.Strings["old"].ExtractionState, "stale")
+}
+
+func TestMerge_RemovedAndChanged(t *testing.T) {
+	ours := edit(t, `"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}}`, `"ja": {"stringUnit": {"state": "needs_review", "value": "こんにちは"}}`)
+	theirs := edit(t, `,
+			"ja": {"stringUnit": {"state": "translated", "value": "こんにちは"}}`, ``)
+
+	xcs, conflicts := mergeCatalogs(t, mergeBase, ours, theirs)
+	test.AssertEqual(t, len(conflicts), 1)
+	test.AssertEqual(t, conflicts[0].Theirs, "(deleted)")
+	// The changed side survives.
+	test.AssertEqual(t, xcs.Strings["greeting"].Localizations["ja"].StringUnit.State, "needs_review")
+}
+
+func TestMerge_EmptyBaseAndOutput(t *testing.T) {
+	merged, conflicts, err := Merge(nil, []byte(mergeBase), []byte(mergeBase))
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, len(conflicts), 0)
+	if !strings.HasPrefix(string(merged), "{\n  \"sourceLanguage\" : \"en\",\n") {
+		t.Errorf("expected Xcode formatting, got:\n%s", merged)
+	}
+
+	_, _, err = Merge([]byte(mergeBase), []byte("[]"), []byte(mergeBase))
+	test.AssertError(t, err)
+	_, _, err = Merge([]byte(mergeBase), []byte(mergeBase), []byte("{"))
+	test.AssertError(t, err)
+}
@@ -0,0 +1,363 @@
+package xcstrings
+
+import (
+	"slices"
+	"strings"
+)
+
+// cldrPluralGroups is the CLDR (v46) cardinal plural rules database, reduced
+// to the categories each language uses and grouped the way plurals.xml
+// groups languages sharing a rule set. Regional variants (pt-PT, es-419,
+// zh-Hant, ...) use the same categories as their base language.
+var cldrPluralGroups = []struct {
+	categories string
+	languages  string
+}{
+	{"other", "bm bo dz hnj id ig ii in ja jbo jv jw kde kea km ko lkt lo ms my nqo osa root sah ses sg su th to tpi vi wo yo yue zh"},
+	{"one other", "af am an as asa ast az bal bem bez bg bho bn brx ce ceb cgg chr ckb csw da de doi dv ee el en eo et eu fa ff fi fil fo fur fy gl gsw gu guw ha haw hi hu hy ia io is jgo ji jmc ka kab kaj kcg kk kkj kl kn ks ksb ku ky lb lg lij ln mas mg mgo mk ml mn mr nah nb nd ne nl nn nnh no nr nso ny nyn om or os pa pap pcm ps rm rof rwk saq sc sd sdh seh si sn so sq ss ssy st sv sw syr ta te teo ti tig tk tl tn tr ts tzm ug ur uz ve vo vun wa wae xh xog yi zu"},
+	{"zero one other", "blo ksh lag lv prg"},
+	{"one two other", "he iu iw naq sat se sma smi smj smn sms"},
+	{"one few other", "bs hr mo ro sh shi sr"},
+	{"one many other", "ca es fr it lld pt scn vec"},
+	{"one two few other", "dsb gd hsb sl"},
+	{"one few many other", "be cs lt pl ru sk uk"},
+	{"one two few many other", "br ga gv mt"},
+	{"zero one two few many other", "ar ars cy kw"},
+}
+
+var pluralCategoriesByLanguage = func() map[string][]PluralCategory {
+	m := make(map[string][]PluralCategory)
+	for _, group := range cldrPluralGroups {
+		categories := strings.Fields(group.categories)
+		for _, lang := range strings.Fields(group.languages) {
+			m[lang] = categories
+		}
+	}
+	return m
+}()
+
+// RequiredPluralCategories returns the CLDR plural categories a plural
+// variation in language must provide, in canonical order (zero, one, two,
+// few, many, other). Language is a catalog language code such as "ru",
+// "pt-BR" or "zh-Hans". ok is false when the language is not in the
+// database.
+func RequiredPluralCategories(language string) (categories []PluralCategory, ok bool) {
+	categories, ok = pluralCategoriesByLanguage[baseLanguage(language)]
+	return slices.Clone(categories), ok
+}
+
+// compactManyLanguages select "many" only for exact multiples of a million
+// ("1 000 000 de fichiers") and compact or exponent forms (1M, 1e6).
+var compactManyLanguages = strings.Fields("ca es fr it lld pt scn vec")
+
+// PluralCategoryRare reports whether language selects category only for
+// exact multiples of a million and compact numbers, as "many" in French,
+// so that a plural variation without it is right for nearly every count.
+func PluralCategoryRare(language string, category PluralCategory) bool {
+	return category == "many" && slices.Contains(compactManyLanguages, baseLanguage(language))
+}
+
+// baseLanguage returns language without its region or script, lowercased.
+func baseLanguage(language string) string {
+	base := strings.ToLower(language)
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
+
+// PluralCategoryUsed reports whether category can ever be selected in
+// language. An explicit "zero" case is honored for the number 0 in every
+// language on Apple platforms, so it always counts as used. Unknown
+// languages accept every category.
+func PluralCategoryUsed(language string, category PluralCategory) bool {
+	if category == "zero" {
+		return true
+	}
+	categories, ok := RequiredPluralCategories(language)
+	return !ok || slices.Contains(categories, category)
+}
+
+// cldrIntegerRules are the CLDR (v46) cardinal plural rules, as they apply
+// to integers (CLDR operands v, f and t are 0), for the languages of
+// cldrPluralGroups that don't select "one" for exactly 1 and "other"
+// otherwise. Languages whose only category is "other" aren't listed either.
+var cldrIntegerRules = []struct {
+	languages string
+	rule      func(n int64) PluralCategory
+}{
+	{"am as bn doi fa gu hi kn pcm zu ff hy kab bho csw guw ln mg nso pa ti wa si", func(n int64) PluralCategory {
+		return pick(n <= 1, "one")
+	}},
+	{"is mk", func(n int64) PluralCategory {
+		return pick(n%10 == 1 && n%100 != 11, "one")
+	}},
+	{"ceb fil tl", func(n int64) PluralCategory {
+		return pick(n <= 3 || (n%10 != 4 && n%10 != 6 && n%10 != 9), "one")
+	}},
+	{"tzm", func(n int64) PluralCategory {
+		return pick(n <= 1 || (n >= 11 && n <= 99), "one")
+	}},
+	{"lv prg", func(n int64) PluralCategory {
+		switch {
+		case n%10 == 0 || (n%100 >= 11 && n%100 <= 19):
+			return "zero"
+		case n%10 == 1:
+			return "one"
+		}
+		return "other"
+	}},
+	{"blo ksh lag", func(n int64) PluralCategory {
+		switch n {
+		case 0:
+			return "zero"
+		case 1:
+			return "one"
+		}
+		return "other"
+	}},
+	{"he iu iw naq sat se sma smi smj smn sms", func(n int64) PluralCategory {
+		switch n {
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		}
+		return "other"
+	}},
+	{"bs hr sh sr", func(n int64) PluralCategory {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "other"
+	}},
+	{"mo ro", func(n int64) PluralCategory {
+		switch {
+		case n == 1:
+			return "one"
+		case n == 0 || (n%100 >= 1 && n%100 <= 19):
+			return "few"
+		}
+		return "other"
+	}},
+	{"shi", func(n int64) PluralCategory {
+		switch {
+		case n <= 1:
+			return "one"
+		case n <= 10:
+			return "few"
+		}
+		return "other"
+	}},
+	{"fr pt", func(n int64) PluralCategory {
+		switch {
+		case n <= 1:
+			return "one"
+		case n%1000000 == 0:
+			return "many"
+		}
+		return "other"
+	}},
+	{"ca es it lld scn vec pt-pt", func(n int64) PluralCategory {
+		switch {
+		case n == 1:
+			return "one"
+		case n != 0 && n%1000000 == 0:
+			return "many"
+		}
+		return "other"
+	}},
+	{"dsb hsb sl", func(n int64) PluralCategory {
+		switch n % 100 {
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		case 3, 4:
+			return "few"
+		}
+		return "other"
+	}},
+	{"gd", func(n int64) PluralCategory {
+		switch {
+		case n == 1 || n == 11:
+			return "one"
+		case n == 2 || n == 12:
+			return "two"
+		case n >= 3 && n <= 19:
+			return "few"
+		}
+		return "other"
+	}},
+	{"be ru uk", func(n int64) PluralCategory {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+	}},
+	{"pl", func(n int64) PluralCategory {
+		switch {
+		case n == 1:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+	}},
+	{"cs sk", func(n int64) PluralCategory {
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		}
+		return "other"
+	}},
+	{"lt", func(n int64) PluralCategory {
+		switch {
+		case n%100 >= 11 && n%100 <= 19:
+			return "other"
+		case n%10 == 1:
+			return "one"
+		case n%10 >= 2:
+			return "few"
+		}
+		return "other"
+	}},
+	{"br", func(n int64) PluralCategory {
+		tens := n % 100 / 10
+		switch {
+		case n%10 == 1 && n%100 != 11 && n%100 != 71 && n%100 != 91:
+			return "one"
+		case n%10 == 2 && n%100 != 12 && n%100 != 72 && n%100 != 92:
+			return "two"
+		case (n%10 == 3 || n%10 == 4 || n%10 == 9) && tens != 1 && tens != 7 && tens != 9:
+			return "few"
+		case n != 0 && n%1000000 == 0:
+			return "many"
+		}
+		return "other"
+	}},
+	{"ga", func(n int64) PluralCategory {
+		switch {
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n >= 3 && n <= 6:
+			return "few"
+		case n >= 7 && n <= 10:
+			return "many"
+		}
+		return "other"
+	}},
+	{"gv", func(n int64) PluralCategory {
+		switch {
+		case n%10 == 1:
+			return "one"
+		case n%10 == 2:
+			return "two"
+		case n%100%20 == 0:
+			return "few"
+		}
+		return "other"
+	}},
+	{"mt", func(n int64) PluralCategory {
+		switch {
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n == 0 || (n%100 >= 3 && n%100 <= 10):
+			return "few"
+		case n%100 >= 11 && n%100 <= 19:
+			return "many"
+		}
+		return "other"
+	}},
+	{"ar ars", func(n int64) PluralCategory {
+		switch {
+		case n <= 2:
+			return []PluralCategory{"zero", "one", "two"}[n]
+		case n%100 >= 3 && n%100 <= 10:
+			return "few"
+		case n%100 >= 11:
+			return "many"
+		}
+		return "other"
+	}},
+	{"cy", func(n int64) PluralCategory {
+		switch n {
+		case 0:
+			return "zero"
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		case 3:
+			return "few"
+		case 6:
+			return "many"
+		}
+		return "other"
+	}},
+	{"kw", func(n int64) PluralCategory {
+		switch {
+		case n <= 1:
+			return []PluralCategory{"zero", "one"}[n]
+		case n%100 == 2 || n%100 == 22 || n%100 == 42 || n%100 == 62 || n%100 == 82 ||
+			(n%1000 == 0 && (n%100000 >= 1000 && n%100000 <= 20000 || n%100000 == 40000 || n%100000 == 60000 || n%100000 == 80000)) ||
+			n%1000000 == 100000:
+			return "two"
+		case n%100 == 3 || n%100 == 23 || n%100 == 43 || n%100 == 63 || n%100 == 83:
+			return "few"
+		case n%100 == 1 || n%100 == 21 || n%100 == 41 || n%100 == 61 || n%100 == 81:
+			return "many"
+		}
+		return "other"
+	}},
+}
+
+var integerRulesByLanguage = func() map[string]func(int64) PluralCategory {
+	m := make(map[string]func(int64) PluralCategory)
+	for _, r := range cldrIntegerRules {
+		for _, lang := range strings.Fields(r.languages) {
+			m[lang] = r.rule
+		}
+	}
+	return m
+}()
+
+func pick(match bool, category PluralCategory) PluralCategory {
+	if match {
+		return category
+	}
+	return "other"
+}
+
+// PluralCategoryOf returns the CLDR plural category language selects for
+// the integer n, as Foundation does when it picks a plural variation. A
+// language not in the database uses "other".
+func PluralCategoryOf(language string, n int64) PluralCategory {
+	if n < 0 {
+		n = -n
+	}
+	full := strings.ReplaceAll(strings.ToLower(language), "_", "-")
+	if rule, ok := integerRulesByLanguage[full]; ok {
+		return rule(n)
+	}
+	base := baseLanguage(language)
+	if rule, ok := integerRulesByLanguage[base]; ok {
+		return rule(n)
+	}
+	if categories := pluralCategoriesByLanguage[base]; slices.Contains(categories, "one") {
+		return pick(n == 1, "one")
+	}
+	return "other"
+}
@@ -0,0 +1,100 @@
+package xcstrings
+
+import (
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func TestRequiredPluralCategories(t *testing.T) {
+	tests := []struct {
+		language string
+		want     []string
+	}{
+		{"ja", []string{"other"}},
+		{"en", []string{"one", "other"}},
+		{"fr", []string{"one", "many", "other"}},
+		{"ru", []string{"one", "few", "many", "other"}},
+		{"ar", []string{"zero", "one", "two", "few", "many", "other"}},
+		{"he", []string{"one", "two", "other"}},
+		// Regional variants and scripts fall back to the base language.
+		{"pt-BR", []string{"one", "many", "other"}},
+		{"zh-Hans", []string{"other"}},
+		{"sr_Latn", []string{"one", "few", "other"}},
+		{"EN-GB", []string{"one", "other"}},
+	}
+	for _, tt := range tests {
+		got, ok := RequiredPluralCategories(tt.language)
+		if !ok {
+			t.Errorf("RequiredPluralCategories(%q): language not found", tt.language)
+			continue
+		}
+		test.AssertSliceEqual(t, got, tt.want)
+	}
+
+	_, ok := RequiredPluralCategories("tlh")
+	test.AssertEqual(t, ok, false)
+
+	// The result is a copy; modifying it must not affect later lookups.
+	got, _ := RequiredPluralCategories("en")
+	got[0] = "few"
+	got, _ = RequiredPluralCategories("en")
+	test.AssertSliceEqual(t, got, []string{"one", "other"})
+}
+
+func TestPluralCategoryUsed(t *testing.T) {
+	test.AssertEqual(t, PluralCategoryUsed("ja", "one"), false)
+	test.AssertEqual(t, PluralCategoryUsed("ja", "other"), true)
+	test.AssertEqual(t, PluralCategoryUsed("ru", "few"), true)
+	test.AssertEqual(t, PluralCategoryUsed("en", "two"), false)
+	// An explicit zero case is honored in every language.
+	test.AssertEqual(t, PluralCategoryUsed("en", "zero"), true)
+	// Unknown languages accept every category.
+	test.AssertEqual(t, PluralCategoryUsed("tlh", "many"), true)
+}
+
+func TestPluralCategoryRare(t *testing.T) {
+	test.AssertEqual(t, PluralCategoryRare("fr", "many"), true)
+	test.AssertEqual(t, PluralCategoryRare("pt-BR", "many"), true)
+	test.AssertEqual(t, PluralCategoryRare("fr", "one"), false)
+	test.AssertEqual(t, PluralCategoryRare("ru", "many"), false)
+}
+
+func TestPluralCategoryOf(t *testing.T) {
+	tests := []struct {
+		language string
+		counts   []int64
+		want     []string
+	}{
+		{"en", []int64{0, 1, 2, 11}, []string{"other", "one", "other", "other"}},
+		{"ja", []int64{0, 1, 2}, []string{"other", "other", "other"}},
+		{"fr", []int64{0, 1, 2, 1000000}, []string{"one", "one", "other", "many"}},
+		{"pt-PT", []int64{0, 1, 2}, []string{"other", "one", "other"}},
+		{"pt-BR", []int64{0, 1, 2}, []string{"one", "one", "other"}},
+		{"ru", []int64{1, 2, 5, 11, 21, 22, 112}, []string{"one", "few", "many", "many", "one", "few", "many"}},
+		{"pl", []int64{1, 21, 22, 25}, []string{"one", "many", "few", "many"}},
+		{"ar", []int64{0, 1, 2, 3, 11, 100, 103}, []string{"zero", "one", "two", "few", "many", "other", "few"}},
+		{"ro", []int64{0, 1, 2, 20, 101}, []string{"few", "one", "few", "other", "few"}},
+		{"lv", []int64{0, 1, 11, 21}, []string{"zero", "one", "zero", "one"}},
+		{"he", []int64{1, 2, 20}, []string{"one", "two", "other"}},
+		{"sr_Latn", []int64{1, 3, 13}, []string{"one", "few", "other"}},
+		{"tlh", []int64{1}, []string{"other"}},
+	}
+	for _, tt := range tests {
+		for i, n := range tt.counts {
+			if got := PluralCategoryOf(tt.language, n); got != tt.want[i] {
+				t.Errorf("PluralCategoryOf(%q, %d) = %q, want %q", tt.language, n, got, tt.want[i])
+			}
+		}
+	}
+
+	// Every language selects only categories it has.
+	for language := range pluralCategoriesByLanguage {
+		for n := int64(0); n < 2000; n++ {
+			if category := PluralCategoryOf(language, n); !PluralCategoryUsed(language, category) {
+				t.Errorf("PluralCategoryOf(%q, %d) = %q, which %s doesn't use", language, n, category, language)
+				break
+			}
+		}
+	}
+}
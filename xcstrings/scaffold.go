@@ -0,0 +1,173 @@
+package xcstrings
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// ScaffoldLocalization gives key a localization for language shaped like the
+// source language's: a plain string unit, the same device variations, and a
+// plural variation for every plural category language needs under its CLDR
+// rules (plus an explicit "zero" case when the source has one). Every
+// substitution is created as well, with its argNum and formatSpecifier copied
+// from the source. New string units are marked "new" and hold the source
+// text (the source's "other" form for a category the source doesn't have),
+// so the app shows the source text, rather than nothing, until they are
+// translated.
+//
+// Existing parts of the localization are kept; only the missing ones are
+// added, so scaffolding also completes a partially translated key. A part
+// whose shape conflicts with the source (a plain string where the source
+// varies, or the reverse) is left alone. A key without a source localization
+// is scaffolded as a plain string holding the key, as Xcode treats keys
+// extracted from code.
+//
+// It returns the paths of the added string units ("" for the plain string,
+// otherwise e.g. "plural.few" or "substitutions.files.plural.one"), sorted.
+func (x *XCStrings) ScaffoldLocalization(key, language string) ([]string, error) {
+	if language == x.SourceLanguage {
+		return nil, fmt.Errorf("%s is the source language", language)
+	}
+	definition, exists := x.Strings[key]
+	if !exists {
+		return nil, fmt.Errorf("key not found: %s", key)
+	}
+
+	source, ok := definition.Localizations[x.SourceLanguage]
+	if !ok {
+		source = Localization{StringUnit: &StringUnit{State: "translated", Value: key}}
+	}
+	target := definition.Localizations[language]
+
+	s := scaffolder{language: language}
+	s.localization(source, &target)
+	if len(s.added) == 0 {
+		return nil, nil
+	}
+
+	if definition.Localizations == nil {
+		definition.Localizations = make(map[string]Localization)
+	}
+	definition.Localizations[language] = target
+	x.Strings[key] = definition
+	sort.Strings(s.added)
+	return s.added, nil
+}
+
+type scaffolder struct {
+	language string
+	added    []string
+}
+
+func (s *scaffolder) unit(source *StringUnit, path string) *StringUnit {
+	s.added = append(s.added, path)
+	value := ""
+	if source != nil {
+		value = source.Value
+	}
+	return &StringUnit{State: "new", Value: value}
+}
+
+func (s *scaffolder) localization(source Localization, target *Localization) {
+	switch {
+	case source.StringUnit != nil && target.StringUnit == nil && target.Variations == nil:
+		target.StringUnit = s.unit(source.StringUnit, "")
+	case source.Variations != nil && target.StringUnit == nil:
+		if target.Variations == nil {
+			target.Variations = &Variations{}
+		}
+		s.variations(source.Variations, target.Variations, "")
+	}
+
+	names := make([]string, 0, len(source.Substitutions))
+	for name := range source.Substitutions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sub, ok := target.Substitutions[name]
+		if !ok {
+			sub = Substitution{
+				ArgNum:          source.Substitutions[name].ArgNum,
+				FormatSpecifier: source.Substitutions[name].FormatSpecifier,
+			}
+		}
+		sourceVariations := source.Substitutions[name].Variations
+		s.variations(&sourceVariations, &sub.Variations, "substitutions."+name)
+		if target.Substitutions == nil {
+			target.Substitutions = make(map[string]Substitution)
+		}
+		target.Substitutions[name] = sub
+	}
+}
+
+func (s *scaffolder) variations(source, target *Variations, prefix string) {
+	if source.Plural != nil {
+		if target.Plural == nil {
+			target.Plural = make(map[PluralCategory]*VariationValue)
+		}
+		for _, category := range s.pluralCategories(source.Plural) {
+			template := source.Plural[category]
+			if template == nil {
+				template = source.Plural["other"]
+			}
+			if value := s.value(template, target.Plural[category], joinPath(prefix, "plural."+category)); value != nil {
+				target.Plural[category] = value
+			}
+		}
+	}
+
+	if source.Device != nil {
+		if target.Device == nil {
+			target.Device = make(map[string]*VariationValue)
+		}
+		for device, template := range source.Device {
+			target.Device[device] = s.value(template, target.Device[device], joinPath(prefix, "device."+device))
+		}
+	}
+}
+
+// value returns target completed from template, or a new value shaped like
+// template when target is nil.
+func (s *scaffolder) value(template, target *VariationValue, path string) *VariationValue {
+	if template == nil {
+		return target
+	}
+	if target == nil {
+		target = &VariationValue{}
+	}
+	switch {
+	case template.Variations != nil && target.StringUnit == nil:
+		if target.Variations == nil {
+			target.Variations = &Variations{}
+		}
+		s.variations(template.Variations, target.Variations, path)
+	case template.Variations == nil && target.StringUnit == nil && target.Variations == nil:
+		target.StringUnit = s.unit(template.StringUnit, path)
+	}
+	return target
+}
+
+// pluralCategories returns the categories to scaffold for a source plural
+// variation: those the language requires, plus any of the source's that the
+// language also uses (an explicit "zero"), in canonical order. A language
+// without known plural rules gets the source's categories.
+func (s *scaffolder) pluralCategories(source map[PluralCategory]*VariationValue) []PluralCategory {
+	required, ok := RequiredPluralCategories(s.language)
+	var categories []PluralCategory
+	for _, category := range ValidPluralCategories {
+		_, inSource := source[category]
+		if (ok && slices.Contains(required, category)) || (inSource && PluralCategoryUsed(s.language, category)) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+func joinPath(prefix, segment string) string {
+	if prefix == "" {
+		return segment
+	}
+	return prefix + "." + segment
+}
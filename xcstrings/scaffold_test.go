@@ -0,0 +1,151 @@
+package xcstrings
+
+import (
+	"testing"
+
+	"xckit/helper/test"
+)
+
+func loadScaffoldFixture(t *testing.T, content string) *XCStrings {
+	t.Helper()
+	xcs, err := Load(test.TempFile(t, "test.xcstrings", content))
+	test.AssertNoError(t, err)
+	return xcs
+}
+
+const scaffoldFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"%lld files in %lld folders": {"localizations": {
+			"en": {
+				"stringUnit": {"state": "translated", "value": "%#@files@ in %#@folders@"},
+				"substitutions": {
+					"files": {"argNum": 1, "formatSpecifier": "lld", "variations": {"plural": {
+						"one": {"stringUnit": {"state": "translated", "value": "%arg file"}},
+						"other": {"stringUnit": {"state": "translated", "value": "%arg files"}}
+					}}},
+					"folders": {"argNum": 2, "formatSpecifier": "lld", "variations": {"plural": {
+						"other": {"stringUnit": {"state": "translated", "value": "%arg folders"}}
+					}}}
+				}
+			}
+		}},
+		"%lld photos": {"localizations": {
+			"en": {"variations": {"device": {
+				"mac": {"variations": {"plural": {
+					"zero": {"stringUnit": {"state": "translated", "value": "No photos"}},
+					"one": {"stringUnit": {"state": "translated", "value": "%lld photo"}},
+					"other": {"stringUnit": {"state": "translated", "value": "%lld photos"}}
+				}}},
+				"other": {"stringUnit": {"state": "translated", "value": "Photos"}}
+			}}},
+			"ru": {"variations": {"device": {
+				"mac": {"variations": {"plural": {
+					"one": {"stringUnit": {"state": "translated", "value": "%lld фото"}}
+				}}}
+			}}}
+		}},
+		"Done": {}
+	},
+	"version": "1.0"
+}`
+
+func TestScaffoldLocalization_Substitutions(t *testing.T) {
+	xcs := loadScaffoldFixture(t, scaffoldFixture)
+
+	paths, err := xcs.ScaffoldLocalization("%lld files in %lld folders", "ru")
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, paths, []string{
+		"",
+		"substitutions.files.plural.few",
+		"substitutions.files.plural.many",
+		"substitutions.files.plural.one",
+		"substitutions.files.plural.other",
+		"substitutions.folders.plural.few",
+		"substitutions.folders.plural.many",
+		"substitutions.folders.plural.one",
+		"substitutions.folders.plural.other",
+	})
+
+	loc := xcs.Strings["%lld files in %lld folders"].Localizations["ru"]
+	test.AssertEqual(t, loc.StringUnit.State, "new")
+	test.AssertEqual(t, loc.StringUnit.Value, "%#@files@ in %#@folders@")
+	files := loc.Substitutions["files"]
+	test.AssertEqual(t, files.ArgNum, 1)
+	test.AssertEqual(t, files.FormatSpecifier, "lld")
+	test.AssertEqual(t, files.Variations.Plural["one"].StringUnit.Value, "%arg file")
+	// Categories English doesn't have start from its "other" form.
+	test.AssertEqual(t, files.Variations.Plural["few"].StringUnit.Value, "%arg files")
+	test.AssertEqual(t, files.Variations.Plural["few"].StringUnit.State, "new")
+	test.AssertEqual(t, loc.Substitutions["folders"].ArgNum, 2)
+
+	// A second run has nothing left to add.
+	paths, err = xcs.ScaffoldLocalization("%lld files in %lld folders", "ru")
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, len(paths), 0)
+}
+
+func TestScaffoldLocalization_SubstitutionsCopyTheSource(t *testing.T) {
+	// Another language disagreeing with the source doesn't matter: the
+	// scaffold mirrors the source, whatever the map order.
+	content := `{"sourceLanguage": "en", "strings": {"%lld files": {"localizations": {
+		"en": {"stringUnit": {"state": "translated", "value": "%#@files@"}, "substitutions": {
+			"files": {"argNum": 1, "formatSpecifier": "lld", "variations": {"plural": {"other": {"stringUnit": {"state": "translated", "value": "%arg files"}}}}}
+		}},
+		"de": {"stringUnit": {"state": "translated", "value": "%#@files@"}, "substitutions": {
+			"files": {"argNum": 2, "formatSpecifier": "d", "variations": {"plural": {"other": {"stringUnit": {"state": "translated", "value": "%arg Dateien"}}}}}
+		}},
+		"fr": {"stringUnit": {"state": "translated", "value": "%#@files@"}, "substitutions": {
+			"files": {"argNum": 3, "formatSpecifier": "ld", "variations": {"plural": {"other": {"stringUnit": {"state": "translated", "value": "%arg fichiers"}}}}}
+		}}
+	}}}, "version": "1.0"}`
+	for range 10 {
+		xcs := loadScaffoldFixture(t, content)
+		_, err := xcs.ScaffoldLocalization("%lld files", "ja")
+		test.AssertNoError(t, err)
+		files := xcs.Strings["%lld files"].Localizations["ja"].Substitutions["files"]
+		test.AssertEqual(t, files.ArgNum, 1)
+		test.AssertEqual(t, files.FormatSpecifier, "lld")
+	}
+}
+
+func TestScaffoldLocalization_CompletesExistingVariations(t *testing.T) {
+	xcs := loadScaffoldFixture(t, scaffoldFixture)
+
+	paths, err := xcs.ScaffoldLocalization("%lld photos", "ru")
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, paths, []string{
+		"device.mac.plural.few",
+		"device.mac.plural.many",
+		"device.mac.plural.other",
+		"device.mac.plural.zero",
+		"device.other",
+	})
+
+	mac := xcs.Strings["%lld photos"].Localizations["ru"].Variations.Device["mac"].Variations.Plural
+	// The existing translation is kept.
+	test.AssertEqual(t, mac["one"].StringUnit.State, "translated")
+	test.AssertEqual(t, mac["one"].StringUnit.Value, "%lld фото")
+	test.AssertEqual(t, mac["zero"].StringUnit.Value, "No photos")
+
+	// Japanese needs only "other", plus the source's explicit zero case.
+	paths, err = xcs.ScaffoldLocalization("%lld photos", "ja")
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, paths, []string{"device.mac.plural.other", "device.mac.plural.zero", "device.other"})
+}
+
+func TestScaffoldLocalization_KeyWithoutSourceLocalization(t *testing.T) {
+	xcs := loadScaffoldFixture(t, scaffoldFixture)
+
+	paths, err := xcs.ScaffoldLocalization("Done", "fr")
+	test.AssertNoError(t, err)
+	test.AssertSliceEqual(t, paths, []string{""})
+	unit := xcs.Strings["Done"].Localizations["fr"].StringUnit
+	test.AssertEqual(t, unit.State, "new")
+	test.AssertEqual(t, unit.Value, "Done")
+
+	_, err = xcs.ScaffoldLocalization("Done", "en")
+	test.AssertError(t, err)
+	_, err = xcs.ScaffoldLocalization("missing", "fr")
+	test.AssertError(t, err)
+}
@@ -0,0 +1,140 @@
+package xcstrings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Extract returns a new catalog with x's sourceLanguage, version and other
+// top-level properties, holding the given keys. With remove set, the keys
+// are moved out of x rather than copied. Keys x doesn't have are ignored.
+func (x *XCStrings) Extract(keys []string, remove bool) *XCStrings {
+	extracted := &XCStrings{
+		SourceLanguage: x.SourceLanguage,
+		Strings:        make(map[string]StringDefinition, len(keys)),
+		Version:        x.Version,
+		unknown:        x.unknown,
+	}
+	for _, key := range keys {
+		definition, exists := x.Strings[key]
+		if !exists {
+			continue
+		}
+		extracted.Strings[key] = definition
+		if remove {
+			delete(x.Strings, key)
+		}
+	}
+	return extracted
+}
+
+// ConflictPolicy decides what MergeCatalog does with a key both catalogs
+// define differently.
+type ConflictPolicy string
+
+const (
+	// ConflictError reports the key as a conflict.
+	ConflictError ConflictPolicy = "error"
+	// ConflictKeepFirst keeps the receiving catalog's definition.
+	ConflictKeepFirst ConflictPolicy = "first"
+	// ConflictKeepLast replaces the definition with the other catalog's.
+	ConflictKeepLast ConflictPolicy = "last"
+	// ConflictMergeUnits merges the localizations as CopyLocalization does,
+	// reporting the string units translated differently as conflicts.
+	ConflictMergeUnits ConflictPolicy = "merge"
+)
+
+// ConflictPolicies lists the valid ConflictPolicy values.
+var ConflictPolicies = []ConflictPolicy{ConflictError, ConflictKeepFirst, ConflictKeepLast, ConflictMergeUnits}
+
+// CatalogMergeResult lists what MergeCatalog did with each key of the other
+// catalog that x didn't already define identically, sorted by key.
+type CatalogMergeResult struct {
+	Added    []string
+	Kept     []string
+	Replaced []string
+	Merged   []string
+	// Conflicts are keys ("key", for ConflictError) or string units
+	// ("key[path] (language)", for ConflictMergeUnits) left unresolved.
+	Conflicts []string
+}
+
+// MergeCatalog adds other's keys to x, resolving keys both define
+// differently with policy. The catalogs must have the same source language.
+// When the result has conflicts, x may be partly merged and should be
+// discarded.
+func (x *XCStrings) MergeCatalog(other *XCStrings, policy ConflictPolicy) (CatalogMergeResult, error) {
+	var result CatalogMergeResult
+	if x.SourceLanguage != other.SourceLanguage {
+		return result, fmt.Errorf("source languages differ: %s and %s", x.SourceLanguage, other.SourceLanguage)
+	}
+
+	keys := other.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		theirs := other.Strings[key]
+		ours, exists := x.Strings[key]
+		if !exists {
+			x.Strings[key] = theirs
+			result.Added = append(result.Added, key)
+			continue
+		}
+		same, err := sameDefinition(ours, theirs)
+		if err != nil {
+			return result, err
+		}
+		if same {
+			continue
+		}
+
+		switch policy {
+		case ConflictKeepFirst:
+			result.Kept = append(result.Kept, key)
+		case ConflictKeepLast:
+			x.Strings[key] = theirs
+			result.Replaced = append(result.Replaced, key)
+		case ConflictMergeUnits:
+			if ours.Comment == "" {
+				ours.Comment = theirs.Comment
+				x.Strings[key] = ours
+			}
+			languages := make([]string, 0, len(theirs.Localizations))
+			for lang := range theirs.Localizations {
+				languages = append(languages, lang)
+			}
+			sort.Strings(languages)
+			for _, lang := range languages {
+				_, conflicts, err := x.CopyLocalization(key, lang, theirs.Localizations[lang], false)
+				if err != nil {
+					return result, err
+				}
+				for _, path := range conflicts {
+					label := key
+					if path != "" {
+						label += "[" + path + "]"
+					}
+					result.Conflicts = append(result.Conflicts, label+" ("+lang+")")
+				}
+			}
+			result.Merged = append(result.Merged, key)
+		default:
+			result.Conflicts = append(result.Conflicts, key)
+		}
+	}
+	return result, nil
+}
+
+// sameDefinition reports whether a and b would be written identically.
+func sameDefinition(a, b StringDefinition) (bool, error) {
+	dataA, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	dataB, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(dataA, dataB), nil
+}
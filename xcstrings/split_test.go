@@ -0,0 +1,106 @@
+package xcstrings
+
+import (
+	"sort"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+const splitFixture = `{
+	"sourceLanguage": "en",
+	"strings": {
+		"settings.title": {"comment": "Title", "localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Settings"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "設定"}}
+		}},
+		"settings.done": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Done"}}
+		}},
+		"home.title": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Home"}}
+		}}
+	},
+	"version": "1.1",
+	"generator": "custom"
+}`
+
+func TestExtract(t *testing.T) {
+	xcs := loadScaffoldFixture(t, splitFixture)
+
+	extracted := xcs.Extract([]string{"settings.title", "settings.done", "missing"}, true)
+	test.AssertEqual(t, extracted.SourceLanguage, "en")
+	test.AssertEqual(t, extracted.Version, "1.1")
+	test.AssertEqual(t, string(extracted.unknown["generator"]), `"custom"`)
+	test.AssertEqual(t, len(extracted.Strings), 2)
+	test.AssertEqual(t, extracted.Strings["settings.title"].Localizations["ja"].StringUnit.Value, "設定")
+	test.AssertSliceEqual(t, xcs.Keys(), []string{"home.title"})
+
+	kept := loadScaffoldFixture(t, splitFixture)
+	kept.Extract([]string{"home.title"}, false)
+	test.AssertEqual(t, len(kept.Strings), 3)
+}
+
+func TestMergeCatalog_Policies(t *testing.T) {
+	other := `{
+	"sourceLanguage": "en",
+	"strings": {
+		"settings.title": {"comment": "Title", "localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Settings"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "設定"}}
+		}},
+		"settings.done": {"comment": "Closes settings", "localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Done"}},
+			"ja": {"stringUnit": {"state": "translated", "value": "完了"}}
+		}},
+		"home.title": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Start"}}
+		}},
+		"profile.title": {"localizations": {
+			"en": {"stringUnit": {"state": "translated", "value": "Profile"}}
+		}}
+	},
+	"version": "1.0"
+}`
+
+	for _, tc := range []struct {
+		policy    ConflictPolicy
+		want      CatalogMergeResult
+		homeTitle string
+	}{
+		{ConflictError, CatalogMergeResult{Added: []string{"profile.title"}, Conflicts: []string{"home.title", "settings.done"}}, "Home"},
+		{ConflictKeepFirst, CatalogMergeResult{Added: []string{"profile.title"}, Kept: []string{"home.title", "settings.done"}}, "Home"},
+		{ConflictKeepLast, CatalogMergeResult{Added: []string{"profile.title"}, Replaced: []string{"home.title", "settings.done"}}, "Start"},
+		{ConflictMergeUnits, CatalogMergeResult{Added: []string{"profile.title"}, Merged: []string{"home.title", "settings.done"}, Conflicts: []string{"home.title (en)"}}, "Home"},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			xcs := loadScaffoldFixture(t, splitFixture)
+			result, err := xcs.MergeCatalog(loadScaffoldFixture(t, other), tc.policy)
+			test.AssertNoError(t, err)
+			test.AssertSliceEqual(t, result.Added, tc.want.Added)
+			test.AssertSliceEqual(t, result.Kept, tc.want.Kept)
+			test.AssertSliceEqual(t, result.Replaced, tc.want.Replaced)
+			test.AssertSliceEqual(t, result.Merged, tc.want.Merged)
+			test.AssertSliceEqual(t, result.Conflicts, tc.want.Conflicts)
+
+			keys := xcs.Keys()
+			sort.Strings(keys)
+			test.AssertSliceEqual(t, keys, []string{"home.title", "profile.title", "settings.done", "settings.title"})
+			test.AssertEqual(t, xcs.Strings["home.title"].Localizations["en"].StringUnit.Value, tc.homeTitle)
+		})
+	}
+
+	// merge fills in the missing translation and comment.
+	xcs := loadScaffoldFixture(t, splitFixture)
+	_, err := xcs.MergeCatalog(loadScaffoldFixture(t, other), ConflictMergeUnits)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, xcs.Strings["settings.done"].Comment, "Closes settings")
+	test.AssertEqual(t, xcs.Strings["settings.done"].Localizations["ja"].StringUnit.Value, "完了")
+}
+
+func TestMergeCatalog_SourceLanguageMismatch(t *testing.T) {
+	xcs := loadScaffoldFixture(t, splitFixture)
+	other := loadScaffoldFixture(t, `{"sourceLanguage": "ja", "strings": {}, "version": "1.0"}`)
+	_, err := xcs.MergeCatalog(other, ConflictKeepLast)
+	test.AssertError(t, err)
+}
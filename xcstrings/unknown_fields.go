@@ -0,0 +1,227 @@
+package xcstrings
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// The catalog types below only model the properties xckit understands. Xcode
+// writes others (e.g. isCommentAutoGenerated on a string definition) and may
+// add more in future releases, so every level of the model keeps the JSON
+// members it does not recognize and writes them back unchanged on save.
+
+// UnmarshalJSON decodes the catalog, retaining unrecognized top-level members.
+func (x *XCStrings) UnmarshalJSON(data []byte) error {
+	type plain XCStrings
+	var p plain
+	unknown, err := decodeObject(data, &p)
+	if err != nil {
+		return err
+	}
+	*x = XCStrings(p)
+	x.unknown = unknown
+	return nil
+}
+
+// MarshalJSON encodes the catalog together with any retained unknown members.
+func (x XCStrings) MarshalJSON() ([]byte, error) {
+	type plain XCStrings
+	return encodeObject(plain(x), x.unknown)
+}
+
+// UnmarshalJSON decodes a string definition, retaining unrecognized members.
+func (d *StringDefinition) UnmarshalJSON(data []byte) error {
+	type plain StringDefinition
+	var p plain
+	unknown, err := decodeObject(data, &p)
+	if err != nil {
+		return err
+	}
+	*d = StringDefinition(p)
+	d.unknown = unknown
+	d.localizationsAbsent = p.Localizations == nil
+	return nil
+}
+
+// MarshalJSON encodes a string definition together with any retained unknown
+// members. Xcode writes definitions without localizations as an empty object
+// ("key" : {}), so an empty localizations map is only written when the
+// definition was not loaded that way; a nil map is never written.
+func (d StringDefinition) MarshalJSON() ([]byte, error) {
+	type plain StringDefinition
+	if (d.localizationsAbsent || d.Localizations == nil) && len(d.Localizations) == 0 {
+		return encodeObject(plain(d), d.unknown, "localizations")
+	}
+	return encodeObject(plain(d), d.unknown)
+}
+
+// UnmarshalJSON decodes a variation value, retaining unrecognized members.
+func (v *VariationValue) UnmarshalJSON(data []byte) error {
+	type plain VariationValue
+	var p plain
+	unknown, err := decodeObject(data, &p)
+	if err != nil {
+		return err
+	}
+	*v = VariationValue(p)
+	v.unknown = unknown
+	return nil
+}
+
+// MarshalJSON encodes a variation value together with any retained unknown members.
+func (v VariationValue) MarshalJSON() ([]byte, error) {
+	type plain VariationValue
+	return encodeObject(plain(v), v.unknown)
+}
+
+// UnmarshalJSON decodes a variations object, retaining unrecognized members
+// (variation kinds other than plural and device).
+func (v *Variations) UnmarshalJSON(data []byte) error {
+	type plain Variations
+	var p plain
+	unknown, err := decodeObject(data, &p)
+	if err != nil {
+		return err
+	}
+	*v = Variations(p)
+	v.unknown = unknown
+	return nil
+}
+
+// MarshalJSON encodes a variations object together with any retained unknown members.
+func (v Variations) MarshalJSON() ([]byte, error) {
+	type plain Variations
+	return encodeObject(plain(v), v.unknown)
+}
+
+// UnmarshalJSON decodes a substitution, retaining unrecognized members.
+func (s *Substitution) UnmarshalJSON(data []byte) error {
+	type plain Substitution
+	var p plain
+	unknown, err := decodeObject(data, &p)
+	if err != nil {
+		return err
+	}
+	*s = Substitution(p)
+	s.unknown = unknown
+	return nil
+}
+
+// MarshalJSON encodes a substitution together with any retained unknown members.
+func (s Substitution) MarshalJSON() ([]byte, error) {
+	type plain Substitution
+	return encodeObject(plain(s), s.unknown)
+}
+
+// UnmarshalJSON decodes a localization, retaining unrecognized members.
+func (l *Localization) UnmarshalJSON(data []byte) error {
+	type plain Localization
+	var p plain
+	unknown, err := decodeObject(data, &p)
+	if err != nil {
+		return err
+	}
+	*l = Localization(p)
+	l.unknown = unknown
+	return nil
+}
+
+// MarshalJSON encodes a localization together with any retained unknown members.
+func (l Localization) MarshalJSON() ([]byte, error) {
+	type plain Localization
+	return encodeObject(plain(l), l.unknown)
+}
+
+// UnmarshalJSON decodes a string unit, retaining unrecognized members.
+func (u *StringUnit) UnmarshalJSON(data []byte) error {
+	type plain StringUnit
+	var p plain
+	unknown, err := decodeObject(data, &p)
+	if err != nil {
+		return err
+	}
+	*u = StringUnit(p)
+	u.unknown = unknown
+	return nil
+}
+
+// MarshalJSON encodes a string unit together with any retained unknown members.
+func (u StringUnit) MarshalJSON() ([]byte, error) {
+	type plain StringUnit
+	return encodeObject(plain(u), u.unknown)
+}
+
+// decodeObject unmarshals the JSON object in data into v (a pointer to a
+// struct without custom JSON methods) and returns the members whose names do
+// not match any of v's json tags, or nil when every member was recognized.
+func decodeObject(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	var unknown map[string]json.RawMessage
+	for name, raw := range members {
+		if known[name] {
+			continue
+		}
+		if unknown == nil {
+			unknown = make(map[string]json.RawMessage)
+		}
+		unknown[name] = raw
+	}
+	return unknown, nil
+}
+
+// encodeObject marshals v (a struct without custom JSON methods), drops the
+// modeled members named in omit, and merges the unknown members back in.
+// Modeled fields always take precedence over an unknown member of the same
+// name.
+func encodeObject(v any, unknown map[string]json.RawMessage, omit ...string) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(unknown) == 0 && len(omit) == 0 {
+		return data, nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for _, name := range omit {
+		delete(members, name)
+	}
+	for name, raw := range unknown {
+		if _, exists := members[name]; !exists {
+			members[name] = raw
+		}
+	}
+	return json.Marshal(members)
+}
+
+// jsonFieldNames returns the set of JSON member names declared by the json
+// tags of struct type t. Fields tagged "-" are excluded.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("json")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		names[name] = true
+	}
+	return names
+}
@@ -0,0 +1,115 @@
+package xcstrings
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"xckit/helper/test"
+)
+
+// decodeGeneric decodes JSON into untyped values, keeping numbers as their
+// literal text so that e.g. 2.50 and 2.5 are not considered equal.
+func decodeGeneric(t *testing.T, data []byte) any {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("failed to decode JSON: %v", err)
+	}
+	return v
+}
+
+func TestUnknownFields_RoundTrip(t *testing.T) {
+	path := test.FixturePath("unknown_fields.xcstrings")
+	original, err := os.ReadFile(path)
+	test.AssertNoError(t, err)
+
+	xc, err := Load(path)
+	test.AssertNoError(t, err)
+
+	out := filepath.Join(t.TempDir(), "out.xcstrings")
+	test.AssertNoError(t, xc.SaveToFile(out))
+
+	saved, err := os.ReadFile(out)
+	test.AssertNoError(t, err)
+
+	if !reflect.DeepEqual(decodeGeneric(t, original), decodeGeneric(t, saved)) {
+		t.Errorf("round trip lost or changed data:\noriginal:\n%s\nsaved:\n%s", original, saved)
+	}
+}
+
+func TestUnknownFields_RetainedAtEveryLevel(t *testing.T) {
+	xc, err := Load(test.FixturePath("unknown_fields.xcstrings"))
+	test.AssertNoError(t, err)
+
+	assertUnknown := func(name string, unknown map[string]json.RawMessage, member, want string) {
+		t.Helper()
+		got, ok := unknown[member]
+		if !ok {
+			t.Errorf("%s: expected unknown member %q to be retained", name, member)
+			return
+		}
+		var compact bytes.Buffer
+		test.AssertNoError(t, json.Compact(&compact, got))
+		test.AssertEqual(t, compact.String(), want)
+	}
+
+	assertUnknown("catalog", xc.unknown, "futureTopLevelKey", `{"enabled":true}`)
+
+	greeting := xc.Strings["greeting"]
+	assertUnknown("definition", greeting.unknown, "isCommentAutoGenerated", `true`)
+	ja := greeting.Localizations["ja"]
+	assertUnknown("localization", ja.unknown, "futureLocalizationKey", `[1,2.50,"three"]`)
+	assertUnknown("stringUnit", ja.StringUnit.unknown, "futureUnitKey", `{"nested":null}`)
+
+	itemCount := xc.Strings["item_count"]
+	assertUnknown("definition", itemCount.unknown, "generatesSymbol", `false`)
+	sub := itemCount.Localizations["en"].Substitutions["count"]
+	assertUnknown("substitution", sub.unknown, "futureSubstitutionKey", `"kept"`)
+	assertUnknown("variations", sub.Variations.unknown, "futureVariationKind", `{"x":{"stringUnit":{"state":"translated","value":"%arg things"}}}`)
+	assertUnknown("variationValue", sub.Variations.Plural["one"].unknown, "futureVariationValueKey", `1e3`)
+
+	if _, ok := greeting.unknown["comment"]; ok {
+		t.Error("modeled members must not be retained as unknown")
+	}
+}
+
+func TestUnknownFields_SurviveEdits(t *testing.T) {
+	xc, err := Load(test.FixturePath("unknown_fields.xcstrings"))
+	test.AssertNoError(t, err)
+
+	_, err = xc.SetTranslation("greeting", "fr", "Bonjour", "")
+	test.AssertNoError(t, err)
+	test.AssertNoError(t, xc.SetComment("greeting", "Updated"))
+
+	out := filepath.Join(t.TempDir(), "out.xcstrings")
+	test.AssertNoError(t, xc.SaveToFile(out))
+
+	reloaded, err := Load(out)
+	test.AssertNoError(t, err)
+
+	greeting := reloaded.Strings["greeting"]
+	test.AssertEqual(t, greeting.Comment, "Updated")
+	test.AssertEqual(t, string(greeting.unknown["isCommentAutoGenerated"]), "true")
+	test.AssertEqual(t, greeting.Localizations["fr"].StringUnit.Value, "Bonjour")
+	if _, ok := greeting.Localizations["ja"].unknown["futureLocalizationKey"]; !ok {
+		t.Error("unknown member of an untouched localization was dropped")
+	}
+}
+
+func TestUnknownFields_ModeledFieldWinsOnConflict(t *testing.T) {
+	unit := StringUnit{
+		State:   "translated",
+		Value:   "Hello",
+		unknown: map[string]json.RawMessage{"value": json.RawMessage(`"stale"`), "extra": json.RawMessage(`1`)},
+	}
+
+	data, err := json.Marshal(unit)
+	test.AssertNoError(t, err)
+	test.AssertEqual(t, string(data), `{"extra":1,"state":"translated","value":"Hello"}`)
+}
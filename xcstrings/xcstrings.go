@@ -3,19 +3,25 @@ package xcstrings
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 
 	"xckit/helper/atomicwrite"
 )
 
 // XCStrings represents the structure of an Xcode String Catalog file.
+// Properties not modeled here are retained at every level of the model and
+// written back unchanged by SaveToFile (see unknown_fields.go).
 type XCStrings struct {
 	SourceLanguage string                      `json:"sourceLanguage"`
 	Strings        map[string]StringDefinition `json:"strings"`
 	Version        string                      `json:"version"`
+
+	unknown map[string]json.RawMessage
 }
 
 // StringDefinition represents a string definition within an XCStrings file.
@@ -24,6 +30,9 @@ type StringDefinition struct {
 	ExtractionState string                  `json:"extractionState,omitempty"`
 	Localizations   map[string]Localization `json:"localizations"`
 	ShouldTranslate *bool                   `json:"shouldTranslate,omitempty"`
+
+	unknown             map[string]json.RawMessage
+	localizationsAbsent bool
 }
 
 // PluralCategory represents a CLDR plural category (zero, one, two, few, many, other).
@@ -34,12 +43,16 @@ type PluralCategory = string
 type VariationValue struct {
 	StringUnit *StringUnit `json:"stringUnit,omitempty"`
 	Variations *Variations `json:"variations,omitempty"`
+
+	unknown map[string]json.RawMessage
 }
 
 // Variations represents device and/or plural variations for a localization.
 type Variations struct {
 	Plural map[PluralCategory]*VariationValue `json:"plural,omitempty"`
 	Device map[string]*VariationValue         `json:"device,omitempty"`
+
+	unknown map[string]json.RawMessage
 }
 
 // Substitution represents a substitution within a localized string.
@@ -47,6 +60,8 @@ type Substitution struct {
 	ArgNum          int        `json:"argNum"`
 	FormatSpecifier string     `json:"formatSpecifier"`
 	Variations      Variations `json:"variations"`
+
+	unknown map[string]json.RawMessage
 }
 
 // Localization represents localization data for a specific language.
@@ -54,12 +69,16 @@ type Localization struct {
 	StringUnit    *StringUnit             `json:"stringUnit,omitempty"`
 	Variations    *Variations             `json:"variations,omitempty"`
 	Substitutions map[string]Substitution `json:"substitutions,omitempty"`
+
+	unknown map[string]json.RawMessage
 }
 
 // StringUnit represents a string unit with translation state and value.
 type StringUnit struct {
 	State string `json:"state"`
 	Value string `json:"value"`
+
+	unknown map[string]json.RawMessage
 }
 
 // AllStringUnits recursively collects all leaf StringUnit pointers from a Localization.
@@ -131,10 +150,12 @@ func Load(path string) (*XCStrings, error) {
 }
 
 // SaveToFile writes the XCStrings data to a file at the given path using atomic writes.
-// It writes to a temporary file in the same directory, syncs to disk, then renames
-// to the target path to prevent data corruption from interrupted writes.
+// The output uses Xcode's own formatting (see Encoder) so that saving does not
+// churn lines Xcode would write identically. It writes to a temporary file in
+// the same directory, syncs to disk, then renames to the target path to
+// prevent data corruption from interrupted writes.
 func (x *XCStrings) SaveToFile(path string) error {
-	data, err := json.MarshalIndent(x, "", "  ")
+	data, err := x.MarshalXcode()
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
@@ -327,6 +348,61 @@ func (x *XCStrings) RemoveKey(key string) bool {
 	return true
 }
 
+// RenameKeys moves each key of renames (old name to new name) to its new
+// name, with its localizations, comment, extraction state and every other
+// property. The renames are applied together, so keys may shift or swap
+// names (a to b and b to c). Nothing is renamed when an old key doesn't
+// exist, a new name is empty, two keys get the same new name, or a new name
+// is taken by a key that isn't renamed itself; the error lists every such
+// problem.
+func (x *XCStrings) RenameKeys(renames map[string]string) error {
+	var problems []string
+	targets := make(map[string]string, len(renames))
+	for _, from := range sortedRenameKeys(renames) {
+		to := renames[from]
+		if _, exists := x.Strings[from]; !exists {
+			problems = append(problems, fmt.Sprintf("key '%s' does not exist", from))
+			continue
+		}
+		if to == "" {
+			problems = append(problems, fmt.Sprintf("key '%s' would be renamed to an empty key", from))
+			continue
+		}
+		if other, taken := targets[to]; taken {
+			problems = append(problems, fmt.Sprintf("keys '%s' and '%s' would both be renamed to '%s'", other, from, to))
+			continue
+		}
+		targets[to] = from
+		if _, exists := x.Strings[to]; exists && to != from {
+			if _, movesAway := renames[to]; !movesAway {
+				problems = append(problems, fmt.Sprintf("key '%s' can't be renamed to '%s', which already exists", from, to))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+
+	definitions := make(map[string]StringDefinition, len(renames))
+	for from := range renames {
+		definitions[from] = x.Strings[from]
+		delete(x.Strings, from)
+	}
+	for from, to := range renames {
+		x.Strings[to] = definitions[from]
+	}
+	return nil
+}
+
+func sortedRenameKeys(renames map[string]string) []string {
+	keys := make([]string, 0, len(renames))
+	for key := range renames {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // VariationOptions specifies which variation path to set a translation on.
 type VariationOptions struct {
 	Plural string // CLDR plural category: zero, one, two, few, many, other
@@ -384,6 +384,57 @@ func TestXCStrings_RemoveKey(t *testing.T) {
 	test.AssertEqual(t, x.RemoveKey("absent"), false)
 }
 
+func TestXCStrings_RenameKeys(t *testing.T) {
+	shouldTranslate := false
+	x := &XCStrings{
+		SourceLanguage: "en",
+		Strings: map[string]StringDefinition{
+			"a": {Comment: "A", ExtractionState: "manual", ShouldTranslate: &shouldTranslate, Localizations: map[string]Localization{
+				"ja": {StringUnit: &StringUnit{State: "translated", Value: "エー"}},
+			}},
+			"b": {Comment: "B"},
+			"c": {Comment: "C"},
+			"e": {Comment: "E"},
+		},
+	}
+	sortedKeys := func() []string {
+		keys := x.Keys()
+		sort.Strings(keys)
+		return keys
+	}
+
+	// a and b shift along: b's name is free once b moves to d.
+	test.AssertNoError(t, x.RenameKeys(map[string]string{"a": "b", "b": "d"}))
+	test.AssertSliceEqual(t, sortedKeys(), []string{"b", "c", "d", "e"})
+	renamed := x.Strings["b"]
+	test.AssertEqual(t, renamed.Comment, "A")
+	test.AssertEqual(t, renamed.ExtractionState, "manual")
+	test.AssertEqual(t, *renamed.ShouldTranslate, false)
+	test.AssertEqual(t, renamed.Localizations["ja"].StringUnit.Value, "エー")
+	test.AssertEqual(t, x.Strings["d"].Comment, "B")
+
+	// Keys can swap names.
+	test.AssertNoError(t, x.RenameKeys(map[string]string{"b": "c", "c": "b"}))
+	test.AssertEqual(t, x.Strings["b"].Comment, "C")
+	test.AssertEqual(t, x.Strings["c"].Comment, "A")
+
+	err := x.RenameKeys(map[string]string{"b": "c", "d": "x", "e": "x", "missing": "y"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{
+		"key 'b' can't be renamed to 'c', which already exists",
+		"keys 'd' and 'e' would both be renamed to 'x'",
+		"key 'missing' does not exist",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error, got:\n%v", want, err)
+		}
+	}
+	// Nothing was renamed.
+	test.AssertSliceEqual(t, sortedKeys(), []string{"b", "c", "d", "e"})
+}
+
 func TestXCStrings_SetTranslation_PreservesExistingLocalization(t *testing.T) {
 	xcstrings := &XCStrings{
 		SourceLanguage: "en",